@@ -44,7 +44,7 @@ func WithApiKeys(id, secret string) Option {
 
 		c.log.Debug("setting up auth",
 			zap.String("key", id),
-			zap.String("secret", secret),
+			zap.String("secret", redactedValue),
 		)
 		c.auth.keyID = id
 		c.auth.secret = secret
@@ -68,7 +68,7 @@ func WithTokenFromKeys(id, secret string) Option {
 // WithToken sets the token used to authenticate the API requests
 func WithToken(token string) Option {
 	return clientFunc(func(c *Client) error {
-		c.log.Debug("setting up auth", zap.String("token", token))
+		c.log.Debug("setting up auth", zap.String("token", redactedValue))
 		c.auth.token = token
 		return nil
 	})
@@ -103,7 +103,7 @@ func (c *Client) GenerateToken() (response TokenResponse, err error) {
 
 	if len(response.Data) > 0 {
 		// @afiune how do we handle cases where there is more than one token
-		c.log.Debug("storing token", zap.Reflect("data", response.Data))
+		c.log.Debug("storing token", zap.String("expires_at", response.Data[0].ExpiresAt))
 		c.auth.token = response.Data[0].Token
 		return
 	}
@@ -116,7 +116,7 @@ func (c *Client) GenerateToken() (response TokenResponse, err error) {
 func (c *Client) GenerateTokenWithKeys(keyID, secretKey string) (TokenResponse, error) {
 	c.log.Debug("setting up auth",
 		zap.String("key", keyID),
-		zap.String("secret", secretKey),
+		zap.String("secret", redactedValue),
 	)
 	c.auth.keyID = keyID
 	c.auth.secret = secretKey
@@ -30,18 +30,24 @@ import (
 	"go.uber.org/zap"
 )
 
-const defaultTimeout = 60 * time.Second
+// DefaultTimeout is the timeout of the HTTP requests of a client, use
+// WithTimeout to change it
+const DefaultTimeout = 60 * time.Second
 
 type Client struct {
 	id         string
 	account    string
 	apiVersion string
 	logLevel   string
+	retries    int
 	baseURL    *url.URL
 	auth       *authConfig
 	c          *http.Client
+	customHTTP bool
+	pool       *WorkerPool
 	log        *zap.Logger
 	headers    map[string]string
+	wrappers   []func(http.RoundTripper) http.RoundTripper
 
 	LQL             *LQLService
 	Events          *EventsService
@@ -89,7 +95,8 @@ func NewClient(account string, opts ...Option) (*Client, error) {
 		auth: &authConfig{
 			expiration: DefaultTokenExpiryTime,
 		},
-		c: &http.Client{Timeout: defaultTimeout},
+		c:    &http.Client{Timeout: DefaultTimeout},
+		pool: NewWorkerPool(DefaultConcurrency),
 	}
 	c.LQL = &LQLService{c}
 	c.Events = &EventsService{c}
@@ -105,17 +112,21 @@ func NewClient(account string, opts ...Option) (*Client, error) {
 			return c, err
 		}
 	}
+	c.reuseConnections()
+	c.wrapTransport()
 
 	c.log.Info("api client created",
 		zap.String("url", c.baseURL.String()),
 		zap.String("version", c.apiVersion),
 		zap.String("log_level", c.logLevel),
 		zap.Int("timeout", c.auth.expiration),
+		zap.Int("concurrency", c.pool.Size()),
 	)
 	return c, nil
 }
 
-// WithURL sets the base URL, this options is only available for test purposes
+// WithURL sets the base URL, useful for isolated regions or test environments
+// where the API does not live at https://<account>.lacework.net
 func WithURL(baseURL string) Option {
 	return clientFunc(func(c *Client) error {
 		u, err := url.Parse(baseURL)
@@ -140,6 +151,104 @@ func WithHeader(header, value string) Option {
 	})
 }
 
+// WithUserAgent overrides the User-Agent header sent to every request,
+// by default "Go Client/<version>"
+func WithUserAgent(userAgent string) Option {
+	return clientFunc(func(c *Client) error {
+		if userAgent != "" {
+			c.log.Debug("setting up client", zap.String("user_agent", userAgent))
+			c.headers["User-Agent"] = userAgent
+		}
+		return nil
+	})
+}
+
+// WithSubaccount sets the subaccount (tenant) of an organizational account
+// to interact with, it is sent to every request via the Account-Name header
+func WithSubaccount(subaccount string) Option {
+	return clientFunc(func(c *Client) error {
+		if subaccount != "" {
+			c.log.Debug("setting up client", zap.String("subaccount", subaccount))
+			c.headers["Account-Name"] = subaccount
+		}
+		return nil
+	})
+}
+
+// WithTimeout configures the timeout of the HTTP requests, by default 60 seconds
+func WithTimeout(timeout time.Duration) Option {
+	return clientFunc(func(c *Client) error {
+		if timeout <= 0 {
+			return errors.New("timeout must be greater than zero")
+		}
+
+		if c.customHTTP {
+			c.log.Debug("custom http client configured, ignoring timeout",
+				zap.Duration("timeout", timeout))
+			return nil
+		}
+
+		c.log.Debug("setting up client", zap.Duration("timeout", timeout))
+		c.c.Timeout = timeout
+		return nil
+	})
+}
+
+// WithHTTPClient configures the HTTP client used to send every request, useful
+// to provide a custom transport like a proxy or a TLS configuration with a
+// custom CA bundle
+//
+// NOTE: When set, the timeout of the provided client is used and WithTimeout
+// is ignored, regardless of the order of the options
+func WithHTTPClient(client *http.Client) Option {
+	return clientFunc(func(c *Client) error {
+		if client == nil {
+			return errors.New("http client cannot be nil")
+		}
+
+		c.log.Debug("setting up client", zap.Duration("http_client_timeout", client.Timeout))
+		c.c = client
+		c.customHTTP = true
+		return nil
+	})
+}
+
+// WithTransportWrapper wraps the transport of the client with the provided
+// function, useful to inspect the requests and responses of the client
+//
+// The transport is wrapped after every other option is applied, so that it
+// keeps the TLS settings of WithCACertFile and WithInsecureSkipVerify
+func WithTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return clientFunc(func(c *Client) error {
+		if wrap == nil {
+			return errors.New("transport wrapper cannot be nil")
+		}
+
+		c.log.Debug("setting up client", zap.Bool("transport_wrapper", true))
+		c.wrappers = append(c.wrappers, wrap)
+		return nil
+	})
+}
+
+// wrapTransport wraps the transport of the client with the configured
+// wrappers, a copy of the HTTP client is used to not modify a custom one
+func (c *Client) wrapTransport() {
+	if len(c.wrappers) == 0 {
+		return
+	}
+
+	client := *c.c
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for _, wrap := range c.wrappers {
+		transport = wrap(transport)
+	}
+	client.Transport = transport
+	c.c = &client
+}
+
 // URL returns the base url configured
 func (c *Client) URL() string {
 	return c.baseURL.String()
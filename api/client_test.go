@@ -19,7 +19,10 @@
 package api_test
 
 import (
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -61,3 +64,115 @@ func TestNewClientWithOptions(t *testing.T) {
 		assert.Equal(t, "v2", c.ApiVersion(), "modified API version should be v2")
 	}
 }
+
+func TestNewClientWithSubaccount(t *testing.T) {
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI("external/integrations", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "tenant-a", r.Header.Get("Account-Name"),
+			"the subaccount header is missing")
+		fmt.Fprintf(w, `{"ok": true, "data": []}`)
+	})
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+		api.WithSubaccount("tenant-a"),
+	)
+	if assert.Nil(t, err) {
+		_, err = c.Integrations.List()
+		assert.Nil(t, err)
+	}
+}
+
+func TestNewClientWithUserAgent(t *testing.T) {
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI("external/integrations", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "lacework-cli/1.0.0", r.Header.Get("User-Agent"))
+		fmt.Fprintf(w, `{"ok": true, "data": []}`)
+	})
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+		api.WithUserAgent("lacework-cli/1.0.0"),
+	)
+	if assert.Nil(t, err) {
+		_, err = c.Integrations.List()
+		assert.Nil(t, err)
+	}
+}
+
+func TestNewClientWithTimeout(t *testing.T) {
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI("external/integrations", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprintf(w, `{"ok": true, "data": []}`)
+	})
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+		api.WithTimeout(10*time.Millisecond),
+	)
+	if assert.Nil(t, err) {
+		_, err = c.Integrations.List()
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), "Client.Timeout exceeded")
+		}
+	}
+
+	_, err = api.NewClient("test", api.WithTimeout(0))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "timeout must be greater than zero", err.Error())
+	}
+}
+
+func TestNewClientWithHTTPClient(t *testing.T) {
+	var (
+		transportCalls = 0
+		fakeServer     = lacework.MockServer()
+	)
+	fakeServer.MockAPI("external/integrations", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprintf(w, `{"ok": true, "data": []}`)
+	})
+	defer fakeServer.Close()
+
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			transportCalls++
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+
+	// the custom client has no timeout, WithTimeout must be ignored
+	// regardless of the order of the options
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+		api.WithTimeout(10*time.Millisecond),
+		api.WithHTTPClient(httpClient),
+		api.WithTimeout(10*time.Millisecond),
+	)
+	if assert.Nil(t, err) {
+		_, err = c.Integrations.List()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, transportCalls, "the custom http client should be used")
+		assert.Equal(t, time.Duration(0), httpClient.Timeout,
+			"the custom http client should not be modified")
+	}
+
+	_, err = api.NewClient("test", api.WithHTTPClient(nil))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "http client cannot be nil", err.Error())
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
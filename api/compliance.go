@@ -85,20 +85,7 @@ type ComplianceRecommendation struct {
 }
 
 func (r *ComplianceRecommendation) SeverityString() string {
-	switch r.Severity {
-	case 1:
-		return "Critical"
-	case 2:
-		return "High"
-	case 3:
-		return "Medium"
-	case 4:
-		return "Low"
-	case 5:
-		return "Info"
-	default:
-		return "Unknown"
-	}
+	return Severity(r.Severity).String()
 }
 
 type ComplianceViolation struct {
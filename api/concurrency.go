@@ -0,0 +1,149 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// DefaultConcurrency is the number of requests that bulk operations send at
+// once, unless the client was configured with WithConcurrency()
+const DefaultConcurrency = 4
+
+// WorkerPool bounds the number of operations that run at once, every bulk
+// operation of a client, like scanning many package manifests, funnels
+// through the same pool, so that running many of them in parallel does
+// not trip the rate limits of the API or exhaust the sockets of the host
+type WorkerPool struct {
+	slots chan struct{}
+}
+
+// NewWorkerPool creates a pool that runs up to the provided number of
+// operations at once, a size lower than one runs them one at a time
+func NewWorkerPool(size int) *WorkerPool {
+	if size < 1 {
+		size = 1
+	}
+	return &WorkerPool{slots: make(chan struct{}, size)}
+}
+
+// Size returns the number of operations that the pool runs at once
+func (p *WorkerPool) Size() int {
+	return cap(p.slots)
+}
+
+// Run calls fn for every index from 0 to total-1, running at most Size()
+// of them at once, and returns the error of every index, in order. When
+// the context is done, the indexes that did not start are not run and
+// their error is the one of the context
+//
+// NOTE: The slots of the pool are shared, fn must not call Run on the
+// same pool or it could wait forever for a slot
+func (p *WorkerPool) Run(ctx context.Context,
+	total int, fn func(ctx context.Context, i int) error,
+) []error {
+	var (
+		errs = make([]error, total)
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < total; i++ {
+		select {
+		case <-ctx.Done():
+			for ; i < total; i++ {
+				errs[i] = ctx.Err()
+			}
+		case p.slots <- struct{}{}:
+			wg.Add(1)
+			go func(i int) {
+				defer func() {
+					<-p.slots
+					wg.Done()
+				}()
+				errs[i] = fn(ctx, i)
+			}(i)
+		}
+	}
+	wg.Wait()
+	return errs
+}
+
+// WithConcurrency configures the number of requests that bulk operations
+// send at once, by default 4 (DefaultConcurrency). The transport of the
+// client keeps the same number of idle connections, so that they are
+// reused across requests instead of opening a new one every time
+func WithConcurrency(concurrency int) Option {
+	return clientFunc(func(c *Client) error {
+		if concurrency < 1 {
+			return errors.New("concurrency must be greater than zero")
+		}
+
+		c.log.Debug("setting up client", zap.Int("concurrency", concurrency))
+		c.pool = NewWorkerPool(concurrency)
+		return nil
+	})
+}
+
+// WorkerPool returns the pool that bounds the bulk operations of the client
+func (c *Client) WorkerPool() *WorkerPool {
+	return c.pool
+}
+
+// Bulk runs a bulk operation through the worker pool of the client, it
+// calls fn for every index from 0 to total-1 and returns their errors,
+// in order, see WorkerPool.Run()
+//
+// Example of scanning many package manifests
+//
+//   errs := lacework.Bulk(ctx, len(manifests), func(ctx context.Context, i int) (err error) {
+//       responses[i], err = lacework.Vulnerabilities.Host.Scan(manifests[i])
+//       return
+//   })
+func (c *Client) Bulk(ctx context.Context,
+	total int, fn func(ctx context.Context, i int) error,
+) []error {
+	return c.pool.Run(ctx, total, fn)
+}
+
+// reuseConnections keeps as many idle connections to the API as requests
+// sent at once by bulk operations, the default transport keeps only two, so
+// the rest of the connections would be closed after every request
+func (c *Client) reuseConnections() {
+	if c.customHTTP {
+		c.log.Debug("custom http client configured, ignoring idle connections")
+		return
+	}
+	c.transport().MaxIdleConnsPerHost = c.pool.Size()
+}
+
+// transport returns the transport of the client, it is cloned from the
+// default one the first time, so that its settings do not leak into
+// other HTTP clients
+func (c *Client) transport() *http.Transport {
+	transport, ok := c.c.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		c.c.Transport = transport
+	}
+	return transport
+}
@@ -0,0 +1,113 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+func TestWorkerPoolRun(t *testing.T) {
+	var (
+		pool     = api.NewWorkerPool(2)
+		mu       sync.Mutex
+		running  int
+		maxFound int
+	)
+	assert.Equal(t, 2, pool.Size())
+
+	errs := pool.Run(context.Background(), 6, func(_ context.Context, i int) error {
+		mu.Lock()
+		running++
+		if running > maxFound {
+			maxFound = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if i == 3 {
+			return errors.New("rate limit exceeded")
+		}
+		return nil
+	})
+
+	assert.Equal(t, 2, maxFound, "the pool should run two operations at once")
+	if assert.Len(t, errs, 6) {
+		assert.Nil(t, errs[0])
+		assert.EqualError(t, errs[3], "rate limit exceeded")
+		assert.Nil(t, errs[5])
+	}
+}
+
+func TestWorkerPoolRunCanceled(t *testing.T) {
+	var (
+		pool        = api.NewWorkerPool(1)
+		ctx, cancel = context.WithCancel(context.Background())
+		ran         = 0
+	)
+	defer cancel()
+
+	errs := pool.Run(ctx, 3, func(_ context.Context, i int) error {
+		ran++
+		cancel()
+		return nil
+	})
+
+	assert.Equal(t, 1, ran, "only the first operation should run")
+	if assert.Len(t, errs, 3) {
+		assert.Nil(t, errs[0])
+		assert.Equal(t, context.Canceled, errs[1])
+		assert.Equal(t, context.Canceled, errs[2])
+	}
+}
+
+func TestNewWorkerPoolMinimumSize(t *testing.T) {
+	assert.Equal(t, 1, api.NewWorkerPool(0).Size())
+	assert.Equal(t, 1, api.NewWorkerPool(-3).Size())
+}
+
+func TestClientWithConcurrency(t *testing.T) {
+	c, err := api.NewClient("test")
+	if assert.Nil(t, err) {
+		assert.Equal(t, api.DefaultConcurrency, c.WorkerPool().Size())
+	}
+
+	c, err = api.NewClient("test", api.WithConcurrency(8))
+	if assert.Nil(t, err) {
+		assert.Equal(t, 8, c.WorkerPool().Size())
+		errs := c.Bulk(context.Background(), 3, func(context.Context, int) error { return nil })
+		assert.Equal(t, []error{nil, nil, nil}, errs)
+	}
+
+	_, err = api.NewClient("test", api.WithConcurrency(0))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "concurrency must be greater than zero", err.Error())
+	}
+}
@@ -23,12 +23,26 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
-// erorResponse handles errors caused by a Lacework API request
-type errorResponse struct {
-	Response *http.Response
-	Message  string
+// Error is the error returned when a Lacework API request fails, it carries
+// the HTTP status code, the body and the request id of the response
+//
+// Use errors.As() to inspect an API error that has been wrapped
+//
+//   var apiErr *api.Error
+//   if errors.As(err, &apiErr) {
+//       fmt.Println(apiErr.StatusCode, apiErr.RequestID)
+//   }
+type Error struct {
+	StatusCode int
+	Body       string
+	Message    string
+	RequestID  string
+	Response   *http.Response
 }
 
 type apiErrorResponse struct {
@@ -47,14 +61,86 @@ func (r *apiErrorResponse) Message() string {
 	return ""
 }
 
-// Error fulfills the built-in error interface function
-func (r *errorResponse) Error() string {
-	return fmt.Sprintf("[%v] %v: %d %s",
-		r.Response.Request.Method,
-		r.Response.Request.URL,
-		r.Response.StatusCode,
-		r.Message,
-	)
+// Error fulfills the built-in error interface function, the method and URL
+// of the request are only included when the error has the response
+func (e *Error) Error() string {
+	msg := e.Message
+	if e.StatusCode != 0 {
+		msg = fmt.Sprintf("%d %s", e.StatusCode, e.Message)
+	}
+	if e.Response != nil && e.Response.Request != nil {
+		msg = fmt.Sprintf("[%v] %v: %s",
+			e.Response.Request.Method,
+			e.Response.Request.URL,
+			msg,
+		)
+	}
+	if e.RequestID != "" {
+		msg = fmt.Sprintf("%s (request id: %s)", msg, e.RequestID)
+	}
+	return msg
+}
+
+// RequestID returns the unique identifier of the failed request that caused
+// the provided error, or an empty string if the error does not come from an
+// API response or the response did not contain a request id
+func RequestID(err error) string {
+	if apiErr, ok := asError(err); ok {
+		return apiErr.RequestID
+	}
+	return ""
+}
+
+// IsNotFound returns true if the provided error was caused by an API
+// response with the status code 404 Not Found
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsRateLimited returns true if the provided error was caused by an API
+// response with the status code 429 Too Many Requests
+func IsRateLimited(err error) bool {
+	return hasStatusCode(err, http.StatusTooManyRequests)
+}
+
+// IsAuthError returns true if the provided error was caused by an API
+// response with the status code 401 Unauthorized or 403 Forbidden
+func IsAuthError(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized) ||
+		hasStatusCode(err, http.StatusForbidden)
+}
+
+// IsFeatureDisabled returns true if the provided error was caused by an API
+// response reporting that the requested feature is not enabled on the account,
+// like the host vulnerability feature
+//
+// Authentication errors are never reported as a disabled feature, even if
+// their message mentions it, the credentials have to be fixed first
+func IsFeatureDisabled(err error) bool {
+	apiErr, ok := asError(err)
+	return ok && !IsAuthError(err) && isFeatureDisabledMessage(apiErr.Message)
+}
+
+// isFeatureDisabledMessage returns true if the provided message of an API
+// response reports that a feature is not enabled on the account
+func isFeatureDisabledMessage(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "not enabled") ||
+		strings.Contains(message, "disabled") ||
+		strings.Contains(message, "not subscribed")
+}
+
+func hasStatusCode(err error, code int) bool {
+	apiErr, ok := asError(err)
+	return ok && apiErr.StatusCode == code
+}
+
+func asError(err error) (*Error, bool) {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
 }
 
 // checkResponse checks the provided response and generates an Error
@@ -64,18 +150,24 @@ func checkErrorInResponse(r *http.Response) error {
 	}
 
 	var (
-		errRes    = &errorResponse{Response: r}
+		apiErr = &Error{
+			StatusCode: r.StatusCode,
+			RequestID:  r.Header.Get(RequestIDHeader),
+			Response:   r,
+		}
 		data, err = ioutil.ReadAll(r.Body)
 	)
 	if err == nil && len(data) > 0 {
+		apiErr.Body = string(data)
+
 		// try to unmarshal the api error response
 		apiErrRes := &apiErrorResponse{}
 		if err := json.Unmarshal(data, apiErrRes); err == nil {
-			errRes.Message = apiErrRes.Message()
+			apiErr.Message = apiErrRes.Message()
 		} else {
-			errRes.Message = string(data)
+			apiErr.Message = apiErr.Body
 		}
 	}
 
-	return errRes
+	return apiErr
 }
@@ -0,0 +1,152 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/internal/lacework"
+)
+
+func TestErrorWithRequestID(t *testing.T) {
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI("external/integrations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(api.RequestIDHeader, "REQ_123")
+		http.Error(w, `{"ok": false, "data": {"message": "boom"}}`, http.StatusInternalServerError)
+	})
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+	)
+	assert.Nil(t, err)
+
+	_, err = c.Integrations.List()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "500 boom (request id: REQ_123)")
+		assert.Equal(t, "REQ_123", api.RequestID(err))
+		assert.Equal(t, "REQ_123", api.RequestID(errors.Wrap(err, "unable to list")),
+			"the request id should be found on wrapped errors")
+	}
+
+	assert.Empty(t, api.RequestID(fmt.Errorf("not an api error")))
+}
+
+func TestErrorWithoutResponse(t *testing.T) {
+	assert.Equal(t, "boom", (&api.Error{Message: "boom"}).Error())
+	assert.Equal(t, "500 boom (request id: REQ_123)",
+		(&api.Error{StatusCode: 500, Message: "boom", RequestID: "REQ_123"}).Error())
+	assert.Equal(t, "404 not found",
+		(&api.Error{StatusCode: 404, Message: "not found", Response: &http.Response{}}).Error(),
+		"a response without request should not panic")
+}
+
+func TestErrorTypes(t *testing.T) {
+	cases := []struct {
+		statusCode    int
+		isNotFound    bool
+		isRateLimited bool
+		isAuthError   bool
+	}{
+		{http.StatusNotFound, true, false, false},
+		{http.StatusTooManyRequests, false, true, false},
+		{http.StatusUnauthorized, false, false, true},
+		{http.StatusForbidden, false, false, true},
+		{http.StatusBadRequest, false, false, false},
+	}
+
+	for _, kase := range cases {
+		t.Run(http.StatusText(kase.statusCode), func(t *testing.T) {
+			fakeServer := lacework.MockServer()
+			fakeServer.MockAPI("external/integrations", func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "raw error body", kase.statusCode)
+			})
+			defer fakeServer.Close()
+
+			c, err := api.NewClient("test",
+				api.WithURL(fakeServer.URL()),
+				api.WithToken("TOKEN"),
+			)
+			assert.Nil(t, err)
+
+			_, err = c.Integrations.List()
+			err = errors.Wrap(err, "unable to list integrations")
+
+			var apiErr *api.Error
+			if assert.True(t, errors.As(err, &apiErr)) {
+				assert.Equal(t, kase.statusCode, apiErr.StatusCode)
+				assert.Equal(t, "raw error body\n", apiErr.Body)
+				assert.Equal(t, "raw error body\n", apiErr.Message)
+			}
+			assert.Equal(t, kase.isNotFound, api.IsNotFound(err))
+			assert.Equal(t, kase.isRateLimited, api.IsRateLimited(err))
+			assert.Equal(t, kase.isAuthError, api.IsAuthError(err))
+		})
+	}
+
+	assert.False(t, api.IsNotFound(nil))
+	assert.False(t, api.IsAuthError(fmt.Errorf("not an api error")))
+}
+
+func TestIsFeatureDisabled(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   int
+		message  string
+		expected bool
+	}{
+		{"bad request", http.StatusBadRequest, "Host vulnerability is not enabled for this account", true},
+		{"not subscribed", http.StatusBadRequest, "The account is not subscribed to this feature", true},
+		{"other error", http.StatusBadRequest, "Invalid request", false},
+		{"forbidden", http.StatusForbidden, "Host vulnerability is not enabled for this account", false},
+		{"unauthorized", http.StatusUnauthorized, "The API key is disabled", false},
+	}
+
+	for _, kase := range cases {
+		t.Run(kase.name, func(t *testing.T) {
+			fakeServer := lacework.MockServer()
+			fakeServer.MockAPI("external/vulnerabilities/host", func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w,
+					fmt.Sprintf(`{"ok": false, "data": {"message": "%s"}}`, kase.message),
+					kase.status,
+				)
+			})
+			defer fakeServer.Close()
+
+			c, err := api.NewClient("test",
+				api.WithURL(fakeServer.URL()),
+				api.WithToken("TOKEN"),
+			)
+			assert.Nil(t, err)
+
+			_, err = c.Vulnerabilities.Host.ListCves()
+			assert.Equal(t, kase.expected, api.IsFeatureDisabled(errors.Wrap(err, "unable to list CVEs")))
+		})
+	}
+
+	assert.False(t, api.IsFeatureDisabled(nil))
+	assert.False(t, api.IsFeatureDisabled(fmt.Errorf("feature disabled, but not an api error")))
+}
@@ -19,10 +19,12 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 )
 
 // EventsService is a service that interacts with the Events endpoints
@@ -36,12 +38,17 @@ var ValidEventSeverities = []string{"critical", "high", "medium", "low", "info"}
 
 // List leverages ListDateRange and returns a list of events from the last 7 days
 func (svc *EventsService) List() (EventsResponse, error) {
+	return svc.ListWithContext(context.Background())
+}
+
+// ListWithContext is like List but with the provided context
+func (svc *EventsService) ListWithContext(ctx context.Context) (EventsResponse, error) {
 	var (
 		now  = time.Now().UTC()
 		from = now.AddDate(0, 0, -7) // 7 days from now
 	)
 
-	return svc.ListDateRange(from, now)
+	return svc.ListDateRangeWithContext(ctx, from, now)
 }
 
 // TODO @afiune (to-be-deprecated) https://github.com/lacework/go-sdk/issues/161
@@ -58,7 +65,12 @@ func (svc *EventsService) ListRange(start, end time.Time) (EventsResponse, error
 // * The difference between the START_TIME and END_TIME must not be greater than 7 days
 // * The START_TIME must be less than or equal to three months from current date
 // * The number of records produced is limited to 5000
-func (svc *EventsService) ListDateRange(start, end time.Time) (
+func (svc *EventsService) ListDateRange(start, end time.Time) (EventsResponse, error) {
+	return svc.ListDateRangeWithContext(context.Background(), start, end)
+}
+
+// ListDateRangeWithContext is like ListDateRange but with the provided context
+func (svc *EventsService) ListDateRangeWithContext(ctx context.Context, start, end time.Time) (
 	response EventsResponse,
 	err error,
 ) {
@@ -73,19 +85,136 @@ func (svc *EventsService) ListDateRange(start, end time.Time) (
 		start.UTC().Format(time.RFC3339),
 		end.UTC().Format(time.RFC3339),
 	)
-	err = svc.client.RequestDecoder("GET", apiPath, nil, &response)
+	err = svc.client.RequestDecoderWithContext(ctx, "GET", apiPath, nil, &response)
 	return
 }
 
+// ListByType leverages ListDateRangeByType and returns a list of events from the
+// last 7 days that match any of the provided event types, like "NewUser"
+//
+// The events endpoint does not support filtering by event type, the events are
+// filtered on the client side with FilterEventsByType (case insensitive), that
+// means that the limit of 5000 records applies before the filter, when no types
+// are provided, all the events are returned
+func (svc *EventsService) ListByType(types ...string) (EventsResponse, error) {
+	return svc.ListByTypeWithContext(context.Background(), types...)
+}
+
+// ListByTypeWithContext is like ListByType but with the provided context
+func (svc *EventsService) ListByTypeWithContext(ctx context.Context, types ...string) (
+	EventsResponse, error,
+) {
+	var (
+		now  = time.Now().UTC()
+		from = now.AddDate(0, 0, -7) // 7 days from now
+	)
+
+	return svc.ListDateRangeByTypeWithContext(ctx, from, now, types...)
+}
+
+// ListDateRangeByType is like ListByType but returns the events during the
+// specified date range, see ListDateRange for the requirements of the range
+func (svc *EventsService) ListDateRangeByType(start, end time.Time, types ...string) (
+	EventsResponse, error,
+) {
+	return svc.ListDateRangeByTypeWithContext(context.Background(), start, end, types...)
+}
+
+// ListDateRangeByTypeWithContext is like ListDateRangeByType but with the provided context
+func (svc *EventsService) ListDateRangeByTypeWithContext(ctx context.Context,
+	start, end time.Time, types ...string,
+) (response EventsResponse, err error) {
+	response, err = svc.ListDateRangeWithContext(ctx, start, end)
+	if err != nil || len(types) == 0 {
+		return
+	}
+
+	svc.client.log.Debug("filtering events by type", zap.Strings("types", types))
+	response.Events = FilterEventsByType(response.Events, types...)
+	return
+}
+
+// eventsPageWindow is the time window of every page of events
+const eventsPageWindow = 24 * time.Hour
+
+// ListPages leverages ListDateRangePages to iterate over the events from the
+// last 7 days, one page at a time, the iteration stops when the provided
+// function returns false
+//
+// Example of streaming events without loading all of them into memory
+//
+//   err := lacework.Events.ListPages(func(page api.EventsResponse) bool {
+//       for _, event := range page.Events {
+//           fmt.Println(event.EventID)
+//       }
+//       return true // continue with the next page
+//   })
+func (svc *EventsService) ListPages(fn func(EventsResponse) bool) error {
+	var (
+		now  = time.Now().UTC()
+		from = now.AddDate(0, 0, -7) // 7 days from now
+	)
+
+	return svc.ListDateRangePages(from, now, fn)
+}
+
+// ListDateRangePages is like ListDateRange but, instead of returning all the
+// events at once, it invokes the provided function for every page of events,
+// the iteration stops when the provided function returns false
+//
+// The events endpoint does not paginate its results nor provides a total
+// count, so every page contains the events of a one day window, from the
+// oldest to the newest, which keeps the memory usage of consumers bounded
+func (svc *EventsService) ListDateRangePages(start, end time.Time,
+	fn func(EventsResponse) bool,
+) error {
+	return svc.ListDateRangePagesWithContext(context.Background(), start, end, fn)
+}
+
+// ListDateRangePagesWithContext is like ListDateRangePages but with the provided context
+func (svc *EventsService) ListDateRangePagesWithContext(ctx context.Context,
+	start, end time.Time, fn func(EventsResponse) bool,
+) error {
+	if start.After(end) {
+		return errors.New("data range should have a start time before the end time")
+	}
+
+	for pageStart := start; pageStart.Before(end); pageStart = pageStart.Add(eventsPageWindow) {
+		pageEnd := pageStart.Add(eventsPageWindow)
+		if pageEnd.After(end) {
+			pageEnd = end
+		}
+
+		page, err := svc.ListDateRangeWithContext(ctx, pageStart, pageEnd)
+		if err != nil {
+			return err
+		}
+
+		if !fn(page) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
 // Details returns details about the specified event_id
-func (svc *EventsService) Details(eventID string) (response EventDetailsResponse, err error) {
+func (svc *EventsService) Details(eventID string) (EventDetailsResponse, error) {
+	return svc.DetailsWithContext(context.Background(), eventID)
+}
+
+// DetailsWithContext is like Details but with the provided context
+func (svc *EventsService) DetailsWithContext(ctx context.Context, eventID string) (
+	response EventDetailsResponse,
+	err error,
+) {
 	if eventID == "" {
 		err = errors.New("event_id cannot be empty")
 		return
 	}
 
 	apiPath := fmt.Sprintf("%s?EVENT_ID=%s", apiEventsDetails, eventID)
-	err = svc.client.RequestDecoder("GET", apiPath, nil, &response)
+	err = svc.client.RequestDecoderWithContext(ctx, "GET", apiPath, nil, &response)
 	return
 }
 
@@ -273,20 +402,7 @@ type Event struct {
 }
 
 func (e *Event) SeverityString() string {
-	switch e.Severity {
-	case "1":
-		return "Critical"
-	case "2":
-		return "High"
-	case "3":
-		return "Medium"
-	case "4":
-		return "Low"
-	case "5":
-		return "Info"
-	default:
-		return "Unknown"
-	}
+	return ParseSeverity(e.Severity).String()
 }
 
 type EventsCount struct {
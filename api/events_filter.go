@@ -0,0 +1,69 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api
+
+import (
+	"strings"
+	"time"
+)
+
+// FilterEventsBySeverity returns the events with a severity equal or higher
+// than the provided threshold, for example, a threshold of SeverityHigh
+// returns both, critical and high events
+func FilterEventsBySeverity(events []Event, threshold Severity) []Event {
+	filtered := []Event{}
+	for _, event := range events {
+		if ParseSeverity(event.Severity).Order() <= threshold.Order() {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// FilterEventsByType returns the events that match any of the provided
+// event types, the comparison is case insensitive
+func FilterEventsByType(events []Event, types ...string) []Event {
+	filtered := []Event{}
+	for _, event := range events {
+		for _, eventType := range types {
+			if strings.EqualFold(event.EventType, eventType) {
+				filtered = append(filtered, event)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// FilterEventsByTime returns the events that occurred, at least partially,
+// within the provided time range, a zero start or end time leaves that side
+// of the range open
+func FilterEventsByTime(events []Event, start, end time.Time) []Event {
+	filtered := []Event{}
+	for _, event := range events {
+		if !start.IsZero() && event.EndTime.Before(start) {
+			continue
+		}
+		if !end.IsZero() && event.StartTime.After(end) {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered
+}
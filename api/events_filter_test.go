@@ -0,0 +1,81 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+var filterEventsMock = []api.Event{
+	{EventID: "1", EventType: "NewUser", Severity: "1",
+		StartTime: time.Date(2020, 8, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2020, 8, 1, 11, 0, 0, 0, time.UTC)},
+	{EventID: "2", EventType: "NewVPC", Severity: "3",
+		StartTime: time.Date(2020, 8, 2, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2020, 8, 2, 11, 0, 0, 0, time.UTC)},
+	{EventID: "3", EventType: "NewUser", Severity: "5",
+		StartTime: time.Date(2020, 8, 3, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2020, 8, 3, 11, 0, 0, 0, time.UTC)},
+}
+
+func filteredEventIDs(events []api.Event) []string {
+	ids := []string{}
+	for _, event := range events {
+		ids = append(ids, event.EventID)
+	}
+	return ids
+}
+
+func TestFilterEventsBySeverity(t *testing.T) {
+	assert.Equal(t, []string{"1"},
+		filteredEventIDs(api.FilterEventsBySeverity(filterEventsMock, api.SeverityHigh)))
+	assert.Equal(t, []string{"1", "2"},
+		filteredEventIDs(api.FilterEventsBySeverity(filterEventsMock, api.SeverityMedium)))
+	assert.Equal(t, []string{"1", "2", "3"},
+		filteredEventIDs(api.FilterEventsBySeverity(filterEventsMock, api.SeverityInfo)))
+	assert.Empty(t, api.FilterEventsBySeverity([]api.Event{}, api.SeverityInfo))
+}
+
+func TestFilterEventsByType(t *testing.T) {
+	assert.Equal(t, []string{"1", "3"},
+		filteredEventIDs(api.FilterEventsByType(filterEventsMock, "newuser")))
+	assert.Equal(t, []string{"1", "2", "3"},
+		filteredEventIDs(api.FilterEventsByType(filterEventsMock, "NewUser", "NewVPC")))
+	assert.Empty(t, api.FilterEventsByType(filterEventsMock))
+}
+
+func TestFilterEventsByTime(t *testing.T) {
+	assert.Equal(t, []string{"2", "3"},
+		filteredEventIDs(api.FilterEventsByTime(filterEventsMock,
+			time.Date(2020, 8, 2, 10, 30, 0, 0, time.UTC), time.Time{})))
+	assert.Equal(t, []string{"1", "2"},
+		filteredEventIDs(api.FilterEventsByTime(filterEventsMock,
+			time.Time{}, time.Date(2020, 8, 2, 10, 0, 0, 0, time.UTC))))
+	assert.Equal(t, []string{"2"},
+		filteredEventIDs(api.FilterEventsByTime(filterEventsMock,
+			time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC),
+			time.Date(2020, 8, 3, 9, 0, 0, 0, time.UTC))))
+	assert.Equal(t, []string{"1", "2", "3"},
+		filteredEventIDs(api.FilterEventsByTime(filterEventsMock, time.Time{}, time.Time{})))
+}
@@ -19,6 +19,7 @@
 package api_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -312,3 +313,148 @@ func eventDetailsResponse(id string) string {
 }
 `
 }
+
+func TestEventsListWithContextCanceled(t *testing.T) {
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI(
+		"external/events/GetEventsForDateRange",
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Fail(t, "the request should not be sent with a canceled context")
+		},
+	)
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithToken("TOKEN"),
+		api.WithURL(fakeServer.URL()),
+	)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = c.Events.ListWithContext(ctx)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "context canceled")
+	}
+
+	_, err = c.Events.DetailsWithContext(ctx, "123")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "context canceled")
+	}
+}
+
+func TestEventsDetailsWithContext(t *testing.T) {
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI(
+		"external/events/GetEventDetails",
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "123", r.URL.Query().Get("EVENT_ID"))
+			fmt.Fprintf(w, `{"data": [{"event_id": "123"}]}`)
+		},
+	)
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithToken("TOKEN"),
+		api.WithURL(fakeServer.URL()),
+	)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	response, err := c.Events.DetailsWithContext(ctx, "123")
+	if assert.Nil(t, err) && assert.Len(t, response.Events, 1) {
+		assert.Equal(t, "123", response.Events[0].EventID)
+	}
+}
+
+func TestEventsListDateRangePages(t *testing.T) {
+	var (
+		calls      = 0
+		fakeServer = lacework.MockServer()
+		end        = time.Date(2020, 6, 10, 12, 0, 0, 0, time.UTC)
+		start      = end.Add(-60 * time.Hour) // two and a half days
+	)
+	fakeServer.MockAPI(
+		"external/events/GetEventsForDateRange",
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			fmt.Fprintf(w, `{"data": [{"event_id": "%d", "start_time": "%s"}]}`,
+				calls, r.URL.Query().Get("START_TIME"),
+			)
+		},
+	)
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithToken("TOKEN"),
+		api.WithURL(fakeServer.URL()),
+	)
+	assert.Nil(t, err)
+
+	pages := []api.EventsResponse{}
+	err = c.Events.ListDateRangePages(start, end, func(page api.EventsResponse) bool {
+		pages = append(pages, page)
+		return true
+	})
+	if assert.Nil(t, err) && assert.Len(t, pages, 3, "every page should be a one day window") {
+		assert.Equal(t, start, pages[0].Events[0].StartTime)
+		assert.Equal(t, start.Add(24*time.Hour), pages[1].Events[0].StartTime)
+		assert.Equal(t, start.Add(48*time.Hour), pages[2].Events[0].StartTime)
+	}
+
+	// stop the iteration after the first page
+	calls = 0
+	err = c.Events.ListDateRangePages(start, end, func(page api.EventsResponse) bool {
+		return false
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, calls, "the iteration should stop when the function returns false")
+
+	err = c.Events.ListDateRangePages(end, start, func(page api.EventsResponse) bool {
+		return true
+	})
+	assert.NotNil(t, err)
+}
+
+func TestEventsListByType(t *testing.T) {
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI(
+		"external/events/GetEventsForDateRange",
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"data": [
+        {"event_id": "1", "event_type": "NewUser"},
+        {"event_id": "2", "event_type": "NewVPC"},
+        {"event_id": "3", "event_type": "CloudTrailDefaultAlert"}
+      ]}`)
+		},
+	)
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithToken("TOKEN"),
+		api.WithURL(fakeServer.URL()),
+	)
+	assert.Nil(t, err)
+
+	response, err := c.Events.ListByType("newuser", "NewVPC")
+	if assert.Nil(t, err) && assert.Len(t, response.Events, 2) {
+		assert.Equal(t, "1", response.Events[0].EventID)
+		assert.Equal(t, "2", response.Events[1].EventID)
+	}
+
+	// without types, all the events are returned
+	response, err = c.Events.ListByType()
+	assert.Nil(t, err)
+	assert.Len(t, response.Events, 3)
+
+	end := time.Now().UTC()
+	response, err = c.Events.ListDateRangeByType(end.Add(-time.Hour), end, "Unknown")
+	assert.Nil(t, err)
+	assert.Empty(t, response.Events)
+
+	_, err = c.Events.ListDateRangeByType(end, end.Add(-time.Hour), "NewUser")
+	assert.NotNil(t, err)
+}
@@ -20,6 +20,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -29,15 +30,27 @@ import (
 	"go.uber.org/zap"
 )
 
+// RequestIDHeader is the response header that contains the unique identifier
+// of a request, useful to correlate a failed request with the Lacework support
+const RequestIDHeader = "X-Request-Id"
+
 // NewRequest generates a new http request
 func (c *Client) NewRequest(method string, apiURL string, body io.Reader) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, apiURL, body)
+}
+
+// NewRequestWithContext generates a new http request with the provided context,
+// the context controls the entire lifetime of the request and its response
+func (c *Client) NewRequestWithContext(ctx context.Context,
+	method string, apiURL string, body io.Reader,
+) (*http.Request, error) {
 	apiPath, err := url.Parse(c.apiPath(apiURL))
 	if err != nil {
 		return nil, err
 	}
 
 	u := c.baseURL.ResolveReference(apiPath)
-	request, err := http.NewRequest(method, u.String(), body)
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +98,7 @@ func (c *Client) NewRequest(method string, apiURL string, body io.Reader) (*http
 		zap.String("method", request.Method),
 		zap.String("url", c.baseURL.String()),
 		zap.String("endpoint", apiPath.String()),
+		zap.String("full_url", request.URL.String()),
 		zap.Reflect("headers", c.httpHeadersSniffer(request.Header)),
 		zap.String("body", c.httpRequestBodySniffer(request)),
 	)
@@ -126,7 +140,14 @@ func (c *Client) DoDecoder(req *http.Request, v interface{}) (*http.Response, er
 // RequestDecoder performs an http request on an endpoint, and
 // decodes the response into the provided interface, all at once
 func (c *Client) RequestDecoder(method, path string, body io.Reader, v interface{}) error {
-	request, err := c.NewRequest(method, path, body)
+	return c.RequestDecoderWithContext(context.Background(), method, path, body, v)
+}
+
+// RequestDecoderWithContext is like RequestDecoder but with the provided context
+func (c *Client) RequestDecoderWithContext(ctx context.Context,
+	method, path string, body io.Reader, v interface{},
+) error {
+	request, err := c.NewRequestWithContext(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
@@ -150,13 +171,15 @@ func (c *Client) RequestEncoderDecoder(method, path string, data, v interface{})
 	return c.RequestDecoder(method, path, body, v)
 }
 
-// Do calls request.Do() directly
+// Do calls request.Do() directly, retrying transient errors if the
+// client was configured with retries, see WithRetries()
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
-	response, err := c.c.Do(req)
+	response, err := c.doWithRetries(req)
 	if err == nil {
 		c.log.Info("response",
 			zap.String("from_req_url", req.URL.String()),
 			zap.Int("code", response.StatusCode),
+			zap.String("request_id", response.Header.Get(RequestIDHeader)),
 			zap.String("proto", response.Proto),
 			zap.Reflect("headers", c.httpHeadersSniffer(response.Header)),
 			zap.String("body", c.httpResponseBodySniffer(response)),
@@ -166,17 +189,19 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 }
 
 // httpHeadersSniffer is only useful to avoid logging out the headers of a request
-// or response when the log level is set to INFO
-func (c *Client) httpHeadersSniffer(headers interface{}) interface{} {
+// or response when the log level is set to INFO, in DEBUG mode, the values of
+// the headers that contain secrets, like the access token, are redacted
+func (c *Client) httpHeadersSniffer(headers http.Header) interface{} {
 	if !c.debugMode() {
 		// prevents headers to be displayed if we are not in DEBUG mode
 		return "suppressed"
 	}
-	return headers
+	return redactHeaders(headers)
 }
 
 // httpRequestBodySniffer a request sniffer, it reads the body from the
-// provided request without closing it (use only for debugging purposes)
+// provided request without closing it (use only for debugging purposes),
+// the values of the JSON keys that contain secrets are redacted
 func (c *Client) httpRequestBodySniffer(r *http.Request) string {
 	if !c.debugMode() {
 		// prevents sniffing the request if we are not in DEBUG mode
@@ -191,11 +216,12 @@ func (c *Client) httpRequestBodySniffer(r *http.Request) string {
 	var stringBody string
 	r.Body, stringBody = sniffBody(r.Body)
 
-	return stringBody
+	return redactJSONBody(stringBody)
 }
 
 // httpResponseBodySniffer a response sniffer, it reads the body from the
-// provided response without closing it (use only for debugging purposes)
+// provided response without closing it (use only for debugging purposes),
+// the values of the JSON keys that contain secrets are redacted
 func (c *Client) httpResponseBodySniffer(r *http.Response) string {
 	if !c.debugMode() {
 		// prevents sniffing the response if we are not in DEBUG mode
@@ -210,7 +236,7 @@ func (c *Client) httpResponseBodySniffer(r *http.Response) string {
 	var stringBody string
 	r.Body, stringBody = sniffBody(r.Body)
 
-	return stringBody
+	return redactJSONBody(stringBody)
 }
 
 // a very simple body sniffer (use only for debugging purposes)
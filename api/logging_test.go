@@ -97,7 +97,7 @@ func TestClientWithLogLevelAndWriter(t *testing.T) {
 	//   "endpoint":"/api/v1/foo",
 	//   "headers":{
 	//     "Accept":"application/json",
-	//     "Authorization":"TOKEN",
+	//     "Authorization":"**REDACTED**",
 	//     "Method":"GET"
 	//   },
 	//   "body":""
@@ -115,7 +115,33 @@ func TestClientWithLogLevelAndWriter(t *testing.T) {
 	assert.Contains(t, logContent, "\"headers\"")
 	assert.Contains(t, logContent, "\"body\"")
 	assert.Contains(t, logContent, "\"Authorization\"")
-	assert.Contains(t, logContent, "TOKEN")
+	assert.Contains(t, logContent, "**REDACTED**")
+	assert.NotContains(t, logContent, "TOKEN")
+}
+
+func TestClientWithLogLevelDebugRedactsSecrets(t *testing.T) {
+	fakeServer := lacework.MockServer()
+	fakeServer.MockToken("SECRET_TOKEN")
+	defer fakeServer.Close()
+
+	logOutput := &bytes.Buffer{}
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithApiKeys("KEY", "SECRET_KEY"),
+		api.WithLogLevelAndWriter("DEBUG", logOutput),
+	)
+	if assert.Nil(t, err) {
+		_, err = c.GenerateToken()
+		assert.Nil(t, err)
+	}
+
+	logContent := logOutput.String()
+	assert.Contains(t, logContent, "\"full_url\"")
+	assert.Contains(t, logContent, "/api/v1/access/tokens")
+	assert.Contains(t, logContent, "\"X-Lw-Uaks\":[\"**REDACTED**\"]")
+	assert.Contains(t, logContent, "KEY")
+	assert.NotContains(t, logContent, "SECRET_KEY")
+	assert.NotContains(t, logContent, "SECRET_TOKEN")
 }
 
 func TestClientWithLogLevelAndFile(t *testing.T) {
@@ -0,0 +1,124 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// redactedValue replaces the sensitive values in the debug logs of the client
+const redactedValue = "**REDACTED**"
+
+// sensitiveHeaders are the headers of requests and responses that contain
+// secrets, their values are never logged
+var sensitiveHeaders = []string{
+	"Authorization",
+	"X-LW-UAKS",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// sensitiveJSONKeys are the fragments of the keys of JSON objects whose
+// values are secrets, like 'secret', 'api_secret' or 'token' (case-insensitive)
+var sensitiveJSONKeys = []string{
+	"secret",
+	"token",
+	"password",
+	"private_key",
+	"privatekey",
+}
+
+// redactHeaders returns a copy of the provided headers with the values
+// of the sensitive headers redacted
+func redactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	for _, header := range sensitiveHeaders {
+		if redacted.Get(header) != "" {
+			redacted.Set(header, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// redactJSONBody returns the provided body with the values of the sensitive
+// keys of every JSON object redacted, bodies that are not JSON, or that do
+// not contain sensitive keys, are returned as is
+func redactJSONBody(body string) string {
+	var (
+		data    interface{}
+		decoder = json.NewDecoder(strings.NewReader(body))
+	)
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return body
+	}
+
+	if !redactJSONValue(data) {
+		return body
+	}
+
+	redacted := &bytes.Buffer{}
+	encoder := json.NewEncoder(redacted)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return redactedValue
+	}
+	return strings.TrimSuffix(redacted.String(), "\n")
+}
+
+// redactJSONValue redacts the sensitive keys of the provided decoded JSON
+// value in place, it returns true if any value was redacted
+func redactJSONValue(value interface{}) bool {
+	redacted := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if isSensitiveJSONKey(key) {
+				if _, isString := nested.(string); isString {
+					v[key] = redactedValue
+					redacted = true
+					continue
+				}
+			}
+			if redactJSONValue(nested) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, nested := range v {
+			if redactJSONValue(nested) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+func isSensitiveJSONKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveJSONKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
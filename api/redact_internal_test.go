@@ -0,0 +1,69 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Authorization", "TOKEN")
+	headers.Set("X-LW-UAKS", "SECRET")
+	headers.Set("Content-Type", "application/json")
+
+	redacted := redactHeaders(headers)
+	assert.Equal(t, redactedValue, redacted.Get("Authorization"))
+	assert.Equal(t, redactedValue, redacted.Get("X-LW-UAKS"))
+	assert.Equal(t, "application/json", redacted.Get("Content-Type"))
+	assert.Empty(t, redacted.Get("Cookie"), "missing headers should not be added")
+
+	// the original headers must not be modified
+	assert.Equal(t, "TOKEN", headers.Get("Authorization"))
+	assert.Equal(t, "SECRET", headers.Get("X-LW-UAKS"))
+}
+
+func TestRedactJSONBody(t *testing.T) {
+	cases := []struct {
+		body     string
+		expected string
+	}{
+		{"", ""},
+		{"not json", "not json"},
+		{`{"keyId":"KEY","expiryTime":3600}`, `{"keyId":"KEY","expiryTime":3600}`},
+		{
+			`{"data":[{"token":"TOKEN","expiresAt":"2020"}],"ok":true}`,
+			`{"data":[{"expiresAt":"2020","token":"**REDACTED**"}],"ok":true}`,
+		},
+		{
+			`{"api_secret":"S","SecretKey":"S","password":"P","name":"foo"}`,
+			`{"SecretKey":"**REDACTED**","api_secret":"**REDACTED**","name":"foo","password":"**REDACTED**"}`,
+		},
+		{
+			`{"data":{"tokenCount":3}}`,
+			`{"data":{"tokenCount":3}}`,
+		},
+	}
+	for _, kase := range cases {
+		assert.Equal(t, kase.expected, redactJSONBody(kase.body))
+	}
+}
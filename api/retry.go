@@ -0,0 +1,181 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// retryBaseDelay is the initial delay of the exponential backoff
+	retryBaseDelay = 1 * time.Second
+
+	// retryMaxDelay is the maximum delay between retries, if the API asks
+	// us to wait longer (via Retry-After), we stop retrying the request
+	retryMaxDelay = 60 * time.Second
+)
+
+// retryableRequestKey is the context key used to mark non-idempotent
+// requests that are safe to retry when the API rejects them without
+// processing them, like a rate limited package manifest scan
+type retryableRequestKey struct{}
+
+// withRetryableRequest returns a context that marks the request as retryable
+func withRetryableRequest(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableRequestKey{}, true)
+}
+
+// WithRetries configures the number of times a request is retried on transient
+// errors, like rate limits (429) and server errors (5xx), using exponential
+// backoff and honoring the Retry-After header. Only idempotent requests are
+// retried, timeouts are not. By default, requests are not retried.
+func WithRetries(retries int) Option {
+	return clientFunc(func(c *Client) error {
+		if retries < 0 {
+			retries = 0
+		}
+
+		c.log.Debug("setting up client", zap.Int("retries", retries))
+		c.retries = retries
+		return nil
+	})
+}
+
+// isIdempotentRequest returns true if sending the request twice has the
+// same effect as sending it once
+func isIdempotentRequest(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// isRetryableRequest returns true if the request is idempotent, or if it
+// was explicitly marked as retryable
+func isRetryableRequest(req *http.Request) bool {
+	if isIdempotentRequest(req) {
+		return true
+	}
+
+	retryable, _ := req.Context().Value(retryableRequestKey{}).(bool)
+	return retryable && (req.Body == nil || req.GetBody != nil)
+}
+
+// isRetryableResponse returns true if the response, or the error, is transient,
+// requests that are not idempotent are only retried when the API rejected them
+// without processing them (429 and 503), since on any other error, the API
+// could have already processed them, like a scan that timed out
+func isRetryableResponse(req *http.Request, res *http.Response, err error) bool {
+	if err != nil {
+		if !isIdempotentRequest(req) {
+			return false
+		}
+
+		// do not retry timeouts, the timeout of the http client does not
+		// cancel the context of the request, retrying them would multiply
+		// the time to wait before surfacing the error
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return false
+		}
+
+		// do not retry if the request was canceled or its deadline exceeded
+		return req.Context().Err() == nil
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusGatewayTimeout:
+		return isIdempotentRequest(req)
+	}
+	return false
+}
+
+// retryDelay returns the time to wait before the next attempt, which is the
+// value of the Retry-After header or an exponential backoff of the attempt
+func retryDelay(res *http.Response, attempt int) time.Duration {
+	if res != nil {
+		if after := res.Header.Get("Retry-After"); after != "" {
+			if seconds, err := strconv.Atoi(after); err == nil {
+				return time.Duration(seconds) * time.Second
+			}
+			if date, err := http.ParseTime(after); err == nil {
+				return time.Until(date)
+			}
+		}
+	}
+
+	return retryBaseDelay << uint(attempt)
+}
+
+// doWithRetries executes the http request retrying it on transient errors
+func (c *Client) doWithRetries(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		res, err := c.c.Do(req)
+		if attempt >= c.retries ||
+			!isRetryableRequest(req) ||
+			!isRetryableResponse(req, res, err) {
+			return res, err
+		}
+
+		delay := retryDelay(res, attempt)
+		if delay > retryMaxDelay {
+			return res, err
+		}
+
+		fields := []zap.Field{
+			zap.String("url", req.URL.String()),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("delay", delay),
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		} else {
+			fields = append(fields, zap.Int("code", res.StatusCode))
+			// drain the body to reuse the connection
+			_, _ = io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+		c.log.Warn("retrying request", fields...)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/internal/lacework"
+)
+
+// flakyHandler returns a handler that responds with the provided status code
+// the first N times it is called, and then succeeds with the provided body
+func flakyHandler(failures, code int, body string, calls *int) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		if *calls <= failures {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "transient error", code)
+			return
+		}
+		fmt.Fprint(w, body)
+	}
+}
+
+func TestClientRetriesOnTransientErrors(t *testing.T) {
+	for _, code := range []int{429, 500, 502, 503, 504} {
+		t.Run(http.StatusText(code), func(t *testing.T) {
+			calls := 0
+			fakeServer := lacework.MockServer()
+			fakeServer.MockAPI("external/integrations",
+				flakyHandler(2, code, `{"ok": true, "data": []}`, &calls),
+			)
+			defer fakeServer.Close()
+
+			c, err := api.NewClient("test",
+				api.WithURL(fakeServer.URL()),
+				api.WithToken("TOKEN"),
+				api.WithRetries(3),
+			)
+			assert.Nil(t, err)
+
+			_, err = c.Integrations.List()
+			assert.Nil(t, err)
+			assert.Equal(t, 3, calls, "the request should have been retried twice")
+		})
+	}
+}
+
+func TestClientRetriesExhausted(t *testing.T) {
+	calls := 0
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI("external/integrations",
+		flakyHandler(5, 503, `{"ok": true, "data": []}`, &calls),
+	)
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+		api.WithRetries(2),
+	)
+	assert.Nil(t, err)
+
+	_, err = c.Integrations.List()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "503")
+	}
+	assert.Equal(t, 3, calls, "the request should have been sent three times")
+}
+
+func TestClientWithoutRetries(t *testing.T) {
+	calls := 0
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI("external/integrations",
+		flakyHandler(1, 429, `{"ok": true, "data": []}`, &calls),
+	)
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+	)
+	assert.Nil(t, err)
+
+	_, err = c.Integrations.List()
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls, "requests should not be retried by default")
+}
+
+func TestClientRetriesNonIdempotentRequests(t *testing.T) {
+	var (
+		createCalls = 0
+		scanCalls   = 0
+		fakeServer  = lacework.MockServer()
+	)
+	fakeServer.MockAPI("external/integrations",
+		flakyHandler(1, 503, `{"ok": true, "data": []}`, &createCalls),
+	)
+	fakeServer.MockAPI("external/vulnerabilities/scan",
+		flakyHandler(1, 429, `{"ok": true, "data": []}`, &scanCalls),
+	)
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+		api.WithRetries(3),
+	)
+	assert.Nil(t, err)
+
+	err = c.RequestEncoderDecoder("POST", "external/integrations", map[string]string{}, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, createCalls, "POST requests should not be retried")
+
+	_, err = c.Vulnerabilities.Host.Scan(`{"os_pkg_info_list": []}`)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, scanCalls, "package manifest scans should be retried")
+}
+
+func TestClientRetriesScanOnlyWhenRejected(t *testing.T) {
+	for _, code := range []int{500, 502, 504} {
+		t.Run(http.StatusText(code), func(t *testing.T) {
+			calls := 0
+			fakeServer := lacework.MockServer()
+			fakeServer.MockAPI("external/vulnerabilities/scan",
+				flakyHandler(1, code, `{"ok": true, "data": []}`, &calls),
+			)
+			defer fakeServer.Close()
+
+			c, err := api.NewClient("test",
+				api.WithURL(fakeServer.URL()),
+				api.WithToken("TOKEN"),
+				api.WithRetries(3),
+			)
+			assert.Nil(t, err)
+
+			_, err = c.Vulnerabilities.Host.Scan(`{"os_pkg_info_list": []}`)
+			assert.NotNil(t, err)
+			assert.Equal(t, 1, calls, "the scan could have been processed, it should not be retried")
+		})
+	}
+}
+
+func TestClientDoesNotRetryTimeouts(t *testing.T) {
+	var (
+		// the handlers keep running after the client times out
+		listCalls  int32
+		scanCalls  int32
+		fakeServer = lacework.MockServer()
+	)
+	fakeServer.MockAPI("external/integrations", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&listCalls, 1)
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, `{"ok": true, "data": []}`)
+	})
+	fakeServer.MockAPI("external/vulnerabilities/scan", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&scanCalls, 1)
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, `{"ok": true, "data": []}`)
+	})
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+		api.WithTimeout(50*time.Millisecond),
+		api.WithRetries(3),
+	)
+	assert.Nil(t, err)
+
+	_, err = c.Integrations.List()
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&listCalls), "timeouts should not be retried")
+
+	_, err = c.Vulnerabilities.Host.Scan(`{"os_pkg_info_list": []}`)
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&scanCalls), "timeouts should not be retried")
+}
+
+func TestClientRetryAfterTooLong(t *testing.T) {
+	calls := 0
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI("external/integrations", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "3600")
+		http.Error(w, "rate limited", 429)
+	})
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+		api.WithRetries(3),
+	)
+	assert.Nil(t, err)
+
+	_, err = c.Integrations.List()
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls, "we should not wait for an hour to retry")
+}
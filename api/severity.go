@@ -0,0 +1,84 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api
+
+import (
+	"strings"
+)
+
+// Severity is the severity of events, vulnerabilities and compliance
+// recommendations, the lower the value, the more severe it is
+type Severity int
+
+const (
+	SeverityCritical Severity = iota + 1
+	SeverityHigh
+	SeverityMedium
+	SeverityLow
+	SeverityInfo
+	SeverityUnknown
+)
+
+// ParseSeverity parses the provided severity, it accepts the names of the
+// severities in any case, like "High" or "critical", and their numeric
+// representation used by the events API, like "2" for high, any other
+// value is parsed as SeverityUnknown
+func ParseSeverity(severity string) Severity {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "1", "critical":
+		return SeverityCritical
+	case "2", "high":
+		return SeverityHigh
+	case "3", "medium":
+		return SeverityMedium
+	case "4", "low":
+		return SeverityLow
+	case "5", "info", "informational":
+		return SeverityInfo
+	default:
+		return SeverityUnknown
+	}
+}
+
+// String returns the human readable name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "Critical"
+	case SeverityHigh:
+		return "High"
+	case SeverityMedium:
+		return "Medium"
+	case SeverityLow:
+		return "Low"
+	case SeverityInfo:
+		return "Info"
+	default:
+		return "Unknown"
+	}
+}
+
+// Order returns the position of the severity when sorting from the most
+// severe to the least severe, unknown severities are sorted at the end
+func (s Severity) Order() int {
+	if s < SeverityCritical || s > SeverityUnknown {
+		return int(SeverityUnknown)
+	}
+	return int(s)
+}
@@ -0,0 +1,77 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+func TestParseSeverity(t *testing.T) {
+	cases := map[string]api.Severity{
+		"1":             api.SeverityCritical,
+		"critical":      api.SeverityCritical,
+		"Critical":      api.SeverityCritical,
+		"2":             api.SeverityHigh,
+		"HIGH":          api.SeverityHigh,
+		"3":             api.SeverityMedium,
+		"medium":        api.SeverityMedium,
+		"4":             api.SeverityLow,
+		" low ":         api.SeverityLow,
+		"5":             api.SeverityInfo,
+		"info":          api.SeverityInfo,
+		"Informational": api.SeverityInfo,
+		"":              api.SeverityUnknown,
+		"6":             api.SeverityUnknown,
+		"negligible":    api.SeverityUnknown,
+	}
+	for severity, expected := range cases {
+		assert.Equal(t, expected, api.ParseSeverity(severity),
+			"wrong severity parsed from '%s'", severity)
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	assert.Equal(t, "Critical", api.SeverityCritical.String())
+	assert.Equal(t, "High", api.SeverityHigh.String())
+	assert.Equal(t, "Medium", api.SeverityMedium.String())
+	assert.Equal(t, "Low", api.SeverityLow.String())
+	assert.Equal(t, "Info", api.SeverityInfo.String())
+	assert.Equal(t, "Unknown", api.SeverityUnknown.String())
+	assert.Equal(t, "Unknown", api.Severity(0).String())
+
+	// the numeric severity of compliance recommendations
+	recommendation := api.ComplianceRecommendation{Severity: 2}
+	assert.Equal(t, "High", recommendation.SeverityString())
+
+	event := api.Event{Severity: "5"}
+	assert.Equal(t, "Info", event.SeverityString())
+}
+
+func TestSeverityOrder(t *testing.T) {
+	assert.Equal(t, 1, api.ParseSeverity("critical").Order())
+	assert.Equal(t, 5, api.ParseSeverity("info").Order())
+	assert.Equal(t, 6, api.ParseSeverity("foo").Order())
+	assert.Equal(t, 6, api.Severity(0).Order())
+	assert.Equal(t, 6, api.Severity(42).Order())
+	assert.True(t, api.ParseSeverity("High").Order() < api.ParseSeverity("3").Order())
+}
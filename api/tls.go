@@ -0,0 +1,93 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// WithCACertFile configures the client to trust the certificate authorities
+// of the provided PEM file, in addition to the ones of the system, useful
+// behind TLS-intercepting proxies that present their own certificates
+//
+// NOTE: When a custom HTTP client is configured with WithHTTPClient, this
+// option is ignored, configure the TLS settings of the custom client instead
+func WithCACertFile(path string) Option {
+	return clientFunc(func(c *Client) error {
+		if path == "" {
+			return nil
+		}
+
+		pem, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrap(err, "unable to read CA certificate file")
+		}
+
+		if c.customHTTP {
+			c.log.Debug("custom http client configured, ignoring CA certificate file",
+				zap.String("ca_cert", path))
+			return nil
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.Errorf("no PEM certificates found in CA certificate file %s", path)
+		}
+
+		c.log.Debug("setting up client", zap.String("ca_cert", path))
+		c.tlsConfig().RootCAs = pool
+		return nil
+	})
+}
+
+// WithInsecureSkipVerify turns off the verification of the TLS certificate
+// of the API, the connection is vulnerable to man-in-the-middle attacks, so
+// this option should only be used for testing, prefer WithCACertFile
+//
+// NOTE: When a custom HTTP client is configured with WithHTTPClient, this
+// option is ignored, configure the TLS settings of the custom client instead
+func WithInsecureSkipVerify() Option {
+	return clientFunc(func(c *Client) error {
+		if c.customHTTP {
+			c.log.Debug("custom http client configured, ignoring insecure skip verify")
+			return nil
+		}
+
+		c.log.Warn("verification of TLS certificates turned off, the connection is insecure")
+		c.tlsConfig().InsecureSkipVerify = true
+		return nil
+	})
+}
+
+// tlsConfig returns the TLS configuration of the transport of the client
+func (c *Client) tlsConfig() *tls.Config {
+	transport := c.transport()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	return transport.TLSClientConfig
+}
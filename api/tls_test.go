@@ -0,0 +1,129 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api_test
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+func TestClientWithCACertFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "message": "SUCCESS", "data": []}`))
+	}))
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "lacework-ca-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caCert := filepath.Join(dir, "ca.pem")
+	assert.Nil(t, ioutil.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: server.Certificate().Raw,
+	}), 0600))
+
+	newClient := func(opts ...api.Option) (*api.Client, error) {
+		return api.NewClient("test", append([]api.Option{
+			api.WithURL(server.URL),
+			api.WithToken("TOKEN"),
+		}, opts...)...)
+	}
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		c, err := newClient()
+		if assert.Nil(t, err) {
+			_, err = c.Integrations.List()
+			if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), "certificate")
+			}
+		}
+	})
+
+	t.Run("trusted CA certificate", func(t *testing.T) {
+		c, err := newClient(api.WithCACertFile(caCert))
+		if assert.Nil(t, err) {
+			_, err = c.Integrations.List()
+			assert.Nil(t, err)
+		}
+	})
+
+	t.Run("insecure skip verify", func(t *testing.T) {
+		c, err := newClient(api.WithInsecureSkipVerify())
+		if assert.Nil(t, err) {
+			_, err = c.Integrations.List()
+			assert.Nil(t, err)
+		}
+	})
+
+	t.Run("transport wrapper keeps the CA certificate", func(t *testing.T) {
+		requests := 0
+		c, err := newClient(
+			api.WithTransportWrapper(func(transport http.RoundTripper) http.RoundTripper {
+				return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					requests++
+					return transport.RoundTrip(req)
+				})
+			}),
+			api.WithCACertFile(caCert),
+		)
+		if assert.Nil(t, err) {
+			_, err = c.Integrations.List()
+			assert.Nil(t, err)
+			assert.Equal(t, 1, requests, "the wrapper should see every request")
+		}
+	})
+
+	t.Run("missing CA certificate file", func(t *testing.T) {
+		_, err := newClient(api.WithCACertFile(filepath.Join(dir, "missing.pem")))
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), "unable to read CA certificate file")
+		}
+	})
+
+	t.Run("CA certificate file without certificates", func(t *testing.T) {
+		empty := filepath.Join(dir, "empty.pem")
+		assert.Nil(t, ioutil.WriteFile(empty, []byte("foo"), 0600))
+		_, err := newClient(api.WithCACertFile(empty))
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), "no PEM certificates found in CA certificate file")
+		}
+	})
+}
+
+func TestClientTLSOptionsDoNotChangeDefaultTransport(t *testing.T) {
+	_, err := api.NewClient("test", api.WithInsecureSkipVerify())
+	if assert.Nil(t, err) {
+		transport := http.DefaultTransport.(*http.Transport)
+		assert.True(t, transport.TLSClientConfig == nil || !transport.TLSClientConfig.InsecureSkipVerify,
+			"the default transport should not be modified")
+	}
+}
@@ -19,6 +19,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -33,11 +34,17 @@ type HostVulnerabilityService struct {
 // to determine if the packages contain any common vulnerabilities and exposures
 //
 // NOTE: Only packages managed by a package manager for supported OS's are reported
+//
+// The scan is synchronous, the response contains the results of the assessment
+// and, unlike container scans, there is no request id to poll for its status
 func (svc *HostVulnerabilityService) Scan(manifest string) (
 	response HostVulnScanPkgManifestResponse,
 	err error,
 ) {
-	err = svc.client.RequestDecoder("POST",
+	// the scan is rate limited, it is retried only when the API rejects it
+	err = svc.client.RequestDecoderWithContext(
+		withRetryableRequest(context.Background()),
+		"POST",
 		apiVulnerabilitiesScanPkgManifest,
 		strings.NewReader(manifest),
 		&response,
@@ -71,6 +78,32 @@ func (svc *HostVulnerabilityService) GetHostAssessment(id string) (
 	return
 }
 
+// HostVulnStatus is the status of the host vulnerability feature reported by
+// a response of the API, it explains why a response has no data
+type HostVulnStatus string
+
+const (
+	// the feature is enabled and the hosts are being assessed
+	HostVulnStatusOk HostVulnStatus = "ok"
+
+	// the feature is enabled but there are no agents deployed on any host
+	HostVulnStatusNoAgents HostVulnStatus = "no_agents"
+
+	// the feature is not enabled on the account
+	HostVulnStatusDisabled HostVulnStatus = "disabled"
+)
+
+// parseHostVulnStatus inspects the message of a host vulnerability response
+func parseHostVulnStatus(message string) HostVulnStatus {
+	if isFeatureDisabledMessage(message) {
+		return HostVulnStatusDisabled
+	}
+	if strings.Contains(strings.ToLower(message), "no agents") {
+		return HostVulnStatusNoAgents
+	}
+	return HostVulnStatusOk
+}
+
 type hostVulnHostResponse struct {
 	Assessment HostVulnHostAssessment `json:"data"`
 	Ok         bool                   `json:"ok"`
@@ -88,6 +121,12 @@ type hostVulnListHostsResponse struct {
 	Message string           `json:"message"`
 }
 
+// Status returns the status of the host vulnerability feature, use it to
+// find out why the response has no hosts
+func (r hostVulnListHostsResponse) Status() HostVulnStatus {
+	return parseHostVulnStatus(r.Message)
+}
+
 type HostVulnDetail struct {
 	Details  hostVulnHostDetail `json:"host"`
 	Packages []HostVulnPackage  `json:"packages"`
@@ -124,6 +163,12 @@ type hostVulnListCvesResponse struct {
 	Message string        `json:"message"`
 }
 
+// Status returns the status of the host vulnerability feature, use it to
+// find out why the response has no CVEs
+func (r hostVulnListCvesResponse) Status() HostVulnStatus {
+	return parseHostVulnStatus(r.Message)
+}
+
 type HostVulnCVE struct {
 	ID       string             `json:"cve_id"`
 	Packages []HostVulnPackage  `json:"packages"`
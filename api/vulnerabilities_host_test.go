@@ -0,0 +1,65 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/internal/lacework"
+)
+
+func TestHostVulnListCvesStatus(t *testing.T) {
+	cases := []struct {
+		message  string
+		expected api.HostVulnStatus
+	}{
+		{"", api.HostVulnStatusOk},
+		{"SUCCESS", api.HostVulnStatusOk},
+		{"No agents found for this account", api.HostVulnStatusNoAgents},
+		{"Assessed 3 hosts with the agent installed", api.HostVulnStatusOk},
+		{"Host Vulnerability feature is disabled", api.HostVulnStatusDisabled},
+	}
+
+	for _, kase := range cases {
+		t.Run(string(kase.expected), func(t *testing.T) {
+			fakeServer := lacework.MockServer()
+			fakeServer.MockAPI("external/vulnerabilities/host", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"ok": true, "data": [], "message": "%s"}`, kase.message)
+			})
+			defer fakeServer.Close()
+
+			c, err := api.NewClient("test",
+				api.WithURL(fakeServer.URL()),
+				api.WithToken("TOKEN"),
+			)
+			assert.Nil(t, err)
+
+			response, err := c.Vulnerabilities.Host.ListCves()
+			if assert.Nil(t, err) {
+				assert.Empty(t, response.CVEs)
+				assert.Equal(t, kase.expected, response.Status())
+			}
+		})
+	}
+}
@@ -19,8 +19,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/lacework/go-sdk/api"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -65,11 +63,12 @@ func generateAccessToken(_ *cobra.Command, args []string) error {
 	} else {
 		// if the duration is different from the default,
 		// regenerate the lacework api client
-		client, err := api.NewClient(cli.Account,
-			api.WithLogLevel(cli.LogLevel),
+		opts := []api.Option{
+			api.WithLogLevel(cli.apiLogLevel()),
 			api.WithExpirationTime(durationSeconds),
-			api.WithHeader("User-Agent", fmt.Sprintf("Command-Line/%s", Version)),
-		)
+			api.WithUserAgent(userAgent()),
+		}
+		client, err := api.NewClient(cli.Account, append(opts, cli.tlsOptions(cli.CACert)...)...)
 		if err != nil {
 			return errors.Wrap(err, "unable to generate api client")
 		}
@@ -91,8 +90,8 @@ Lacework CLI by reporting this issue at:
 `)
 	}
 
-	if cli.JSONOutput() {
-		return cli.OutputJSON(response.Data[0])
+	if cli.StructuredOutput() {
+		return cli.OutputStructured(response.Data[0])
 	}
 
 	cli.OutputHuman(response.Token())
@@ -0,0 +1,130 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// procVersionFile is the file used to detect if we are running under the
+// Windows Subsystem for Linux (WSL), it is a variable for testing purposes
+var procVersionFile = "/proc/version"
+
+// errNoBrowser is returned when there is no command available to launch
+// a web browser, like on headless servers
+var errNoBrowser = errors.New("no web browser available")
+
+// openURL launches the provided URL in a web browser by using the
+// first opener available in the current platform
+func openURL(url string) error {
+	for _, opener := range browserOpeners(runtime.GOOS, isWSL()) {
+		path, err := exec.LookPath(opener[0])
+		if err != nil {
+			continue
+		}
+
+		args := append(opener[1:], url)
+		cli.Log.Debugw("opening web browser", "cmd", path, "args", args)
+		return exec.Command(path, args...).Start()
+	}
+	return errNoBrowser
+}
+
+// browserOpeners returns the list of commands, in order of preference,
+// that can launch a web browser in the provided platform
+func browserOpeners(goos string, wsl bool) [][]string {
+	switch goos {
+	case "windows":
+		return [][]string{{"rundll32", "url.dll,FileProtocolHandler"}}
+	case "darwin":
+		return [][]string{{"open"}}
+	case "linux":
+		if wsl {
+			return [][]string{{"wslview"}, {"cmd.exe", "/c", "start"}}
+		}
+		// without a display server there is no browser to launch
+		if !hasDisplay() {
+			return nil
+		}
+		return [][]string{{"xdg-open"}}
+	default:
+		return nil
+	}
+}
+
+// copyToClipboard copies the provided text to the clipboard by using the
+// first clipboard command available in the current platform
+func copyToClipboard(text string) error {
+	for _, copier := range clipboardCopiers(runtime.GOOS, isWSL()) {
+		path, err := exec.LookPath(copier[0])
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, copier[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return errors.New("no clipboard available")
+}
+
+// clipboardCopiers returns the list of commands, in order of preference,
+// that can copy text from the standard input to the clipboard
+func clipboardCopiers(goos string, wsl bool) [][]string {
+	switch goos {
+	case "windows":
+		return [][]string{{"clip"}}
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "linux":
+		if wsl {
+			return [][]string{{"clip.exe"}}
+		}
+		if !hasDisplay() {
+			return nil
+		}
+		return [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+		}
+	default:
+		return nil
+	}
+}
+
+// isWSL detects if we are running under the Windows Subsystem for Linux
+func isWSL() bool {
+	version, err := ioutil.ReadFile(procVersionFile)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(version)), "microsoft")
+}
+
+// hasDisplay checks if there is a display server (X11 or Wayland) available
+func hasDisplay() bool {
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
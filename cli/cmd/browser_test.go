@@ -0,0 +1,86 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsWSL(t *testing.T) {
+	defer func(file string) { procVersionFile = file }(procVersionFile)
+
+	dir, err := ioutil.TempDir("", "lacework-cli")
+	if assert.Nil(t, err) {
+		defer os.RemoveAll(dir)
+	}
+
+	procVersionFile = path.Join(dir, "version")
+	assert.False(t, isWSL(), "a missing /proc/version is not WSL")
+
+	err = ioutil.WriteFile(procVersionFile,
+		[]byte("Linux version 4.19.128-microsoft-standard (gcc version 8.2.0)"), 0644)
+	assert.Nil(t, err)
+	assert.True(t, isWSL())
+
+	err = ioutil.WriteFile(procVersionFile,
+		[]byte("Linux version 5.4.0-42-generic (buildd@lgw01-amd64-038)"), 0644)
+	assert.Nil(t, err)
+	assert.False(t, isWSL())
+}
+
+func TestBrowserOpeners(t *testing.T) {
+	defer os.Setenv("DISPLAY", os.Getenv("DISPLAY"))
+	defer os.Setenv("WAYLAND_DISPLAY", os.Getenv("WAYLAND_DISPLAY"))
+	os.Setenv("DISPLAY", "")
+	os.Setenv("WAYLAND_DISPLAY", "")
+
+	assert.Equal(t, [][]string{{"open"}}, browserOpeners("darwin", false))
+	assert.Equal(t,
+		[][]string{{"rundll32", "url.dll,FileProtocolHandler"}},
+		browserOpeners("windows", false),
+	)
+	assert.Equal(t,
+		[][]string{{"wslview"}, {"cmd.exe", "/c", "start"}},
+		browserOpeners("linux", true),
+	)
+	assert.Empty(t, browserOpeners("linux", false), "headless linux has no browser")
+	assert.Empty(t, browserOpeners("plan9", false))
+
+	os.Setenv("DISPLAY", ":0")
+	assert.Equal(t, [][]string{{"xdg-open"}}, browserOpeners("linux", false))
+}
+
+func TestClipboardCopiers(t *testing.T) {
+	defer os.Setenv("DISPLAY", os.Getenv("DISPLAY"))
+	defer os.Setenv("WAYLAND_DISPLAY", os.Getenv("WAYLAND_DISPLAY"))
+	os.Setenv("DISPLAY", "")
+	os.Setenv("WAYLAND_DISPLAY", "")
+
+	assert.Equal(t, [][]string{{"pbcopy"}}, clipboardCopiers("darwin", false))
+	assert.Equal(t, [][]string{{"clip.exe"}}, clipboardCopiers("linux", true))
+	assert.Empty(t, clipboardCopiers("linux", false))
+
+	os.Setenv("WAYLAND_DISPLAY", "wayland-0")
+	assert.Contains(t, clipboardCopiers("linux", false), []string{"wl-copy"})
+}
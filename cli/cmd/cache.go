@@ -0,0 +1,45 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// cachePath returns the path of a file or directory inside the cache directory
+// of the cli, when the user has no cache directory, we use the temp directory
+func cachePath(elem ...string) string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+
+	return filepath.Join(append([]string{cacheDir, "lacework"}, elem...)...)
+}
+
+// cacheAccount returns the account used to make the cache unique per
+// account and subaccount
+func (c *cliState) cacheAccount() string {
+	if c.Subaccount != "" {
+		return fmt.Sprintf("%s.%s", c.Account, c.Subaccount)
+	}
+	return c.Account
+}
@@ -20,35 +20,55 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/BurntSushi/toml"
 	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
 	prettyjson "github.com/hokaccha/go-prettyjson"
+	"github.com/mattn/go-isatty"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
 	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwconfig"
 )
 
+// defaultApiRetries is the number of times the CLI retries API requests
+// on transient errors, like rate limits, unless the flag --no-retry is set
+const defaultApiRetries = 3
+
 // cliState holds the state of the entire Lacework CLI
 type cliState struct {
-	Profile  string
-	Account  string
-	KeyID    string
-	Secret   string
-	Token    string
-	LogLevel string
+	Profile    string
+	Account    string
+	Subaccount string
+	ApiURL     string
+	Domain     string
+	CACert     string
+	KeyID      string
+	Secret     string
+	Token      string
+	LogLevel   string
+	Timeout    time.Duration
 
 	LwApi *api.Client
 	JsonF *prettyjson.Formatter
 	Log   *zap.SugaredLogger
 
 	spinner        *spinner.Spinner
-	jsonOutput     bool
+	outputFormat   string
+	jsonStyle      string
 	nonInteractive bool
+	quiet          bool
+	debugHTTP      bool
+	insecureTLS    bool
+	paginate       bool
+	pager          *pager
+	timezone       *time.Location
 	profileDetails map[string]interface{}
 }
 
@@ -91,10 +111,10 @@ func (c *cliState) LoadState() error {
 	c.profileDetails = viper.GetStringMap(c.Profile)
 	if len(c.profileDetails) == 0 {
 		if c.Profile != "default" {
-			return fmt.Errorf(
+			return withExitCode(fmt.Errorf(
 				"The profile '%s' could not be found.\n\nTry running 'lacework configure --profile %s'.",
 				c.Profile, c.Profile,
-			)
+			), exitCodeNotFound)
 		} else {
 			c.Log.Debugw("unable to load state from config")
 			c.loadStateFromViper()
@@ -105,36 +125,94 @@ func (c *cliState) LoadState() error {
 	c.KeyID = c.extractValueString("api_key")
 	c.Secret = c.extractValueString("api_secret")
 	c.Account = c.extractValueString("account")
+	c.Subaccount = c.extractValueString("subaccount")
+	c.ApiURL = c.extractValueString("api_url")
+	c.Domain = c.extractValueString("domain")
+	c.CACert = c.extractValueString("ca_cert")
+	c.Timeout = time.Duration(c.extractValueInt("timeout")) * time.Second
 
 	c.Log.Debugw("state loaded",
 		"profile", c.Profile,
 		"account", c.Account,
-		"api_key", c.KeyID,
-		"api_secret", c.Secret,
+		"subaccount", c.Subaccount,
+		"api_url", c.ApiURL,
+		"domain", c.Domain,
+		"ca_cert", c.CACert,
+		"timeout", c.Timeout,
+		"api_key", formatSecret(4, c.KeyID),
+		"api_secret", formatSecret(4, c.Secret),
 	)
 
+	profileAccount := c.Account
 	c.loadStateFromViper()
+	c.resetProfileSettingsOfOtherAccount(profileAccount)
 	return nil
 }
 
-// LoadProfiles loads all the profiles from the configuration file
-func (c *cliState) LoadProfiles() (Profiles, error) {
-	var (
-		profiles = Profiles{}
-		confPath = viper.ConfigFileUsed()
-	)
+// resetProfileSettingsOfOtherAccount resets the API URL and the subaccount loaded
+// from the profile when the account is overridden with the flag --account or the
+// environment variable LW_ACCOUNT, since those settings belong to the account of
+// the profile, they are only used when they are also overridden
+func (c *cliState) resetProfileSettingsOfOtherAccount(profileAccount string) {
+	if strings.EqualFold(c.Account, profileAccount) {
+		return
+	}
+
+	if c.ApiURL != "" && viper.GetString("api_url") == "" {
+		c.Log.Debugw("ignoring api_url of profile, the account was overridden",
+			"profile_account", profileAccount, "account", c.Account, "api_url", c.ApiURL,
+		)
+		c.ApiURL = ""
+	}
+
+	if c.Subaccount != "" && viper.GetString("subaccount") == "" {
+		c.Log.Debugw("ignoring subaccount of profile, the account was overridden",
+			"profile_account", profileAccount, "account", c.Account, "subaccount", c.Subaccount,
+		)
+		c.Subaccount = ""
+	}
+}
+
+// ConfigFilePath returns the path of the configuration file, that is, the
+// path provided via --config or LW_CONFIG, or the default ~/.lacework.toml
+//
+// NOTE: The returned file might not exist yet
+func (c *cliState) ConfigFilePath() (string, error) {
+	if confPath := viper.ConfigFileUsed(); confPath != "" {
+		return confPath, nil
+	}
+	return lwconfig.DefaultConfigPath()
+}
+
+// UpdatesEnabled returns true if the check for updates is on, the check can
+// be turned off with 'lacework configure updates off' (updates = false in
+// the configuration file)
+func (c *cliState) UpdatesEnabled() bool {
+	if !viper.InConfig("updates") {
+		return true
+	}
+	return viper.GetBool("updates")
+}
 
+// LoadProfiles loads all the profiles from the configuration file
+func (c *cliState) LoadProfiles() (lwconfig.Profiles, error) {
+	confPath := viper.ConfigFileUsed()
 	if confPath == "" {
-		return profiles, errors.New("unable to load profiles. No configuration file found.")
+		return lwconfig.Profiles{}, errors.New("unable to load profiles. No configuration file found.")
+	}
+	if _, err := os.Stat(confPath); os.IsNotExist(err) {
+		return lwconfig.Profiles{}, errors.Errorf(
+			"unable to load profiles. No configuration file found at %s.", confPath)
 	}
 
-	cli.Log.Debugw("decoding config", "path", confPath)
-	if _, err := toml.DecodeFile(confPath, &profiles); err != nil {
-		return profiles, errors.Wrap(err, "unable to decode profiles from config")
+	c.Log.Debugw("decoding config", "path", confPath)
+	config, err := lwconfig.LoadFromFile(confPath)
+	if err != nil {
+		return config.Profiles, err
 	}
 
-	cli.Log.Debugw("profiles loaded from config", "profiles", profiles)
-	return profiles, nil
+	c.Log.Debugw("profiles loaded from config", "profiles", config.ProfileNames())
+	return config.Profiles, nil
 }
 
 // VerifySettings checks if the CLI state has the neccessary settings to run,
@@ -152,6 +230,15 @@ func (c *cliState) VerifySettings() error {
 	return nil
 }
 
+// apiLogLevel returns the log level of the API clients, requests and
+// responses are only logged at debug level, so --debug-http forces it
+func (c *cliState) apiLogLevel() string {
+	if c.debugHTTP {
+		return "DEBUG"
+	}
+	return c.LogLevel
+}
+
 // NewClient creates and stores a new Lacework API client to be used by the CLI
 func (c *cliState) NewClient() error {
 	err := c.VerifySettings()
@@ -159,11 +246,25 @@ func (c *cliState) NewClient() error {
 		return err
 	}
 
-	client, err := api.NewClient(c.Account,
-		api.WithLogLevel(c.LogLevel),
+	opts := []api.Option{
+		api.WithLogLevel(c.apiLogLevel()),
+		api.WithSubaccount(c.Subaccount),
 		api.WithApiKeys(c.KeyID, c.Secret),
-		api.WithHeader("User-Agent", fmt.Sprintf("Command-Line/%s", Version)),
-	)
+		api.WithUserAgent(userAgent()),
+	}
+	if c.ApiURL != "" {
+		opts = append(opts, api.WithURL(c.ApiURL))
+	}
+	if c.Timeout != 0 {
+		opts = append(opts, api.WithTimeout(c.Timeout))
+	}
+	if !viper.GetBool("no_retry") {
+		opts = append(opts, api.WithRetries(defaultApiRetries))
+	}
+	opts = append(opts, c.concurrencyOptions()...)
+	opts = append(opts, c.tlsOptions(c.CACert)...)
+
+	client, err := api.NewClient(c.Account, opts...)
 	if err != nil {
 		return errors.Wrap(err, "unable to generate api client")
 	}
@@ -172,6 +273,34 @@ func (c *cliState) NewClient() error {
 	return nil
 }
 
+// concurrencyOptions returns the options of the api client to bound the
+// number of requests that bulk operations send at once, set with the flag
+// --concurrency or the environment variable LW_CONCURRENCY
+func (c *cliState) concurrencyOptions() []api.Option {
+	if !viper.IsSet("concurrency") {
+		return []api.Option{}
+	}
+	return []api.Option{api.WithConcurrency(viper.GetInt("concurrency"))}
+}
+
+// tlsOptions returns the options of the api client to trust the certificate
+// authorities of the provided CA certificate file, and to turn off the
+// verification of TLS certificates when --insecure-skip-verify is set
+func (c *cliState) tlsOptions(caCert string) []api.Option {
+	opts := []api.Option{}
+	if caCert != "" {
+		opts = append(opts, api.WithCACertFile(caCert))
+	}
+	if c.insecureTLS {
+		errcheckWARN(errors.New(
+			"the verification of TLS certificates is turned off (--insecure-skip-verify), " +
+				"the connection to the Lacework API is not secure",
+		))
+		opts = append(opts, api.WithInsecureSkipVerify())
+	}
+	return opts
+}
+
 // InteractiveMode returns true if the cli is running in interactive mode
 func (c *cliState) InteractiveMode() bool {
 	return !c.nonInteractive
@@ -183,8 +312,53 @@ func (c *cliState) NonInteractive() {
 	c.nonInteractive = true
 }
 
+// Quiet returns true if the informational human output, like footers with
+// hints and success banners, should be suppressed, commands should consult
+// it before printing anything that is not the actual data
+func (c *cliState) Quiet() bool {
+	return c.quiet
+}
+
+// SetQuiet turns on or off the quiet mode, see Quiet()
+func (c *cliState) SetQuiet(quiet bool) {
+	c.Log.Infow("switch quiet mode", "quiet", quiet)
+	c.quiet = quiet
+}
+
+// SetTimezone sets the time zone used to format the timestamps of the
+// human-readable output, it accepts IANA names like America/New_York
+func (c *cliState) SetTimezone(name string) error {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return errors.Errorf(
+			"unknown time zone '%s', use an IANA name like America/New_York or UTC", name,
+		)
+	}
+
+	c.Log.Infow("switch time zone", "timezone", location.String())
+	c.timezone = location
+	return nil
+}
+
+// Location returns the time zone set with the flag --timezone, by default, UTC
+func (c *cliState) Location() *time.Location {
+	if c.timezone == nil {
+		return time.UTC
+	}
+	return c.timezone
+}
+
+// FormatTime formats the provided time in RFC3339 in the time zone
+// set with the flag --timezone, by default, in UTC
+func (c *cliState) FormatTime(t time.Time) string {
+	return t.In(c.Location()).Format(time.RFC3339)
+}
+
 // StartProgress starts a new progress spinner with the provider suffix and stores it
 // into the cli state, make sure to run StopSpinner when you are done processing
+//
+// The spinner is disabled in non-interactive mode, with a structured output
+// and when the standard output is not a terminal
 func (c *cliState) StartProgress(suffix string) {
 	if c.nonInteractive {
 		c.Log.Debugw("skipping spinner",
@@ -194,6 +368,14 @@ func (c *cliState) StartProgress(suffix string) {
 		return
 	}
 
+	if !stdoutIsTerminal() {
+		c.Log.Debugw("skipping spinner",
+			"terminal", false,
+			"action", "start_progress",
+		)
+		return
+	}
+
 	// humans like spinners (^.^)
 	if c.HumanOutput() {
 		// make sure there is not a spinner already running
@@ -206,6 +388,35 @@ func (c *cliState) StartProgress(suffix string) {
 	}
 }
 
+// StepProgress reports the progress of an operation that makes many API
+// calls, like a loop over a list of items, i.e. " Scanning 3/12...", it
+// starts a progress spinner if there is none running, make sure to run
+// StopProgress when you are done processing
+func (c *cliState) StepProgress(action string, step, total int) {
+	suffix := progressStepSuffix(action, step, total)
+	if c.spinner == nil {
+		c.StartProgress(suffix)
+		return
+	}
+
+	c.Log.Debugw("updating spinner", "step", step, "total", total)
+	c.spinner.Lock()
+	c.spinner.Suffix = suffix
+	c.spinner.Unlock()
+}
+
+// progressStepSuffix returns the suffix of a progress spinner that reports
+// the step of an operation, i.e. " Scanning 3/12..."
+func progressStepSuffix(action string, step, total int) string {
+	return fmt.Sprintf(" %s %d/%d...", action, step, total)
+}
+
+// stdoutIsTerminal returns true if the standard output is a terminal
+func stdoutIsTerminal() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
 // StopProgress stops the running progress spinner, if any
 func (c *cliState) StopProgress() {
 	if c.nonInteractive {
@@ -229,23 +440,73 @@ func (c *cliState) StopProgress() {
 // EnableJSONOutput enables the cli to display JSON output
 func (c *cliState) EnableJSONOutput() {
 	c.Log.Info("switch output to json format")
-	c.jsonOutput = true
+	c.outputFormat = outputFormatJSON
 }
 
 // EnableJSONOutput enables the cli to display human readable output
 func (c *cliState) EnableHumanOutput() {
 	c.Log.Info("switch output to human format")
-	c.jsonOutput = false
+	c.outputFormat = outputFormatTable
+}
+
+// SetOutputFormat switches the output of the cli to the provided format,
+// valid formats are: table, json, csv and yaml
+func (c *cliState) SetOutputFormat(format string) error {
+	format = strings.ToLower(format)
+	for _, valid := range validOutputFormats {
+		if format == valid {
+			c.Log.Infow("switch output format", "format", format)
+			c.outputFormat = format
+			return nil
+		}
+	}
+	return errors.Errorf("invalid output format '%s' (valid formats: %s)",
+		format, strings.Join(validOutputFormats, ", "))
+}
+
+// OutputFormat returns the output format that the cli is configured to display,
+// subcommands should consult it to decide how to print out their results
+func (c *cliState) OutputFormat() string {
+	if c.outputFormat == "" {
+		return outputFormatTable
+	}
+	return c.outputFormat
+}
+
+// SetJSONStyle switches the style of the JSON output, valid styles are:
+// pretty (the default), compact and lines (JSON Lines)
+func (c *cliState) SetJSONStyle(style string) {
+	c.Log.Infow("switch json style", "style", style)
+	c.jsonStyle = style
+}
+
+// JSONStyle returns the style of the JSON output, pretty by default
+func (c *cliState) JSONStyle() string {
+	if c.jsonStyle == "" {
+		return jsonStylePretty
+	}
+	return c.jsonStyle
 }
 
 // JSONOutput returns true if the cli is configured to display JSON output
 func (c *cliState) JSONOutput() bool {
-	return c.jsonOutput
+	return c.OutputFormat() == outputFormatJSON
+}
+
+// CSVOutput returns true if the cli is configured to display CSV output
+func (c *cliState) CSVOutput() bool {
+	return c.OutputFormat() == outputFormatCSV
 }
 
 // HumanOutput returns true if the cli is configured to siplay human readable output
 func (c *cliState) HumanOutput() bool {
-	return !c.jsonOutput
+	return c.OutputFormat() == outputFormatTable
+}
+
+// StructuredOutput returns true if the cli is configured to display a machine
+// readable output like JSON, CSV or YAML, the opposite of HumanOutput()
+func (c *cliState) StructuredOutput() bool {
+	return !c.HumanOutput()
 }
 
 // loadStateFromViper loads parameters and environment variables
@@ -253,18 +514,53 @@ func (c *cliState) HumanOutput() bool {
 func (c *cliState) loadStateFromViper() {
 	if v := viper.GetString("api_key"); v != "" {
 		c.KeyID = v
-		c.Log.Debugw("state updated", "api_key", c.KeyID)
+		c.Log.Debugw("state updated", "api_key", formatSecret(4, c.KeyID))
 	}
 
 	if v := viper.GetString("api_secret"); v != "" {
 		c.Secret = v
-		c.Log.Debugw("state updated", "api_secret", c.Secret)
+		c.Log.Debugw("state updated", "api_secret", formatSecret(4, c.Secret))
 	}
 
 	if v := viper.GetString("account"); v != "" {
 		c.Account = v
 		c.Log.Debugw("state updated", "account", c.Account)
 	}
+
+	if v := viper.GetString("subaccount"); v != "" {
+		c.Subaccount = v
+		c.Log.Debugw("state updated", "subaccount", c.Subaccount)
+	}
+
+	if v := viper.GetString("api_url"); v != "" {
+		c.ApiURL = v
+		c.Log.Debugw("state updated", "api_url", c.ApiURL)
+	}
+
+	if v := viper.GetString("domain"); v != "" {
+		c.Domain = v
+		c.Log.Debugw("state updated", "domain", c.Domain)
+	}
+
+	if v := viper.GetString("ca_cert"); v != "" {
+		c.CACert = v
+		c.Log.Debugw("state updated", "ca_cert", c.CACert)
+	}
+
+	if viper.GetBool("insecure_skip_verify") {
+		c.insecureTLS = true
+		c.Log.Debugw("state updated", "insecure_skip_verify", c.insecureTLS)
+	}
+
+	if v := viper.GetString("timeout"); v != "" {
+		timeout, err := parseTimeout(v)
+		if err != nil {
+			c.Log.Warnw("invalid timeout, using default", "timeout", v, "error", err)
+		} else {
+			c.Timeout = timeout
+			c.Log.Debugw("state updated", "timeout", c.Timeout)
+		}
+	}
 }
 
 func (c *cliState) extractValueString(key string) string {
@@ -288,3 +584,40 @@ func (c *cliState) extractValueString(key string) string {
 	)
 	return ""
 }
+
+func (c *cliState) extractValueInt(key string) int {
+	if val, ok := c.profileDetails[key]; ok {
+		switch num := val.(type) {
+		case int64:
+			return int(num)
+		case int:
+			return num
+		}
+		c.Log.Warnw("config value type mismatch",
+			"expected_type", "int",
+			"file", viper.ConfigFileUsed(),
+			"profile", c.Profile,
+			"key", key,
+			"value", val,
+		)
+	}
+	return 0
+}
+
+// parseTimeout parses a timeout provided as a number of seconds
+// or as a duration (i.e. 90s, 2m), it must be greater than zero
+func parseTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, errInt := strconv.Atoi(value)
+		if errInt != nil {
+			return 0, err
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	if timeout <= 0 {
+		return 0, errors.New("timeout must be greater than zero")
+	}
+	return timeout, nil
+}
@@ -0,0 +1,195 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestParseTimeout(t *testing.T) {
+	timeout, err := parseTimeout("30")
+	if assert.Nil(t, err) {
+		assert.Equal(t, 30*time.Second, timeout)
+	}
+
+	timeout, err = parseTimeout("2m")
+	if assert.Nil(t, err) {
+		assert.Equal(t, 2*time.Minute, timeout)
+	}
+
+	_, err = parseTimeout("0")
+	assert.EqualError(t, err, "timeout must be greater than zero")
+
+	_, err = parseTimeout("foo")
+	assert.NotNil(t, err)
+}
+
+func TestOutputFormat(t *testing.T) {
+	c := NewDefaultState()
+	c.Log = lwlogger.New("").Sugar()
+
+	assert.Equal(t, "table", c.OutputFormat())
+	assert.True(t, c.HumanOutput())
+	assert.False(t, c.StructuredOutput())
+
+	c.EnableJSONOutput()
+	assert.Equal(t, "json", c.OutputFormat())
+	assert.True(t, c.JSONOutput())
+	assert.True(t, c.StructuredOutput())
+
+	if assert.Nil(t, c.SetOutputFormat("CSV")) {
+		assert.Equal(t, "csv", c.OutputFormat())
+		assert.True(t, c.CSVOutput())
+		assert.False(t, c.JSONOutput())
+		assert.False(t, c.HumanOutput())
+	}
+
+	if assert.Nil(t, c.SetOutputFormat("yaml")) {
+		assert.Equal(t, "yaml", c.OutputFormat())
+		assert.True(t, c.StructuredOutput())
+	}
+
+	err := c.SetOutputFormat("xml")
+	assert.EqualError(t, err, "invalid output format 'xml' (valid formats: table, json, csv, yaml)")
+	assert.Equal(t, "yaml", c.OutputFormat(), "an invalid format should not change the output")
+}
+
+func TestJSONStyle(t *testing.T) {
+	c := NewDefaultState()
+	c.Log = lwlogger.New("").Sugar()
+	assert.Equal(t, "pretty", c.JSONStyle())
+
+	c.SetJSONStyle(jsonStyleLines)
+	assert.Equal(t, "lines", c.JSONStyle())
+}
+
+func TestQuiet(t *testing.T) {
+	c := NewDefaultState()
+	c.Log = lwlogger.New("").Sugar()
+	assert.False(t, c.Quiet(), "quiet mode should be off by default")
+
+	c.SetQuiet(true)
+	assert.True(t, c.Quiet())
+}
+
+func TestApiLogLevel(t *testing.T) {
+	state := cliState{LogLevel: "INFO"}
+	assert.Equal(t, "INFO", state.apiLogLevel())
+
+	state.debugHTTP = true
+	assert.Equal(t, "DEBUG", state.apiLogLevel(),
+		"--debug-http should log the API requests at debug level")
+}
+
+func TestResetProfileSettingsOfOtherAccount(t *testing.T) {
+	state := cliState{
+		Log:        lwlogger.New("").Sugar(),
+		Account:    "other",
+		ApiURL:     "https://profile.lacework.net",
+		Subaccount: "profile-sub",
+	}
+	state.resetProfileSettingsOfOtherAccount("profile")
+	assert.Empty(t, state.ApiURL, "the api_url of the profile should be ignored")
+	assert.Empty(t, state.Subaccount, "the subaccount of the profile should be ignored")
+
+	state = cliState{
+		Log:        lwlogger.New("").Sugar(),
+		Account:    "Profile",
+		ApiURL:     "https://profile.lacework.net",
+		Subaccount: "profile-sub",
+	}
+	state.resetProfileSettingsOfOtherAccount("profile")
+	assert.Equal(t, "https://profile.lacework.net", state.ApiURL)
+	assert.Equal(t, "profile-sub", state.Subaccount)
+
+	viper.Set("subaccount", "flag-sub")
+	defer viper.Set("subaccount", "")
+	state.Account = "other"
+	state.Subaccount = "flag-sub"
+	state.resetProfileSettingsOfOtherAccount("profile")
+	assert.Empty(t, state.ApiURL)
+	assert.Equal(t, "flag-sub", state.Subaccount,
+		"an overridden subaccount should be kept")
+}
+
+func TestFormatTimeTimezone(t *testing.T) {
+	c := NewDefaultState()
+	c.Log = lwlogger.New("").Sugar()
+	moment := time.Date(2020, 8, 20, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2020-08-20T08:00:00Z", c.FormatTime(moment), "timestamps should be in UTC by default")
+
+	if assert.Nil(t, c.SetTimezone("America/New_York")) {
+		assert.Equal(t, "2020-08-20T04:00:00-04:00", c.FormatTime(moment))
+	}
+
+	assert.EqualError(t, c.SetTimezone("Mars/Olympus_Mons"),
+		"unknown time zone 'Mars/Olympus_Mons', use an IANA name like America/New_York or UTC")
+}
+
+func TestProgressStepSuffix(t *testing.T) {
+	assert.Equal(t, " Scanning 3/12...", progressStepSuffix("Scanning", 3, 12))
+}
+
+func TestStepProgressWithoutTerminal(t *testing.T) {
+	c := NewDefaultState()
+	c.Log = lwlogger.New("").Sugar()
+
+	// the standard output of the tests is not a terminal,
+	// no spinner should be started
+	c.StepProgress("Scanning", 1, 2)
+	assert.Nil(t, c.spinner)
+	c.StopProgress()
+}
+
+func TestWriteJSONStyles(t *testing.T) {
+	events := []struct {
+		ID       int    `json:"id"`
+		Severity string `json:"severity"`
+	}{{1, "high"}, {2, "low"}}
+
+	buf := new(bytes.Buffer)
+	if assert.Nil(t, writeJSONCompact(buf, events)) {
+		assert.Equal(t, `[{"id":1,"severity":"high"},{"id":2,"severity":"low"}]`+"\n", buf.String())
+	}
+
+	buf.Reset()
+	if assert.Nil(t, writeJSONLines(buf, events)) {
+		assert.Equal(t, `{"id":1,"severity":"high"}
+{"id":2,"severity":"low"}
+`, buf.String())
+	}
+
+	// data that is not a list is written into a single line
+	buf.Reset()
+	if assert.Nil(t, writeJSONLines(buf, events[0])) {
+		assert.Equal(t, `{"id":1,"severity":"high"}`+"\n", buf.String())
+	}
+
+	buf.Reset()
+	if assert.Nil(t, writeJSONLines(buf, []string{})) {
+		assert.Empty(t, buf.String())
+	}
+}
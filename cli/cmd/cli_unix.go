@@ -19,7 +19,12 @@
 
 package cmd
 
-import "github.com/AlecAivazis/survey/v2"
+import (
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"golang.org/x/sys/unix"
+)
 
 // used by configure.go
 var configureListCmdSetProfileEnv = `$ export LW_PROFILE="my-profile"`
@@ -36,3 +41,13 @@ func (c *cliState) UpdateCommand() string {
   $ curl https://raw.githubusercontent.com/lacework/go-sdk/master/cli/install.sh | bash
 `
 }
+
+// stdoutTerminalWidth returns the number of columns of the terminal attached
+// to the standard output, or zero if it is not a terminal (unix specific)
+func stdoutTerminalWidth() int {
+	size, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+	return int(size.Col)
+}
@@ -36,3 +36,9 @@ func (c *cliState) UpdateCommand() string {
   C:\> iex ((New-Object System.Net.WebClient).DownloadString('https://raw.githubusercontent.com/lacework/go-sdk/master/cli/install.ps1'))
 `
 }
+
+// stdoutTerminalWidth returns zero since the width of the terminal is not
+// detected on Windows, use the environment variable COLUMNS to provide it
+func stdoutTerminalWidth() int {
+	return 0
+}
@@ -0,0 +1,59 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// severityColors are the colors used to highlight severities in tables
+var severityColors = map[string]*color.Color{
+	"critical": color.New(color.FgHiRed, color.Bold),
+	"high":     color.New(color.FgRed),
+	"medium":   color.New(color.FgYellow),
+	"low":      color.New(color.FgCyan),
+}
+
+// DisableColors turns off the colors of every output of the cli
+func (c *cliState) DisableColors() {
+	c.JsonF.DisabledColor = true
+	color.NoColor = true
+}
+
+// ColorsEnabled returns true if the cli is configured to display colors,
+// colors are automatically disabled when the standard output is not a
+// terminal or when the output format is not human readable
+func (c *cliState) ColorsEnabled() bool {
+	return c.HumanOutput() && !color.NoColor
+}
+
+// colorizeSeverity returns the provided severity highlighted with its
+// color, or the severity untouched if colors are disabled
+func (c *cliState) colorizeSeverity(severity string) string {
+	if !c.ColorsEnabled() {
+		return severity
+	}
+
+	if sevColor, ok := severityColors[strings.ToLower(severity)]; ok {
+		return sevColor.Sprint(severity)
+	}
+	return severity
+}
@@ -0,0 +1,49 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorizeSeverity(t *testing.T) {
+	defer func(state cliState, noColor bool) {
+		cli = state
+		color.NoColor = noColor
+	}(cli, color.NoColor)
+
+	color.NoColor = false
+	cli.outputFormat = outputFormatTable
+	assert.Equal(t, "\x1b[91;1mCritical\x1b[0m", cli.colorizeSeverity("Critical"))
+	assert.Equal(t, "\x1b[31mhigh\x1b[0m", cli.colorizeSeverity("high"))
+	assert.Equal(t, "Negligible", cli.colorizeSeverity("Negligible"),
+		"severities without a color should not be modified")
+
+	cli.outputFormat = outputFormatCSV
+	assert.Equal(t, "Critical", cli.colorizeSeverity("Critical"),
+		"colors should be disabled for machine readable outputs")
+
+	cli.outputFormat = outputFormatTable
+	color.NoColor = true
+	assert.Equal(t, "Critical", cli.colorizeSeverity("Critical"),
+		"colors should be disabled when the output is not a terminal")
+}
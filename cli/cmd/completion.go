@@ -0,0 +1,201 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// completionCacheTTL is the time that the values of dynamic completions are
+// cached, every tab press runs a new process so we cache them on disk to
+// avoid hammering the Lacework API
+const completionCacheTTL = 5 * time.Minute
+
+// completionCache is the content of a cache file of dynamic completions
+type completionCache struct {
+	Timestamp time.Time `json:"timestamp"`
+	Values    []string  `json:"values"`
+}
+
+var (
+	// completionCmd represents the completion command
+	completionCmd = &cobra.Command{
+		Use:   "completion <bash|zsh|fish|powershell>",
+		Short: "generate shell completion scripts",
+		Long: `Generates a completion script for the provided shell.
+
+To load completions in your current bash session:
+
+    $ source <(lacework completion bash)
+
+To load completions for every new zsh session, add the script to your $fpath:
+
+    $ lacework completion zsh > "${fpath[1]}/_lacework"
+
+To load completions for every new fish session:
+
+    $ lacework completion fish > ~/.config/fish/completions/lacework.fish`,
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch args[0] {
+			case "bash":
+				return cmd.Root().GenBashCompletion(os.Stdout)
+			case "zsh":
+				return cmd.Root().GenZshCompletion(os.Stdout)
+			case "fish":
+				return cmd.Root().GenFishCompletion(os.Stdout, true)
+			case "powershell":
+				return cmd.Root().GenPowerShellCompletion(os.Stdout)
+			default:
+				return errors.Errorf("unsupported shell '%s'", args[0])
+			}
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+// completeWithValues returns a completion function that suggests the
+// provided values, useful for flags that accept a fixed list of values
+func completeWithValues(values []string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeEventIDs suggests the ids of the events from the last 7 days
+func completeEventIDs(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completeWithCachedValues("events", func() ([]string, error) {
+		response, err := cli.LwApi.Events.List()
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, len(response.Events))
+		for i, event := range response.Events {
+			ids[i] = event.EventID
+		}
+		return ids, nil
+	})
+}
+
+// completeHostCVEIDs suggests the ids of the CVEs found in the hosts
+func completeHostCVEIDs(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completeWithCachedValues("host-cves", func() ([]string, error) {
+		response, err := cli.LwApi.Vulnerabilities.Host.ListCves()
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, len(response.CVEs))
+		for i, cve := range response.CVEs {
+			ids[i] = cve.ID
+		}
+		return ids, nil
+	})
+}
+
+// completeWithCachedValues returns the cached values of the provided completion,
+// if the cache doesn't exist or it has expired, it creates a new API client
+// and runs the fetch function to get the values and cache them
+func completeWithCachedValues(name string,
+	fetch func() ([]string, error),
+) ([]string, cobra.ShellCompDirective) {
+	cachePath := completionCachePath(name)
+	if values, ok := readCompletionCache(cachePath); ok {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if cli.LwApi == nil {
+		if err := cli.NewClient(); err != nil {
+			cli.Log.Debugw("unable to create api client for completion", "error", err)
+			return nil, cobra.ShellCompDirectiveError
+		}
+	}
+
+	values, err := fetch()
+	if err != nil {
+		cli.Log.Debugw("unable to fetch completion values", "name", name, "error", err)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	writeCompletionCache(cachePath, values)
+	return values, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionCachePath returns the path of the cache file of the provided
+// completion, the cache is unique per account and subaccount
+func completionCachePath(name string) string {
+	return cachePath("completion", fmt.Sprintf("%s-%s.json", cli.cacheAccount(), name))
+}
+
+func readCompletionCache(path string) ([]string, bool) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var cache completionCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, false
+	}
+
+	if time.Since(cache.Timestamp) > completionCacheTTL {
+		cli.Log.Debugw("completion cache expired", "path", path)
+		return nil, false
+	}
+
+	cli.Log.Debugw("using completion cache", "path", path)
+	return cache.Values, true
+}
+
+func writeCompletionCache(path string, values []string) {
+	data, err := json.Marshal(completionCache{Timestamp: time.Now(), Values: values})
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		cli.Log.Debugw("unable to create completion cache directory", "error", err)
+		return
+	}
+
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		cli.Log.Debugw("unable to write completion cache", "path", path, "error", err)
+	}
+}
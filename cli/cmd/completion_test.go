@@ -0,0 +1,85 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestCompleteWithCachedValues(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+
+	cacheDir, err := ioutil.TempDir("", "lacework-completion")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(cacheDir)
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	os.Setenv("XDG_CACHE_HOME", cacheDir)
+
+	cli.Account = "test"
+	cli.Log = lwlogger.New("").Sugar()
+	cli.LwApi, err = api.NewClient("test")
+	assert.Nil(t, err)
+
+	var (
+		calls = 0
+		fetch = func() ([]string, error) {
+			calls++
+			return []string{"1", "2"}, nil
+		}
+	)
+
+	values, directive := completeWithCachedValues("events", fetch)
+	assert.Equal(t, []string{"1", "2"}, values)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	assert.FileExists(t, filepath.Join(cacheDir, "lacework", "completion", "test-events.json"))
+
+	values, _ = completeWithCachedValues("events", fetch)
+	assert.Equal(t, []string{"1", "2"}, values)
+	assert.Equal(t, 1, calls, "the second completion should use the cache")
+
+	// expire the cache
+	data, _ := json.Marshal(completionCache{
+		Timestamp: time.Now().Add(-2 * completionCacheTTL),
+		Values:    []string{"old"},
+	})
+	assert.Nil(t, ioutil.WriteFile(completionCachePath("events"), data, 0600))
+
+	values, _ = completeWithCachedValues("events", fetch)
+	assert.Equal(t, []string{"1", "2"}, values)
+	assert.Equal(t, 2, calls, "an expired cache should be refreshed")
+
+	// the cache is unique per subaccount
+	cli.Subaccount = "tenant"
+	assert.Equal(t,
+		filepath.Join(cacheDir, "lacework", "completion", "test.tenant-events.json"),
+		completionCachePath("events"))
+}
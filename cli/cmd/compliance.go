@@ -203,7 +203,7 @@ func complianceReportRecommendationsTable(recommendations []api.ComplianceRecomm
 	}
 
 	sort.Slice(out, func(i, j int) bool {
-		return severityOrder(out[i][3]) < severityOrder(out[j][3])
+		return api.ParseSeverity(out[i][3]).Order() < api.ParseSeverity(out[j][3]).Order()
 	})
 
 	return out
@@ -278,11 +278,13 @@ func buildComplianceReportTable(detailsTable, summaryTable, recommendationsTable
 	if compCmdState.Details {
 		mainReport.WriteString(buildComplianceReportRecommandations(recommendationsTable))
 		mainReport.WriteString("\n")
-		mainReport.WriteString(
-			"Try using '--pdf' to download the report in PDF format.",
-		)
-		mainReport.WriteString("\n")
-	} else {
+		if !cli.Quiet() {
+			mainReport.WriteString(
+				"Try using '--pdf' to download the report in PDF format.",
+			)
+			mainReport.WriteString("\n")
+		}
+	} else if !cli.Quiet() {
 		mainReport.WriteString(
 			"Try using '--details' to increase details shown about the compliance report.\n",
 		)
@@ -103,8 +103,8 @@ To run an ad-hoc compliance assessment use the command:
 				return errors.New("there is no data found in the report")
 			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response.Data[0])
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(response.Data[0])
 			}
 
 			report := response.Data[0]
@@ -133,8 +133,8 @@ To run an ad-hoc compliance assessment use the command:
 				return errors.Wrap(err, "unable to run aws compliance report")
 			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response)
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(response)
 			}
 
 			cli.OutputHuman("A new AWS compliance report has been initiated.\n")
@@ -190,6 +190,6 @@ func complianceAwsReportDetailsTable(report *api.ComplianceAwsReport) [][]string
 		[]string{"Report Title", report.ReportTitle},
 		[]string{"Account ID", report.AccountID},
 		[]string{"Account Alias", report.AccountAlias},
-		[]string{"Report Time", report.ReportTime.UTC().Format(time.RFC3339)},
+		[]string{"Report Time", cli.FormatTime(report.ReportTime)},
 	}
 }
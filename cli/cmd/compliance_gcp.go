@@ -53,8 +53,8 @@ Then, select one GUID from an integration and visualize its details using the co
 				return errors.Wrap(err, "unable to list gcp projects")
 			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response.Data[0])
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(response.Data[0])
 			}
 
 			cli.OutputHuman(buildGcpProjectsTable(response.Data))
@@ -128,8 +128,8 @@ To run an ad-hoc compliance assessment use the command:
 				return errors.New("there is no data found in the report")
 			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response.Data[0])
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(response.Data[0])
 			}
 
 			report := response.Data[0]
@@ -158,8 +158,8 @@ To run an ad-hoc compliance assessment use the command:
 				return errors.Wrap(err, "unable to run gcp compliance assessment")
 			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response)
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(response)
 			}
 
 			cli.OutputHuman("A new GCP compliance assessment has been initiated.\n")
@@ -234,6 +234,6 @@ func complianceGcpReportDetailsTable(report *api.ComplianceGcpReport) [][]string
 		[]string{"Organization Name", report.OrganizationName},
 		[]string{"Project ID", report.ProjectID},
 		[]string{"Project Name", report.ProjectName},
-		[]string{"Report Time", report.ReportTime.UTC().Format(time.RFC3339)},
+		[]string{"Report Time", cli.FormatTime(report.ReportTime)},
 	}
 }
@@ -19,58 +19,27 @@
 package cmd
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
-	"path"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/BurntSushi/toml"
-	homedir "github.com/mitchellh/go-homedir"
 	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-)
-
-// Profiles is the representation of the ~/.lacework.toml
-//
-// Example:
-//
-// [default]
-// account = "example"
-// api_key = "EXAMPLE_0123456789"
-// api_secret = "_0123456789"
-//
-// [dev]
-// account = "dev"
-// api_key = "DEV_0123456789"
-// api_secret = "_0123456789"
-type Profiles map[string]credsDetails
-
-type credsDetails struct {
-	Account   string `toml:"account" json:"account"`
-	ApiKey    string `toml:"api_key" json:"api_key" survey:"api_key"`
-	ApiSecret string `toml:"api_secret" json:"api_secret" survey:"api_secret"`
-}
 
-func (c *credsDetails) Verify() error {
-	if c.Account == "" {
-		return errors.New("account missing")
-	}
-	if c.ApiKey == "" {
-		return errors.New("api_key missing")
-	}
-	if c.ApiSecret == "" {
-		return errors.New("api_secret missing")
-	}
-	return nil
-}
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwconfig"
+)
 
 // apiKeyDetails represents the details of an API key, we use this struct
 // internally to unmarshal the JSON file provided by the Lacework WebUI
@@ -80,8 +49,36 @@ type apiKeyDetails struct {
 }
 
 var (
-	// configureJsonFile is the API key file downloaded form the Lacework WebUI
-	configureJsonFile string
+	// configureJsonFiles are the API key files downloaded form the Lacework WebUI,
+	// when more than one file is provided, we configure one profile per file
+	configureJsonFiles []string
+
+	// configureProfilePrefix is prepended to the profiles created from API key files
+	configureProfilePrefix string
+
+	// configureSecretStdin reads the API secret from the standard input
+	configureSecretStdin bool
+
+	// configureLenient skips the length validation of legacy API keys
+	configureLenient bool
+
+	// configureApiURL is a custom API base URL, for isolated regions or test environments
+	configureApiURL string
+
+	// configureNoBackup disables the backup of the config file before overwriting it
+	configureNoBackup bool
+
+	// configureMaxBackups is the number of config backups to keep
+	configureMaxBackups int
+
+	// configureForce overwrites an existing profile without confirmation
+	configureForce bool
+
+	// configureDryRun prints the config file that would be written instead of writing it
+	configureDryRun bool
+
+	// configureListTableHeaders are the headers of the table and CSV outputs of profiles
+	configureListTableHeaders = []string{"Profile", "Account", "API Key", "API Secret"}
 
 	// configureCmd represents the configure command
 	configureCmd = &cobra.Command{
@@ -98,6 +95,65 @@ download the generated API key file.
 
 Use the flag --json_file to preload the downloaded API key file.
 
+To configure multiple accounts at once, repeat the flag --json_file or pass
+a glob, one profile per file will be created or updated. The profile name is
+derived from the file name, use the flag --profile-prefix to prepend a prefix
+to the profile names. The account is the one of the flag --account, without
+it, you are prompted for the account of every file:
+
+    $ lacework configure --json_file 'keys/*.json' --profile-prefix org- --account my-org
+
+Updating existing profiles asks for confirmation, use the flag --force to
+update them without prompts.
+
+To configure the Lacework CLI without any prompt, useful for provisioning
+automation and container images, provide the account, API key and secret
+via flags (or the account plus the API key file):
+
+    $ lacework configure --account my-account --api_key X --api_secret Y
+
+The API access key and secret are validated to have at least 55 and 30
+characters respectively, use the flag --lenient for legacy API keys.
+
+Organization admins can configure the sub-account to interact with by
+using the flag --subaccount.
+
+For isolated regions or test environments where the API does not live at
+https://<ACCOUNT>.lacework.net, use the flag --api-url.
+
+Before overwriting the config file, a timestamped backup is written next to
+it (i.e. ~/.lacework.toml.bak-<timestamp>), only the latest backups are kept,
+use the flags --no-backup and --max-backups to control this behavior.
+
+To provision the Lacework CLI on EC2 instances or ECS tasks without copying
+API key files around, store the API key JSON file in AWS Secrets Manager and
+use the flag --from-aws-secret, the secret is fetched with the AWS SDK using
+the default credential chain (environment variables, config files or roles):
+
+    $ lacework configure --account my-account \
+        --from-aws-secret arn:aws:secretsmanager:us-west-2:123456789012:secret:lacework
+
+To avoid the secret being echoed or stored in your shell history, use the
+flag --secret-stdin to read it from the standard input:
+
+    $ cat secret.txt | lacework configure --account my-account --api_key X --secret-stdin
+
+To preview the changes without touching the config file, use the flag
+--dry-run, the credentials are verified and the config file that would be
+written is printed with the API secrets masked:
+
+    $ lacework configure --account my-account --api_key X --api_secret Y --dry-run
+
+When the profile already exists, you are asked to confirm before updating
+it. If there are no prompts (i.e. with --noninteractive or when all values
+are provided via flags), use the flag --force to overwrite it.
+
+To confirm what was written, for example from provisioning automation, use
+the flag --json to display the configured profile (with a masked secret)
+instead of the success message:
+
+    $ lacework configure --account my-account --api_key X --api_secret Y --json
+
 If this command is run with no flags, the Lacework CLI will store all
 settings under the default profile. The information in the default profile
 is used any time you run a Lacework CLI command that doesn't explicitly
@@ -117,8 +173,18 @@ the Lacework CLI will create it for you.`,
 		Args:  cobra.NoArgs,
 		Long: `List all profiles configured into the config file ~/.lacework.toml
 
-To switch to a different profile permanently in your current terminal,
-export the environment variable:
+API keys and secrets are masked. Use the flag --output to display the
+profiles in a different format like json, csv or yaml.
+
+Problems found in the config file, like profiles with missing settings,
+are reported after the list of profiles.
+
+To switch to a different profile permanently, set it as the default profile:
+
+    $ lacework configure default my-profile
+
+To switch to a different profile only in your current terminal, export the
+environment variable:
 
     ` + configureListCmdSetProfileEnv,
 		RunE: func(_ *cobra.Command, _ []string) error {
@@ -127,26 +193,248 @@ export the environment variable:
 				return err
 			}
 
+			if cli.CSVOutput() {
+				return cli.OutputCSV(configureListTableHeaders,
+					buildProfilesCSVContent(profiles))
+			}
+
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(buildProfilesJSONContent(cli.Profile, profiles))
+			}
+
+			cli.OutputHuman(cli.RenderTable(
+				configureListTableHeaders,
+				buildProfilesTableContent(cli.Profile, profiles),
+				withTableAlignment(tablewriter.ALIGN_LEFT),
+			))
+
+			// surface every problem of the config file at once, instead of
+			// failing later when a broken profile is used
+			config := lwconfig.Config{Profiles: profiles}
+			if err := config.ValidateWithDefault(cli.Profile); err != nil {
+				cli.OutputHuman("\n%s\n", err)
+			}
+			return nil
+		},
+	}
+
+	configureTestCmd = &cobra.Command{
+		Use:   "test [profile]",
+		Short: "test the credentials of a profile against the Lacework API",
+		Args:  cobra.MaximumNArgs(1),
+		Long: `Validates that the account, API key and secret of a profile are correct by
+generating an access token against the Lacework API.
+
+If no profile is provided, the active profile is tested.
+
+    $ lacework configure test my-profile`,
+		RunE: func(_ *cobra.Command, args []string) error {
 			var (
-				strBuilder = &strings.Builder{}
-				table      = tablewriter.NewWriter(strBuilder)
+				name  = cli.Profile
+				creds = lwconfig.ProfileDetails{
+					Account:    cli.Account,
+					Subaccount: cli.Subaccount,
+					ApiKey:     cli.KeyID,
+					ApiSecret:  cli.Secret,
+					ApiURL:     cli.ApiURL,
+					CACert:     cli.CACert,
+					Timeout:    int(cli.Timeout / time.Second),
+				}
 			)
 
-			table.SetBorder(false)
-			table.SetAlignment(tablewriter.ALIGN_LEFT)
-			table.SetHeader([]string{"Profile", "Account", "API Key", "API Secret"})
-			table.AppendBulk(buildProfilesTableContent(cli.Profile, profiles))
-			table.Render()
+			if len(args) != 0 && args[0] != cli.Profile {
+				profiles, err := cli.LoadProfiles()
+				if err != nil {
+					return err
+				}
+
+				var ok bool
+				name = args[0]
+				creds, ok = profiles[name]
+				if !ok {
+					return profileNotFoundError(name)
+				}
+			}
+
+			if err := creds.Verify(); err != nil {
+				return errors.Wrapf(err, "the profile '%s' is not configured correctly", name)
+			}
+
+			return testProfileCredentials(name, creds)
+		},
+	}
+
+	// configureRenameForce overwrites the new profile if it already exists
+	configureRenameForce bool
+
+	configureRenameCmd = &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "rename a profile from the config file ~/.lacework.toml",
+		Args:  cobra.ExactArgs(2),
+		Long: `Renames a profile without losing its account, API key and secret.
+
+If the new profile already exists, use the flag --force to overwrite it.
+
+    $ lacework configure rename dev staging`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			var (
+				oldName = args[0]
+				newName = args[1]
+			)
+
+			profiles, err := cli.LoadProfiles()
+			if err != nil {
+				return err
+			}
+
+			if _, ok := profiles[oldName]; !ok {
+				return profileNotFoundError(oldName)
+			}
 
-			cli.OutputHuman(strBuilder.String())
+			if _, exist := profiles[newName]; exist && !configureRenameForce {
+				return errors.Errorf(
+					"the profile '%s' already exists. Use --force to overwrite it", newName,
+				)
+			}
+
+			confPath := viper.ConfigFileUsed()
+			config, err := loadStoredConfig(confPath)
+			if err != nil {
+				return err
+			}
+
+			// the stored default profile follows the renamed profile
+			renamedDefault := config.DefaultProfile == oldName
+			config.Profiles = profiles
+			if err := config.RenameProfile(oldName, newName); err != nil {
+				return errors.Wrap(err, "unable to rename profile")
+			}
+
+			cli.Log.Debugw("renaming profile", "old", oldName, "new", newName)
+			if err := storeConfig(confPath, config); err != nil {
+				return errors.Wrap(err, "unable to rename profile")
+			}
+
+			cli.OutputHuman("Profile '%s' renamed to '%s'.\n", oldName, newName)
+			if renamedDefault {
+				cli.OutputHuman("Profile '%s' is now the default profile.\n", newName)
+			} else if oldName == cli.Profile && !cli.Quiet() {
+				cli.OutputHuman(
+					"\nNOTE: '%s' was the active profile, update your --profile flag or LW_PROFILE\n"+
+						"environment variable to keep using it:\n\n    %s\n",
+					oldName, strings.Replace(configureListCmdSetProfileEnv, "my-profile", newName, 1),
+				)
+			}
 			return nil
 		},
 	}
 
-	configureGetCmd = &cobra.Command{
-		Use:   "show <config_key>",
-		Short: "show current configuration data",
+	configureDefaultCmd = &cobra.Command{
+		Use:   "default <profile>",
+		Short: "set the default profile of the config file ~/.lacework.toml",
 		Args:  cobra.ExactArgs(1),
+		Long: `Sets the profile to use when no profile is selected, so that subsequent commands
+use it without passing the flag --profile.
+
+    $ lacework configure default dev
+
+The order of precedence to select the profile is the flag --profile, the
+environment variable LW_PROFILE (or its alias LACEWORK_PROFILE), the default
+profile set with this command, and the profile named 'default'.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			confPath, err := cli.ConfigFilePath()
+			if err != nil {
+				return err
+			}
+
+			profiles, err := cli.LoadProfiles()
+			if err != nil {
+				return err
+			}
+
+			config, err := loadStoredConfig(confPath)
+			if err != nil {
+				return err
+			}
+
+			config.Profiles = profiles
+			if err := config.SetDefaultProfile(args[0]); err != nil {
+				return profileNotFoundError(args[0])
+			}
+
+			cli.Log.Debugw("setting default profile", "profile", args[0])
+			if err := storeConfig(confPath, config); err != nil {
+				return errors.Wrap(err, "unable to set default profile")
+			}
+
+			cli.OutputHuman("Profile '%s' is now the default profile.\n", args[0])
+			if envProfile, envVariable := lwconfig.EnvProfile(); envProfile != "" && envProfile != args[0] && !cli.Quiet() {
+				cli.OutputHuman(
+					"\nNOTE: the environment variable %s is set to '%s', it takes\n"+
+						"precedence over the default profile, unset it to use '%s'.\n",
+					envVariable, envProfile, args[0],
+				)
+			}
+			return nil
+		},
+	}
+
+	configureUpdatesCmd = &cobra.Command{
+		Use:       "updates [on|off]",
+		Short:     "turn on or off the check for updates of the Lacework CLI",
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: []string{"on", "off"},
+		Long: `Turns on or off the check for updates of the Lacework CLI, the setting is
+stored in the config file ~/.lacework.toml and the check is on by default.
+
+    $ lacework configure updates off
+
+Without arguments, the current value of the setting is displayed.
+
+The environment variable LW_UPDATES_DISABLE=1 always turns off the check.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			confPath, err := cli.ConfigFilePath()
+			if err != nil {
+				return err
+			}
+
+			config, err := loadStoredConfig(confPath)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				cli.OutputHuman("%s\n", onOff(config.UpdatesEnabled()))
+				return nil
+			}
+
+			switch args[0] {
+			case "on":
+				config.SetUpdates(true)
+			case "off":
+				config.SetUpdates(false)
+			default:
+				return errors.Errorf("invalid value '%s', use 'on' or 'off'", args[0])
+			}
+
+			cli.Log.Debugw("setting updates", "updates", args[0])
+			if err := storeConfig(confPath, config); err != nil {
+				return errors.Wrap(err, "unable to set updates")
+			}
+
+			cli.OutputHuman("The check for updates is now %s.\n", args[0])
+			return nil
+		},
+	}
+
+	// configureShowSecret reveals the full secret when showing a profile
+	configureShowSecret bool
+
+	configureGetCmd = &cobra.Command{
+		Use:       "show [config_key]",
+		Short:     "show current configuration data",
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: configureShowKeys,
 		Long: `Prints the current computed configuration data from the specified configuration
 key. The order of precedence to compute the configuration is flags, environment
 variables, and the configuration file ~/.lacework.toml. 
@@ -154,22 +442,38 @@ variables, and the configuration file ~/.lacework.toml.
 The available configuration keys are:
 * profile
 * account
+* subaccount
 * api_secret
 * api_key
 
 To show the configuration from a different profile, use the flag --profile.
 
-    $ lacework configure show account --profile my-profile`,
+    $ lacework configure show account --profile my-profile
+
+To show all the details of a profile, use the flag --profile without a
+configuration key, or no flag at all to show the details of the active
+profile. The secret is masked by default, use the flag --show-secret to
+reveal it.
+
+    $ lacework configure show --profile my-profile --show-secret`,
 		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return showProfileDetails(cli.Profile)
+			}
+
 			data, ok := showConfigurationDataFromKey(args[0])
 			if !ok {
-				return errors.New("unknown configuration key. (available: profile, account, api_secret, api_key)")
+				return withExitCode(errors.Errorf(
+					"unknown configuration key '%s'. (available: %s)\n\n"+
+						"To show the details of a profile, use the flag --profile.",
+					args[0], strings.Join(configureShowKeys, ", "),
+				), exitCodeUsage)
 			}
 
 			if data == "" {
 				// @afiune something is not set correctly, here is a big
 				// exeption to exit the CLI in a non-standard way
-				os.Exit(1)
+				os.Exit(exitCodeError)
 			}
 
 			cli.OutputHuman(data)
@@ -179,12 +483,17 @@ To show the configuration from a different profile, use the flag --profile.
 	}
 )
 
+// configureShowKeys are the configuration keys of the 'configure show' command
+var configureShowKeys = []string{"profile", "account", "subaccount", "api_secret", "api_key"}
+
 func showConfigurationDataFromKey(key string) (string, bool) {
 	switch key {
 	case "profile":
 		return cli.Profile, true
 	case "account":
 		return cli.Account, true
+	case "subaccount":
+		return cli.Subaccount, true
 	case "api_secret":
 		return cli.Secret, true
 	case "api_key":
@@ -198,10 +507,173 @@ func init() {
 	rootCmd.AddCommand(configureCmd)
 	configureCmd.AddCommand(configureListCmd)
 	configureCmd.AddCommand(configureGetCmd)
+	configureCmd.AddCommand(configureTestCmd)
+	configureCmd.AddCommand(configureRenameCmd)
+	configureCmd.AddCommand(configureDefaultCmd)
+	configureCmd.AddCommand(configureUpdatesCmd)
+	configureCmd.AddCommand(configureImportCmd)
+	configureCmd.AddCommand(configureVerifyCmd)
+
+	configureCmd.Flags().StringArrayVarP(&configureJsonFiles,
+		"json_file", "j", []string{},
+		"loads the generated API key JSON file from the WebUI (repeatable, accepts globs)",
+	)
+	configureCmd.Flags().StringVar(&configureAwsSecret,
+		"from-aws-secret", "", "loads the API key JSON file from an AWS Secrets Manager secret ARN",
+	)
+	configureCmd.Flags().StringVar(&configureProfilePrefix,
+		"profile-prefix", "", "prefix for the profiles created from multiple API key files",
+	)
+	configureCmd.Flags().BoolVar(&configureSecretStdin,
+		"secret-stdin", false, "read the API secret from the standard input",
+	)
+	configureCmd.PersistentFlags().BoolVar(&configureNoBackup,
+		"no-backup", false, "do not back up the config file before overwriting it",
+	)
+	configureCmd.PersistentFlags().IntVar(&configureMaxBackups,
+		"max-backups", lwconfig.DefaultMaxBackups, "number of config file backups to keep",
+	)
+	configureCmd.Flags().StringVar(&configureApiURL,
+		"api-url", "", "custom API base URL (default https://<ACCOUNT>.lacework.net)",
+	)
+	configureCmd.Flags().BoolVar(&configureLenient,
+		"lenient", false, "skip the length validation of legacy API keys and secrets",
+	)
+	configureCmd.Flags().BoolVar(&configureForce,
+		"force", false, "overwrite an existing profile without asking for confirmation",
+	)
+	configureCmd.Flags().BoolVar(&configureDryRun,
+		"dry-run", false, "print the config file that would be written without modifying it",
+	)
+
+	configureVerifyCmd.Flags().BoolVar(&configureVerifyFixPerms,
+		"fix-perms", false, "make the config file only readable by you (0600)",
+	)
 
-	configureCmd.Flags().StringVarP(&configureJsonFile,
-		"json_file", "j", "", "loads the generated API key JSON file from the WebUI",
+	configureImportCmd.Flags().StringVar(&configureImportOnConflict,
+		"on-conflict", "", "what to do when a profile already exists (keep or overwrite)",
 	)
+
+	configureRenameCmd.Flags().BoolVar(&configureRenameForce,
+		"force", false, "overwrite the new profile if it already exists",
+	)
+
+	configureGetCmd.Flags().BoolVar(&configureShowSecret,
+		"show-secret", false, "reveal the full API secret when showing a profile",
+	)
+}
+
+// profileNotFoundError returns the error of a profile that doesn't exist
+// in the config file, annotated with the not found exit code
+func profileNotFoundError(name string) error {
+	return withExitCode(
+		errors.Errorf("the profile '%s' could not be found", name), exitCodeNotFound,
+	)
+}
+
+// showProfileDetails prints all the details of the provided profile
+func showProfileDetails(name string) error {
+	profiles, err := cli.LoadProfiles()
+	if err != nil {
+		return err
+	}
+
+	creds, ok := profiles[name]
+	if !ok {
+		return profileNotFoundError(name)
+	}
+
+	secret := formatSecret(4, creds.ApiSecret)
+	if configureShowSecret {
+		secret = creds.ApiSecret
+	}
+
+	if cli.StructuredOutput() {
+		return cli.OutputStructured(profileJSON{
+			Profile:    name,
+			Account:    creds.Account,
+			Subaccount: creds.Subaccount,
+			ApiKey:     creds.ApiKey,
+			ApiSecret:  secret,
+			ApiURL:     creds.ApiURL,
+			CACert:     creds.CACert,
+			Active:     name == cli.Profile,
+		})
+	}
+
+	details := [][]string{
+		[]string{"Profile", name},
+		[]string{"Account", creds.Account},
+	}
+
+	if creds.Subaccount != "" {
+		details = append(details, []string{"Subaccount", creds.Subaccount})
+	}
+	if creds.ApiURL != "" {
+		details = append(details, []string{"API URL", creds.ApiURL})
+	}
+	if creds.CACert != "" {
+		details = append(details, []string{"CA Certificate", creds.CACert})
+	}
+
+	cli.OutputHuman(cli.RenderTable(nil,
+		append(details,
+			[]string{"API Key", creds.ApiKey},
+			[]string{"API Secret", secret},
+		),
+		withTableColumnSeparator(""),
+		withTableAlignment(tablewriter.ALIGN_LEFT),
+	))
+	return nil
+}
+
+// testProfileCredentials verifies the provided credentials by generating
+// a new access token, this is the cheapest authenticated API call we have
+func testProfileCredentials(name string, creds lwconfig.ProfileDetails) error {
+	client, err := newProfileClient(creds)
+	if err != nil {
+		return errors.Wrap(err, "unable to generate api client")
+	}
+
+	cli.StartProgress(" Verifying credentials...")
+	_, err = client.GenerateToken()
+	cli.StopProgress()
+	if err != nil {
+		return errors.Wrapf(err, "unable to authenticate profile '%s'", name)
+	}
+
+	if cli.StructuredOutput() {
+		return cli.OutputStructured(struct {
+			Profile string `json:"profile"`
+			Account string `json:"account"`
+			Valid   bool   `json:"valid"`
+		}{name, creds.Account, true})
+	}
+
+	cli.OutputHuman("The credentials of the profile '%s' (account: %s) are valid.\n",
+		name, creds.Account)
+	return nil
+}
+
+// newProfileClient generates a new api client from the details of a profile,
+// the provided options are appended to the ones generated from the profile
+func newProfileClient(creds lwconfig.ProfileDetails, extraOpts ...api.Option) (*api.Client, error) {
+	opts := []api.Option{
+		api.WithLogLevel(cli.apiLogLevel()),
+		api.WithSubaccount(creds.Subaccount),
+		api.WithApiKeys(creds.ApiKey, creds.ApiSecret),
+		api.WithUserAgent(userAgent()),
+	}
+	if creds.ApiURL != "" {
+		opts = append(opts, api.WithURL(creds.ApiURL))
+	}
+	if creds.Timeout != 0 {
+		opts = append(opts, api.WithTimeout(time.Duration(creds.Timeout)*time.Second))
+	}
+	opts = append(opts, cli.concurrencyOptions()...)
+	opts = append(opts, cli.tlsOptions(creds.CACert)...)
+
+	return api.NewClient(creds.Account, append(opts, extraOpts...)...)
 }
 
 func promptConfigureSetup() error {
@@ -210,14 +682,23 @@ func promptConfigureSetup() error {
 	// make sure that the state is loaded to use during configuration
 	cli.loadStateFromViper()
 
+	jsonFiles, err := expandJsonFiles(configureJsonFiles)
+	if err != nil {
+		return err
+	}
+
+	if len(jsonFiles) > 1 {
+		return configureProfilesFromJsonFiles(jsonFiles)
+	}
+
 	// if the Lacework account is empty, and the profile that is being configured is
 	// not the 'default' profile, auto-populate the account with the provided profile
 	if cli.Account == "" && cli.Profile != "default" {
 		cli.Account = cli.Profile
 	}
 
-	if len(configureJsonFile) != 0 {
-		auth, err := loadKeysFromJsonFile(configureJsonFile)
+	if len(jsonFiles) != 0 {
+		auth, err := loadKeysFromJsonFile(jsonFiles[0])
 		if err != nil {
 			return errors.Wrap(err, "unable to load keys from the provided json file")
 		}
@@ -225,6 +706,33 @@ func promptConfigureSetup() error {
 		cli.Secret = auth.Secret
 	}
 
+	if configureAwsSecret != "" {
+		if len(jsonFiles) != 0 {
+			return errors.New("cannot combine --from-aws-secret with --json_file")
+		}
+
+		auth, err := loadKeysFromAwsSecret(configureAwsSecret)
+		if err != nil {
+			return err
+		}
+		cli.KeyID = auth.KeyID
+		cli.Secret = auth.Secret
+	}
+
+	if configureSecretStdin {
+		secret, err := readSecretFromStdin(os.Stdin)
+		if err != nil {
+			return errors.Wrap(err, "unable to read the API secret from stdin")
+		}
+		cli.Secret = secret
+	}
+
+	if configureApiURL != "" {
+		if err := validateApiURL(configureApiURL); err != nil {
+			return err
+		}
+	}
+
 	questions := []*survey.Question{
 		{
 			Name: "account",
@@ -259,8 +767,9 @@ func promptConfigureSetup() error {
 				Message: "Access Key ID:",
 				Default: cli.KeyID,
 			},
-			Validate: promptRequiredStringLen(55,
-				"The API access key id must have more than 55 characters.",
+			Validate: promptRequiredStringLen(configureMinLength(lwconfig.ApiKeyMinLength),
+				fmt.Sprintf("The API access key id must have more than %d characters.",
+					configureMinLength(lwconfig.ApiKeyMinLength)),
 			),
 		},
 	}
@@ -268,17 +777,49 @@ func promptConfigureSetup() error {
 	secretQuest := &survey.Question{
 		Name: "api_secret",
 		Validate: func(input interface{}) error {
+			minLength := configureMinLength(lwconfig.ApiSecretMinLength)
 			str, ok := input.(string)
-			if !ok || len(str) < 30 {
+			if !ok || len(str) < minLength {
 				if len(str) == 0 && len(cli.Secret) != 0 {
 					return nil
 				}
-				return errors.New("The API secret access key must have more than 30 characters.")
+				return errors.Errorf(
+					"The API secret access key must have more than %d characters.", minLength,
+				)
 			}
 			return nil
 		},
 	}
 
+	confPath, profiles, err := loadProfilesToConfigure()
+	if err != nil {
+		return err
+	}
+
+	promptsEnabled := cli.InteractiveMode() && !configureValuesFromFlags()
+	// a dry run does not modify the profile, there is nothing to confirm
+	if _, exists := profiles[cli.Profile]; exists && !configureForce && !configureDryRun {
+		if !promptsEnabled {
+			return errors.Errorf(
+				"profile '%s' already exists, use --force to overwrite it", cli.Profile,
+			)
+		}
+
+		update := false
+		err := survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Profile '%s' exists, update it?", cli.Profile),
+			Default: true,
+		}, &update, survey.WithIcons(promptIconsFunc))
+		if err != nil {
+			return err
+		}
+
+		if !update {
+			cli.OutputHuman("The profile '%s' was not modified.\n", cli.Profile)
+			return nil
+		}
+	}
+
 	secretMessage := "Secret Access Key:"
 	if len(cli.Secret) != 0 {
 		secretMessage = fmt.Sprintf("Secret Access Key: (%s)", formatSecret(4, cli.Secret))
@@ -287,9 +828,14 @@ func promptConfigureSetup() error {
 		Message: secretMessage,
 	}
 
-	newCreds := credsDetails{}
-	if cli.InteractiveMode() {
-		err := survey.Ask(append(questions, secretQuest), &newCreds,
+	newCreds := lwconfig.ProfileDetails{}
+	if promptsEnabled {
+		// the secret was already read from stdin, there is no need to ask for it
+		if !configureSecretStdin {
+			questions = append(questions, secretQuest)
+		}
+
+		err := survey.Ask(questions, &newCreds,
 			survey.WithIcons(promptIconsFunc),
 		)
 		if err != nil {
@@ -306,47 +852,399 @@ func promptConfigureSetup() error {
 		newCreds.ApiSecret = cli.Secret
 	}
 
-	if err := newCreds.Verify(); err != nil {
+	setProfileSettingsFromFlags(&newCreds)
+
+	if err := verifyProfileDetails(newCreds); err != nil {
 		return errors.Wrap(err, "unable to configure the command-line")
 	}
 
+	profiles[cli.Profile] = newCreds
+	if configureDryRun {
+		return previewProfiles(confPath, profiles)
+	}
+	if err := storeProfiles(confPath, profiles); err != nil {
+		return err
+	}
+
+	if cli.StructuredOutput() {
+		return cli.OutputStructured(newProfileJSON(cli.Profile, newCreds, true))
+	}
+
+	if !cli.Quiet() {
+		cli.OutputHuman("You are all set!\n")
+	}
+	return nil
+}
+
+// setProfileSettingsFromFlags sets the settings of the profile that are not
+// prompted for, like the subaccount, only used by organizational accounts,
+// and the API URL, only needed for isolated regions or test environments,
+// they are configured via flags or environment variables, like LW_DOMAIN
+func setProfileSettingsFromFlags(creds *lwconfig.ProfileDetails) {
+	creds.Subaccount = cli.Subaccount
+	creds.ApiURL = cli.ApiURL
+	creds.Domain = cli.Domain
+	creds.CACert = cli.CACert
+	creds.Timeout = int(cli.Timeout / time.Second)
+	if configureApiURL != "" {
+		creds.ApiURL = strings.TrimSuffix(configureApiURL, "/")
+	}
+}
+
+// loadProfilesToConfigure returns the path of the config file and its profiles,
+// if the config file does not exist, it returns the path where to generate it
+func loadProfilesToConfigure() (string, lwconfig.Profiles, error) {
+	confPath, err := cli.ConfigFilePath()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if _, err := os.Stat(confPath); err == nil {
+		profiles, err := cli.LoadProfiles()
+		return confPath, profiles, err
+	}
+
+	cli.Log.Debugw("generating new config file",
+		"path", confPath,
+	)
+	return confPath, lwconfig.Profiles{}, nil
+}
+
+// expandJsonFiles expands the glob patterns of the provided API key files
+func expandJsonFiles(patterns []string) ([]string, error) {
+	files := []string{}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid json file pattern '%s'", pattern)
+		}
+
+		if len(matches) == 0 {
+			// not a glob, or a glob without matches, keep it as is to
+			// surface the error when we try to read the file
+			files = append(files, pattern)
+			continue
+		}
+
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// configureProfilesFromJsonFiles creates or updates one profile per API key file,
+// the profile name is derived from the name of the file, and the account is the
+// one of the flag --account, or the one the user is prompted for
+func configureProfilesFromJsonFiles(files []string) error {
+	if configureApiURL != "" {
+		if err := validateApiURL(configureApiURL); err != nil {
+			return err
+		}
+	}
+
+	confPath, profiles, err := loadProfilesToConfigure()
+	if err != nil {
+		return err
+	}
+
 	var (
-		profiles = Profiles{}
-		confPath = viper.ConfigFileUsed()
-		buf      = new(bytes.Buffer)
-		err      error
+		rows       = [][]string{}
+		configured = []profileJSON{}
+		existing   = []string{}
 	)
-	if confPath == "" {
-		home, err := homedir.Dir()
+	for _, file := range files {
+		auth, err := loadKeysFromJsonFile(file)
+		if err != nil {
+			return errors.Wrapf(err, "unable to load keys from json file '%s'", file)
+		}
+
+		name := configureProfilePrefix + strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		account, err := jsonFileAccount(file, profiles[name])
 		if err != nil {
 			return err
 		}
-		confPath = path.Join(home, ".lacework.toml")
-		cli.Log.Debugw("generating new config file",
-			"path", confPath,
-		)
-	} else {
-		profiles, err = cli.LoadProfiles()
+
+		creds := lwconfig.ProfileDetails{
+			Account:   account,
+			ApiKey:    auth.KeyID,
+			ApiSecret: auth.Secret,
+		}
+		setProfileSettingsFromFlags(&creds)
+		if err := verifyProfileDetails(creds); err != nil {
+			return errors.Wrapf(err, "invalid API keys in json file '%s'", file)
+		}
+
+		action := "created"
+		if _, exist := profiles[name]; exist {
+			action = "updated"
+			existing = append(existing, name)
+		}
+
+		profiles[name] = creds
+		rows = append(rows, []string{name, account, action, file})
+		configured = append(configured, newProfileJSON(name, creds, name == cli.Profile))
+	}
+
+	// a dry run does not modify the profiles, there is nothing to confirm
+	if len(existing) != 0 && !configureForce && !configureDryRun {
+		update, err := confirmUpdateProfiles(existing)
 		if err != nil {
 			return err
 		}
+
+		if !update {
+			cli.OutputHuman("The profiles were not modified.\n")
+			return nil
+		}
 	}
 
-	profiles[cli.Profile] = newCreds
-	cli.Log.Debugw("storing updated profiles", "profiles", profiles)
-	if err := toml.NewEncoder(buf).Encode(profiles); err != nil {
+	if configureDryRun {
+		return previewProfiles(confPath, profiles)
+	}
+	if err := storeProfiles(confPath, profiles); err != nil {
+		return err
+	}
+
+	if cli.StructuredOutput() {
+		return cli.OutputStructured(configured)
+	}
+
+	cli.OutputHuman(cli.RenderTable(
+		[]string{"Profile", "Account", "Status", "File"},
+		rows,
+		withTableAlignment(tablewriter.ALIGN_LEFT),
+	))
+	if !cli.Quiet() {
+		cli.OutputHuman("\nYou are all set! %d profiles configured.\n", len(rows))
+	}
+	return nil
+}
+
+// jsonFileAccount returns the account of the profile of an API key file, the
+// file name says nothing about the account, so it is the one of the flag
+// --account (or LW_ACCOUNT), otherwise, the user is prompted for it
+func jsonFileAccount(file string, current lwconfig.ProfileDetails) (string, error) {
+	if account := viper.GetString("account"); account != "" {
+		return account, nil
+	}
+
+	if !cli.InteractiveMode() || !stdoutIsTerminal() {
+		return "", errors.Errorf(
+			"the account of the API key file '%s' is required, use --account", file,
+		)
+	}
+
+	account := ""
+	err := survey.AskOne(&survey.Input{
+		Message: fmt.Sprintf("Account of '%s':", filepath.Base(file)),
+		Default: current.Account,
+	}, &account, survey.WithValidator(survey.Required), survey.WithIcons(promptIconsFunc))
+	return account, err
+}
+
+// confirmUpdateProfiles asks the user to confirm the update of the provided
+// existing profiles, when prompts are not possible, --force is required
+func confirmUpdateProfiles(names []string) (bool, error) {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("'%s'", name)
+	}
+
+	if !cli.InteractiveMode() || !stdoutIsTerminal() {
+		return false, errors.Errorf(
+			"profiles %s already exist, use --force to overwrite them", strings.Join(quoted, ", "),
+		)
+	}
+
+	update := false
+	err := survey.AskOne(&survey.Confirm{
+		Message: fmt.Sprintf("Profiles %s exist, update them?", strings.Join(quoted, ", ")),
+		Default: true,
+	}, &update, survey.WithIcons(promptIconsFunc))
+	return update, err
+}
+
+// storeProfiles writes the provided profiles into the config file, the
+// global settings stored in the config file, like the default profile,
+// are preserved
+func storeProfiles(confPath string, profiles lwconfig.Profiles) error {
+	if err := validateProfileNames(profiles); err != nil {
+		return err
+	}
+
+	config, err := loadStoredConfig(confPath)
+	if err != nil {
+		return err
+	}
+
+	config.Profiles = profiles
+	return storeConfig(confPath, config)
+}
+
+// validateProfileNames verifies that the names of the provided profiles can
+// be stored in the config file, the names of its global settings are reserved
+func validateProfileNames(profiles lwconfig.Profiles) error {
+	for name := range profiles {
+		if err := lwconfig.ValidateProfileName(name); err != nil {
+			return withExitCode(err, exitCodeUsage)
+		}
+	}
+	return nil
+}
+
+// loadStoredConfig returns the config stored in the provided file, or an
+// empty config if the file does not exist, a config that cannot be loaded
+// is an error, since writing an empty one back would wipe every profile
+func loadStoredConfig(confPath string) (lwconfig.Config, error) {
+	if _, err := os.Stat(confPath); err != nil {
+		if os.IsNotExist(err) {
+			return lwconfig.Config{Profiles: lwconfig.Profiles{}}, nil
+		}
+		return lwconfig.Config{}, errors.Wrap(err, "unable to load config file")
+	}
+
+	config, err := lwconfig.LoadFromFile(confPath)
+	if err != nil {
+		cli.Log.Debugw("unable to load stored config", "path", confPath, "error", err)
+		return config, errors.Wrapf(err, "unable to load config file %s", confPath)
+	}
+	return config, nil
+}
+
+// previewProfiles prints the config file that storeProfiles() would write,
+// with the API secrets masked, without modifying the config file
+func previewProfiles(confPath string, profiles lwconfig.Profiles) error {
+	if err := validateProfileNames(profiles); err != nil {
 		return err
 	}
 
-	err = ioutil.WriteFile(confPath, buf.Bytes(), 0600)
+	config, err := loadStoredConfig(confPath)
 	if err != nil {
 		return err
 	}
 
-	cli.OutputHuman("You are all set!\n")
+	config.Profiles = lwconfig.Profiles{}
+	for name, profile := range profiles {
+		profile.ApiSecret = formatSecret(4, profile.ApiSecret)
+		config.Profiles[name] = profile
+	}
+	pruneDefaultProfile(&config)
+
+	data, err := config.Encode()
+	if err != nil {
+		return err
+	}
+
+	// the note is a TOML comment, the output is still a valid config file
+	if !cli.Quiet() {
+		fmt.Fprintf(os.Stdout, "# dry run, the config file %s was not modified\n", confPath)
+	}
+	fmt.Fprint(os.Stdout, string(data))
+	return nil
+}
+
+// pruneDefaultProfile removes the default profile from the provided
+// config if the profile no longer exists
+func pruneDefaultProfile(config *lwconfig.Config) {
+	if _, ok := config.Profiles[config.DefaultProfile]; !ok && config.DefaultProfile != "" {
+		cli.Log.Debugw("removing missing default profile", "profile", config.DefaultProfile)
+		config.DefaultProfile = ""
+	}
+}
+
+// storeConfig writes the provided config into the config file, if the
+// default profile no longer exists, it is removed from the config
+func storeConfig(confPath string, config lwconfig.Config) error {
+	if confPath == "" {
+		return errors.New("unable to store profiles. No configuration file found.")
+	}
+
+	pruneDefaultProfile(&config)
+
+	if !configureNoBackup {
+		backupPath, err := lwconfig.BackupFile(confPath, configureMaxBackups)
+		if err != nil {
+			return err
+		}
+		cli.Log.Debugw("config file backed up", "path", backupPath)
+	}
+
+	cli.Log.Debugw("storing updated profiles", "path", confPath,
+		"profiles", config.ProfileNames(), "default_profile", config.DefaultProfile,
+	)
+	return config.WriteToFile(confPath)
+}
+
+// onOff returns the provided boolean as "on" or "off"
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// validateApiURL checks that the provided API URL is a valid http(s) URL
+func validateApiURL(apiURL string) error {
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return errors.Errorf("invalid API URL '%s'. (i.e. https://api.example.com)", apiURL)
+	}
 	return nil
 }
 
+// verifyProfileDetails verifies the provided profile details, by default
+// with strict validation, unless the user requested a lenient one
+func verifyProfileDetails(creds lwconfig.ProfileDetails) error {
+	if configureLenient {
+		return creds.Verify()
+	}
+	return creds.VerifyStrict()
+}
+
+// configureMinLength returns the provided minimum length for a setting
+// or one, that is, only required, when the lenient mode is enabled
+func configureMinLength(length int) int {
+	if configureLenient {
+		return 1
+	}
+	return length
+}
+
+// configureValuesFromFlags returns true when the account, API key and secret
+// were all provided via flags (or the API key JSON file, the AWS secret and
+// stdin), in such case there is no need to prompt the user, even on a TTY
+func configureValuesFromFlags() bool {
+	flags := rootCmd.PersistentFlags()
+	if !flags.Changed("account") {
+		return false
+	}
+
+	if len(configureJsonFiles) != 0 || configureAwsSecret != "" {
+		return true
+	}
+
+	return flags.Changed("api_key") &&
+		(flags.Changed("api_secret") || configureSecretStdin)
+}
+
+// readSecretFromStdin reads the API secret from the provided reader,
+// trimming the trailing newline, and validates its length
+func readSecretFromStdin(r io.Reader) (string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	secret := strings.TrimSuffix(string(data), "\n")
+	secret = strings.TrimSuffix(secret, "\r")
+	if minLength := configureMinLength(lwconfig.ApiSecretMinLength); len(secret) < minLength {
+		return "", errors.Errorf(
+			"The API secret access key must have more than %d characters.", minLength,
+		)
+	}
+
+	return secret, nil
+}
+
 func loadKeysFromJsonFile(file string) (*apiKeyDetails, error) {
 	cli.Log.Debugw("loading API key JSON file", "path", file)
 	jsonData, err := ioutil.ReadFile(file)
@@ -354,35 +1252,84 @@ func loadKeysFromJsonFile(file string) (*apiKeyDetails, error) {
 		return nil, err
 	}
 
-	cli.Log.Debugw("keys from file", "raw", string(jsonData))
 	var auth apiKeyDetails
 	err = json.Unmarshal(jsonData, &auth)
+	cli.Log.Debugw("keys from file",
+		"key_id", formatSecret(4, auth.KeyID), "secret", formatSecret(4, auth.Secret),
+	)
 	return &auth, err
 }
 
-func buildProfilesTableContent(current string, profiles Profiles) [][]string {
+func buildProfilesTableContent(current string, profiles lwconfig.Profiles) [][]string {
+	out := buildProfilesCSVContent(profiles)
+	for i := range out {
+		if out[i][0] == current {
+			out[i][0] = fmt.Sprintf("> %s", out[i][0])
+		} else {
+			out[i][0] = fmt.Sprintf("  %s", out[i][0])
+		}
+	}
+
+	return out
+}
+
+// buildProfilesCSVContent is like buildProfilesTableContent but without
+// the marker of the current profile
+func buildProfilesCSVContent(profiles lwconfig.Profiles) [][]string {
 	out := [][]string{}
 	for profile, creds := range profiles {
 		out = append(out, []string{
 			profile,
 			creds.Account,
-			creds.ApiKey,
+			formatSecret(4, creds.ApiKey),
 			formatSecret(4, creds.ApiSecret),
 		})
 	}
 
-	// order by severity
+	// order by profile name
 	sort.Slice(out, func(i, j int) bool {
 		return out[i][0] < out[j][0]
 	})
 
-	for i := range out {
-		if out[i][0] == current {
-			out[i][0] = fmt.Sprintf("> %s", out[i][0])
-		} else {
-			out[i][0] = fmt.Sprintf("  %s", out[i][0])
-		}
+	return out
+}
+
+// profileJSON is the JSON representation of a profile, secrets are masked
+type profileJSON struct {
+	Profile    string `json:"profile"`
+	Account    string `json:"account"`
+	Subaccount string `json:"subaccount,omitempty"`
+	ApiKey     string `json:"api_key"`
+	ApiSecret  string `json:"api_secret"`
+	ApiURL     string `json:"api_url,omitempty"`
+	CACert     string `json:"ca_cert,omitempty"`
+	Active     bool   `json:"active"`
+}
+
+// newProfileJSON returns the JSON representation of the provided profile
+func newProfileJSON(profile string, creds lwconfig.ProfileDetails, active bool) profileJSON {
+	return profileJSON{
+		Profile:    profile,
+		Account:    creds.Account,
+		Subaccount: creds.Subaccount,
+		ApiKey:     formatSecret(4, creds.ApiKey),
+		ApiSecret:  formatSecret(4, creds.ApiSecret),
+		ApiURL:     creds.ApiURL,
+		CACert:     creds.CACert,
+		Active:     active,
 	}
+}
+
+func buildProfilesJSONContent(current string, profiles lwconfig.Profiles) []profileJSON {
+	out := []profileJSON{}
+	for profile, creds := range profiles {
+		out = append(out, newProfileJSON(profile, creds, profile == current))
+	}
+
+	// order by profile name
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Profile < out[j].Profile
+	})
 
 	return out
 }
@@ -0,0 +1,99 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/pkg/errors"
+)
+
+// configureAwsSecret is the ARN of an AWS Secrets Manager secret that
+// contains the API key JSON file downloaded from the Lacework WebUI
+var configureAwsSecret string
+
+// fetchAwsSecret returns the value of the provided AWS Secrets Manager secret,
+// the credentials are resolved with the default credential chain of the AWS
+// SDK, that is, environment variables, shared config files, and the roles of
+// EC2 instances and ECS tasks
+var fetchAwsSecret = func(arn, region string) ([]byte, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            aws.Config{Region: aws.String(region)},
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load AWS credentials")
+	}
+
+	secret, err := secretsmanager.New(sess).GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if secret.SecretString != nil {
+		return []byte(aws.StringValue(secret.SecretString)), nil
+	}
+	return secret.SecretBinary, nil
+}
+
+// awsSecretRegion returns the region of the provided AWS Secrets Manager
+// secret ARN (arn:<partition>:secretsmanager:<region>:<account>:secret:<name>)
+func awsSecretRegion(arn string) (string, error) {
+	parts := strings.SplitN(arn, ":", 7)
+	if len(parts) != 7 || parts[0] != "arn" ||
+		!strings.HasPrefix(parts[1], "aws") ||
+		parts[2] != "secretsmanager" || parts[3] == "" || parts[5] != "secret" {
+		return "", errors.Errorf(
+			"invalid AWS secret '%s', expected an ARN like arn:aws:secretsmanager:<region>:<account>:secret:<name>",
+			arn,
+		)
+	}
+	return parts[3], nil
+}
+
+// loadKeysFromAwsSecret fetches the provided AWS Secrets Manager secret and
+// loads the API key and secret from it, the secret must have the same shape
+// as the API key JSON file downloaded from the Lacework WebUI
+func loadKeysFromAwsSecret(arn string) (*apiKeyDetails, error) {
+	region, err := awsSecretRegion(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	cli.Log.Debugw("fetching API keys from AWS Secrets Manager", "arn", arn, "region", region)
+	data, err := fetchAwsSecret(arn, region)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to fetch secret from AWS Secrets Manager")
+	}
+
+	var auth apiKeyDetails
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return nil, errors.Wrap(err, "unable to load keys from the AWS secret, expected the API key JSON file")
+	}
+	if auth.KeyID == "" || auth.Secret == "" {
+		return nil, errors.New("unable to load keys from the AWS secret, the 'keyId' or 'secret' fields are missing")
+	}
+	return &auth, nil
+}
@@ -0,0 +1,90 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestAwsSecretRegion(t *testing.T) {
+	region, err := awsSecretRegion("arn:aws:secretsmanager:us-west-2:123456789012:secret:lacework-AbCdEf")
+	assert.Nil(t, err)
+	assert.Equal(t, "us-west-2", region)
+
+	region, err = awsSecretRegion("arn:aws-us-gov:secretsmanager:us-gov-west-1:123456789012:secret:lacework")
+	assert.Nil(t, err)
+	assert.Equal(t, "us-gov-west-1", region)
+
+	for _, arn := range []string{
+		"lacework",
+		"arn:aws:s3:::bucket",
+		"arn:aws:secretsmanager::123456789012:secret:lacework",
+		"arn:aws:ssm:us-west-2:123456789012:parameter:lacework",
+	} {
+		_, err := awsSecretRegion(arn)
+		if assert.NotNil(t, err, arn) {
+			assert.Contains(t, err.Error(), "invalid AWS secret")
+		}
+	}
+}
+
+func TestLoadKeysFromAwsSecret(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	defer func(fetch func(string, string) ([]byte, error)) { fetchAwsSecret = fetch }(fetchAwsSecret)
+	cli.Log = lwlogger.New("").Sugar()
+
+	arn := "arn:aws:secretsmanager:us-west-2:123456789012:secret:lacework"
+	fetchAwsSecret = func(secret, region string) ([]byte, error) {
+		assert.Equal(t, arn, secret)
+		assert.Equal(t, "us-west-2", region)
+		return []byte(`{"keyId": "KEY", "secret": "_SECRET"}`), nil
+	}
+	auth, err := loadKeysFromAwsSecret(arn)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "KEY", auth.KeyID)
+		assert.Equal(t, "_SECRET", auth.Secret)
+	}
+
+	fetchAwsSecret = func(_, _ string) ([]byte, error) {
+		return []byte(`{"username": "admin"}`), nil
+	}
+	_, err = loadKeysFromAwsSecret(arn)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "'keyId' or 'secret' fields are missing")
+	}
+
+	fetchAwsSecret = func(_, _ string) ([]byte, error) {
+		return []byte("not-json"), nil
+	}
+	_, err = loadKeysFromAwsSecret(arn)
+	assert.NotNil(t, err)
+
+	fetchAwsSecret = func(_, _ string) ([]byte, error) {
+		return nil, errors.New("AccessDeniedException")
+	}
+	_, err = loadKeysFromAwsSecret(arn)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unable to fetch secret from AWS Secrets Manager: AccessDeniedException")
+	}
+}
@@ -0,0 +1,225 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+const (
+	configureImportKeep      = "keep"
+	configureImportOverwrite = "overwrite"
+	configureImportRename    = "rename"
+)
+
+// configureImportOnConflict is what to do when an imported profile already
+// exists, one of keep or overwrite, empty means to ask
+var configureImportOnConflict string
+
+var configureImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "import the profiles from another config file",
+	Args:  cobra.ExactArgs(1),
+	Long: `Imports the profiles from another config file into ~/.lacework.toml, useful
+to consolidate the profiles configured on different machines.
+
+    $ lacework configure import other.toml
+
+When an imported profile already exists with different settings, you are asked
+to keep the existing profile, overwrite it, or import it under a new name.
+Identical profiles are skipped. Use the flag --on-conflict to choose what to
+do without prompts:
+
+    $ lacework configure import other.toml --on-conflict overwrite
+
+Without prompts (i.e. with --noninteractive), existing profiles are kept.
+Only profiles are imported, global settings like the default profile are not.`,
+	RunE: func(_ *cobra.Command, args []string) error {
+		strategy, err := configureImportStrategy()
+		if err != nil {
+			return err
+		}
+
+		confPath, err := cli.ConfigFilePath()
+		if err != nil {
+			return err
+		}
+
+		cli.Log.Debugw("loading config to import", "path", args[0])
+		other, err := lwconfig.LoadFromFile(args[0])
+		if err != nil {
+			return errors.Wrapf(err, "unable to load config file %s", args[0])
+		}
+
+		config, err := loadStoredConfig(confPath)
+		if err != nil {
+			return err
+		}
+
+		var promptErr error
+		if strategy == nil {
+			strategy = func(name string) (lwconfig.MergeAction, string) {
+				action, newName, err := promptImportConflict(name, config, other)
+				if err != nil && promptErr == nil {
+					promptErr = err
+				}
+				return action, newName
+			}
+		}
+
+		result, err := config.Merge(other, strategy)
+		if err != nil {
+			return errors.Wrap(err, "unable to import profiles")
+		}
+		if promptErr != nil {
+			return promptErr
+		}
+
+		if len(result.Added)+len(result.Overwritten)+len(result.Renamed) != 0 {
+			cli.Log.Debugw("importing profiles", "path", args[0], "result", result)
+			if err := storeConfig(confPath, config); err != nil {
+				return errors.Wrap(err, "unable to import profiles")
+			}
+		}
+
+		if cli.StructuredOutput() {
+			return cli.OutputStructured(result)
+		}
+
+		cli.OutputHuman(buildImportReport(result))
+		return nil
+	},
+}
+
+// configureImportStrategy returns the merge strategy selected with the flag
+// --on-conflict, nil means that the user must be asked on every conflict
+func configureImportStrategy() (lwconfig.MergeStrategy, error) {
+	switch configureImportOnConflict {
+	case configureImportKeep:
+		return lwconfig.MergeKeepExisting, nil
+	case configureImportOverwrite:
+		return lwconfig.MergeOverwriteExisting, nil
+	case "":
+		if cli.InteractiveMode() {
+			return nil, nil
+		}
+		return lwconfig.MergeKeepExisting, nil
+	default:
+		return nil, errors.Errorf(
+			"invalid value '%s' for --on-conflict, use '%s' or '%s'",
+			configureImportOnConflict, configureImportKeep, configureImportOverwrite,
+		)
+	}
+}
+
+// promptImportConflict asks the user what to do with an imported profile
+// that already exists in the provided config
+func promptImportConflict(
+	name string, config, other lwconfig.Config,
+) (lwconfig.MergeAction, string, error) {
+	answer := ""
+	err := survey.AskOne(&survey.Select{
+		Message: fmt.Sprintf("Profile '%s' already exists with different settings:", name),
+		Options: []string{configureImportKeep, configureImportOverwrite, configureImportRename},
+		Default: configureImportKeep,
+	}, &answer, survey.WithIcons(promptIconsFunc))
+	if err != nil {
+		return lwconfig.MergeKeep, "", err
+	}
+
+	switch answer {
+	case configureImportOverwrite:
+		return lwconfig.MergeOverwrite, "", nil
+	case configureImportRename:
+		newName := ""
+		err := survey.AskOne(&survey.Input{
+			Message: fmt.Sprintf("New name for the imported profile '%s':", name),
+		}, &newName,
+			survey.WithIcons(promptIconsFunc),
+			survey.WithValidator(func(ans interface{}) error {
+				str, _ := ans.(string)
+				if str == "" {
+					return errors.New("The new name is required.")
+				}
+				if _, exists := config.Profiles[str]; exists {
+					return errors.Errorf("The profile '%s' already exists.", str)
+				}
+				if _, exists := other.Profiles[str]; exists {
+					return errors.Errorf("The profile '%s' is also being imported.", str)
+				}
+				return nil
+			}),
+		)
+		if err != nil {
+			return lwconfig.MergeKeep, "", err
+		}
+		return lwconfig.MergeRename, newName, nil
+	default:
+		return lwconfig.MergeKeep, "", nil
+	}
+}
+
+// buildImportReport returns a human-readable report of the imported profiles
+func buildImportReport(result lwconfig.MergeResult) string {
+	var (
+		report   = &strings.Builder{}
+		imported = len(result.Added) + len(result.Overwritten) + len(result.Renamed)
+	)
+
+	if imported == 0 {
+		report.WriteString("No profiles were imported.\n")
+	} else {
+		report.WriteString(fmt.Sprintf("%d profiles imported.\n", imported))
+	}
+
+	if len(result.Added) != 0 {
+		report.WriteString(fmt.Sprintf("\n  Added: %s", strings.Join(result.Added, ", ")))
+	}
+	if len(result.Overwritten) != 0 {
+		report.WriteString(fmt.Sprintf("\n  Overwritten: %s", strings.Join(result.Overwritten, ", ")))
+	}
+	if len(result.Renamed) != 0 {
+		names := make([]string, 0, len(result.Renamed))
+		for name := range result.Renamed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		renamed := make([]string, len(names))
+		for i, name := range names {
+			renamed[i] = fmt.Sprintf("%s -> %s", name, result.Renamed[name])
+		}
+		report.WriteString(fmt.Sprintf("\n  Renamed: %s", strings.Join(renamed, ", ")))
+	}
+	if len(result.Skipped) != 0 {
+		report.WriteString(fmt.Sprintf("\n  Skipped: %s", strings.Join(result.Skipped, ", ")))
+	}
+	if imported != 0 || len(result.Skipped) != 0 {
+		report.WriteString("\n")
+	}
+	return report.String()
+}
@@ -0,0 +1,90 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+func TestConfigureImportStrategy(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	defer func(onConflict string) { configureImportOnConflict = onConflict }(configureImportOnConflict)
+
+	configureImportOnConflict = "keep"
+	strategy, err := configureImportStrategy()
+	if assert.Nil(t, err) && assert.NotNil(t, strategy) {
+		action, _ := strategy("dev")
+		assert.Equal(t, lwconfig.MergeKeep, action)
+	}
+
+	configureImportOnConflict = "overwrite"
+	strategy, err = configureImportStrategy()
+	if assert.Nil(t, err) && assert.NotNil(t, strategy) {
+		action, _ := strategy("dev")
+		assert.Equal(t, lwconfig.MergeOverwrite, action)
+	}
+
+	configureImportOnConflict = "rename"
+	_, err = configureImportStrategy()
+	if assert.NotNil(t, err) {
+		assert.Equal(t,
+			"invalid value 'rename' for --on-conflict, use 'keep' or 'overwrite'", err.Error())
+	}
+
+	// without the flag, the user is asked when running interactively
+	configureImportOnConflict = ""
+	strategy, err = configureImportStrategy()
+	assert.Nil(t, err)
+	assert.Nil(t, strategy)
+
+	cli.nonInteractive = true
+	strategy, err = configureImportStrategy()
+	if assert.Nil(t, err) && assert.NotNil(t, strategy) {
+		action, _ := strategy("dev")
+		assert.Equal(t, lwconfig.MergeKeep, action)
+	}
+}
+
+func TestBuildImportReport(t *testing.T) {
+	assert.Equal(t, "No profiles were imported.\n", buildImportReport(lwconfig.MergeResult{}))
+
+	assert.Equal(t,
+		"No profiles were imported.\n\n  Skipped: default, dev\n",
+		buildImportReport(lwconfig.MergeResult{Skipped: []string{"default", "dev"}}),
+	)
+
+	assert.Equal(t, `4 profiles imported.
+
+  Added: prod
+  Overwritten: dev
+  Renamed: qa -> qa-other, test -> test-other
+  Skipped: default
+`,
+		buildImportReport(lwconfig.MergeResult{
+			Added:       []string{"prod"},
+			Overwritten: []string{"dev"},
+			Renamed:     map[string]string{"test": "test-other", "qa": "qa-other"},
+			Skipped:     []string{"default"},
+		}),
+	)
+}
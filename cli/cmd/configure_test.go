@@ -0,0 +1,122 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestLoadKeysFromJsonFileMasksCredentials(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	logs := new(bytes.Buffer)
+	cli.Log = lwlogger.NewWithWriter("DEBUG", logs).Sugar()
+
+	dir, err := ioutil.TempDir("", "lacework-keys")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	keysFile := filepath.Join(dir, "keys.json")
+	err = ioutil.WriteFile(keysFile,
+		[]byte(`{"keyId":"INTEGRATION_KEY_ID","secret":"_SUPER_SECRET_VALUE"}`), 0600)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	auth, err := loadKeysFromJsonFile(keysFile)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "INTEGRATION_KEY_ID", auth.KeyID)
+		assert.Equal(t, "_SUPER_SECRET_VALUE", auth.Secret)
+	}
+
+	assert.Contains(t, logs.String(), "keys from file")
+	assert.Contains(t, logs.String(), "**************Y_ID")
+	assert.NotContains(t, logs.String(), "INTEGRATION_KEY_ID")
+	assert.NotContains(t, logs.String(), "_SUPER_SECRET_VALUE")
+	assert.NotContains(t, logs.String(), "SUPER_SECRET")
+}
+
+func TestStoreProfilesInvalidConfig(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+
+	dir, err := ioutil.TempDir("", "lacework-config")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// a missing config file is an empty config
+	config, err := loadStoredConfig(filepath.Join(dir, "missing.toml"))
+	if assert.Nil(t, err) {
+		assert.Empty(t, config.Profiles)
+	}
+
+	// a config file that cannot be loaded is never overwritten
+	var (
+		confPath = filepath.Join(dir, "lacework.toml")
+		invalid  = []byte("[default]\naccount = \"test\"\napi_key = \n")
+	)
+	if !assert.Nil(t, ioutil.WriteFile(confPath, invalid, 0600)) {
+		return
+	}
+
+	err = storeProfiles(confPath, lwconfig.Profiles{})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unable to load config file")
+	}
+
+	stored, err := ioutil.ReadFile(confPath)
+	if assert.Nil(t, err) {
+		assert.Equal(t, invalid, stored)
+	}
+}
+
+func TestStoreProfilesReservedName(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+
+	dir, err := ioutil.TempDir("", "lacework-config")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	confPath := filepath.Join(dir, "lacework.toml")
+	err = storeProfiles(confPath, lwconfig.Profiles{
+		"updates": {Account: "test", ApiKey: "KEY", ApiSecret: "SECRET"},
+	})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "the profile name 'updates' is reserved, use a different name", err.Error())
+		assert.Equal(t, exitCodeUsage, exitCode(err))
+	}
+
+	_, err = os.Stat(confPath)
+	assert.True(t, os.IsNotExist(err), "the config file should not be written")
+}
@@ -0,0 +1,348 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+const (
+	// the maximum difference between the local clock and the clock of the
+	// Lacework API before access tokens are rejected or expire too early
+	configureVerifyMaxClockSkew = 5 * time.Minute
+
+	verifyCheckPass = "pass"
+	verifyCheckFail = "fail"
+	verifyCheckSkip = "skip"
+)
+
+// configureVerifyFixPerms fixes the permissions of the config file when
+// it is accessible by other users
+var configureVerifyFixPerms bool
+
+var configureVerifyCmd = &cobra.Command{
+	Use:     "verify",
+	Aliases: []string{"doctor"},
+	Short:   "diagnose the setup of the Lacework CLI",
+	Args:    cobra.NoArgs,
+
+	// the failed checks already explain what went wrong
+	SilenceUsage: true,
+	Long: `Runs a series of checks to diagnose the most common setup problems:
+
+  * the config file ~/.lacework.toml exists and it is only readable by you
+  * the selected profile is valid, i.e. it has an account, API key and secret
+  * the credentials of the profile authenticate against the Lacework API
+  * the clock of this machine is in sync with the clock of the Lacework API
+
+Every check is displayed with a hint to fix it when it fails.
+
+    $ lacework configure verify --profile my-profile
+
+Use the flag --fix-perms to make the config file only readable by you when
+it is accessible by other users.`,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		checks := runVerifyChecks()
+
+		if cli.StructuredOutput() {
+			if err := cli.OutputStructured(checks); err != nil {
+				return err
+			}
+		} else {
+			cli.OutputHuman(buildVerifyChecksReport(checks))
+		}
+
+		if failed := countFailedChecks(checks); failed != 0 {
+			return errors.Errorf("%d of %d checks failed", failed, len(checks))
+		}
+
+		if !cli.Quiet() {
+			cli.OutputHuman("\nAll checks passed, the Lacework CLI is ready to use.\n")
+		}
+		return nil
+	},
+}
+
+// verifyCheck is the result of a single check of the verify command
+type verifyCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+func (c verifyCheck) pass(message string) verifyCheck {
+	c.Status, c.Message = verifyCheckPass, message
+	return c
+}
+
+func (c verifyCheck) fail(message, hint string) verifyCheck {
+	c.Status, c.Message, c.Hint = verifyCheckFail, message, hint
+	return c
+}
+
+func (c verifyCheck) skip(message string) verifyCheck {
+	c.Status, c.Message = verifyCheckSkip, message
+	return c
+}
+
+// runVerifyChecks runs every check in order, the checks that talk to the
+// Lacework API are skipped when the selected profile is not valid
+func runVerifyChecks() []verifyCheck {
+	confPath, _ := cli.ConfigFilePath()
+
+	var (
+		checks = verifyConfigFile(confPath)
+		creds  = lwconfig.ProfileDetails{
+			Account:    cli.Account,
+			Subaccount: cli.Subaccount,
+			ApiKey:     cli.KeyID,
+			ApiSecret:  cli.Secret,
+			ApiURL:     cli.ApiURL,
+			CACert:     cli.CACert,
+			Timeout:    int(cli.Timeout / time.Second),
+		}
+		profileCheck = verifyProfile(confPath, cli.Profile, creds)
+	)
+
+	checks = append(checks, profileCheck)
+	if profileCheck.Status != verifyCheckPass {
+		return append(checks,
+			verifyCheck{Name: "credentials"}.skip("the profile is not valid"),
+			verifyCheck{Name: "clock skew"}.skip("the profile is not valid"),
+		)
+	}
+
+	return append(checks, verifyCredentials(cli.Profile, creds)...)
+}
+
+// verifyConfigFile checks that the config file exists and that it is not
+// accessible by other users since it contains credentials
+func verifyConfigFile(confPath string) []verifyCheck {
+	var (
+		existsCheck = verifyCheck{Name: "config file"}
+		permsCheck  = verifyCheck{Name: "config file permissions"}
+	)
+
+	info, err := os.Stat(confPath)
+	if err != nil {
+		return []verifyCheck{
+			existsCheck.fail(fmt.Sprintf("no config file found at %s", confPath),
+				"run 'lacework configure' to create it"),
+			permsCheck.skip("there is no config file"),
+		}
+	}
+	existsCheck = existsCheck.pass(confPath)
+
+	if runtime.GOOS == "windows" {
+		return []verifyCheck{existsCheck, permsCheck.skip("not supported on Windows")}
+	}
+
+	perm := info.Mode().Perm()
+	if !lwconfig.IsInsecurePermissions(lwconfig.CheckPermissions(confPath)) {
+		return []verifyCheck{existsCheck, permsCheck.pass(fmt.Sprintf("%04o", perm))}
+	}
+
+	if !configureVerifyFixPerms {
+		return []verifyCheck{existsCheck, permsCheck.fail(
+			fmt.Sprintf("the file is accessible by other users (%04o)", perm),
+			fmt.Sprintf("run 'lacework configure verify --fix-perms' or 'chmod 600 %s'", confPath),
+		)}
+	}
+
+	if err := lwconfig.FixPermissions(confPath); err != nil {
+		return []verifyCheck{existsCheck, permsCheck.fail(err.Error(),
+			fmt.Sprintf("run 'chmod 600 %s'", confPath),
+		)}
+	}
+	return []verifyCheck{existsCheck, permsCheck.pass(
+		fmt.Sprintf("0600 (fixed, the file was accessible by other users %04o)", perm),
+	)}
+}
+
+// verifyProfile checks that the config file can be parsed and that the
+// selected profile has all the required settings, the settings could come
+// from the environment variables so the profile is not required to exist
+func verifyProfile(confPath, name string, creds lwconfig.ProfileDetails) verifyCheck {
+	var (
+		check        = verifyCheck{Name: fmt.Sprintf("profile '%s'", name)}
+		configureCmd = "lacework configure"
+		inConfig     = false
+	)
+	if name != lwconfig.DefaultProfile {
+		configureCmd = fmt.Sprintf("lacework configure --profile %s", name)
+	}
+
+	if _, err := os.Stat(confPath); err == nil {
+		config, err := lwconfig.LoadFromFile(confPath)
+		if err != nil {
+			return check.fail(err.Error(),
+				fmt.Sprintf("fix the syntax of the config file or run '%s'", configureCmd))
+		}
+		_, err = config.GetProfile(name)
+		inConfig = err == nil
+	}
+
+	if err := creds.Verify(); err != nil {
+		if !inConfig {
+			return check.fail("the profile is not configured",
+				fmt.Sprintf("run '%s'", configureCmd))
+		}
+		return check.fail(err.Error(), fmt.Sprintf("run '%s'", configureCmd))
+	}
+
+	return check.pass(fmt.Sprintf("account: %s", creds.Account))
+}
+
+// verifyCredentials generates an access token to check that the credentials
+// authenticate, and uses the date of the response to measure the clock skew,
+// the client has the same timeout and TLS settings as every other command
+func verifyCredentials(name string, creds lwconfig.ProfileDetails) []verifyCheck {
+	var (
+		authCheck = verifyCheck{Name: "credentials"}
+		skewCheck = verifyCheck{Name: "clock skew"}
+		recorder  = &dateRecorder{}
+	)
+
+	client, err := newProfileClient(creds,
+		api.WithTransportWrapper(func(transport http.RoundTripper) http.RoundTripper {
+			recorder.transport = transport
+			return recorder
+		}),
+	)
+	if err != nil {
+		return []verifyCheck{
+			authCheck.fail(err.Error(), "verify the settings of the profile"),
+			skewCheck.skip("unable to reach the Lacework API"),
+		}
+	}
+
+	cli.StartProgress(" Verifying credentials...")
+	_, err = client.GenerateToken()
+	cli.StopProgress()
+	if err != nil {
+		hint := apiErrorHint(err)
+		if hint == "" {
+			hint = fmt.Sprintf(
+				"verify the network access to %s and the credentials of the profile '%s'",
+				client.URL(), name,
+			)
+		}
+		authCheck = authCheck.fail(err.Error(), strings.Replace(hint, ",\n", ", ", 1))
+	} else {
+		authCheck = authCheck.pass(fmt.Sprintf("authenticated against %s", client.URL()))
+	}
+
+	// the clock skew is measured even if the authentication failed, since
+	// a clock out of sync is a common cause of authentication errors
+	if recorder.date.IsZero() {
+		return []verifyCheck{authCheck, skewCheck.skip("unable to get the date of the Lacework API")}
+	}
+	return []verifyCheck{authCheck, clockSkewCheck(recorder.date, recorder.received)}
+}
+
+// clockSkewCheck compares the date of the Lacework API with the local date
+func clockSkewCheck(serverDate, localDate time.Time) verifyCheck {
+	var (
+		check     = verifyCheck{Name: "clock skew"}
+		skew      = localDate.Sub(serverDate).Truncate(time.Second)
+		direction = "ahead of"
+	)
+	if skew < 0 {
+		skew = -skew
+		direction = "behind"
+	}
+
+	message := fmt.Sprintf("the local clock is %s %s the Lacework API", skew, direction)
+	if skew > configureVerifyMaxClockSkew {
+		return check.fail(message,
+			"synchronize the clock of this machine, for instance, by enabling NTP")
+	}
+	return check.pass(message)
+}
+
+// dateRecorder is an http.RoundTripper that records the Date header of the
+// last response, and the local time when it was received
+type dateRecorder struct {
+	transport http.RoundTripper
+	date      time.Time
+	received  time.Time
+}
+
+func (r *dateRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	if date, err := http.ParseTime(res.Header.Get("Date")); err == nil {
+		r.date = date
+		r.received = time.Now()
+	}
+	return res, nil
+}
+
+func countFailedChecks(checks []verifyCheck) (failed int) {
+	for _, check := range checks {
+		if check.Status == verifyCheckFail {
+			failed++
+		}
+	}
+	return
+}
+
+// verifyStatusColors are the colors used to highlight the status of a check
+var verifyStatusColors = map[string]*color.Color{
+	verifyCheckPass: color.New(color.FgGreen),
+	verifyCheckFail: color.New(color.FgRed),
+	verifyCheckSkip: color.New(color.FgYellow),
+}
+
+func buildVerifyChecksReport(checks []verifyCheck) string {
+	report := &strings.Builder{}
+	for _, check := range checks {
+		status := strings.ToUpper(check.Status)
+		if cli.ColorsEnabled() {
+			status = verifyStatusColors[check.Status].Sprint(status)
+		}
+
+		report.WriteString(fmt.Sprintf("[%s] %s", status, check.Name))
+		if check.Message != "" {
+			report.WriteString(fmt.Sprintf(": %s", check.Message))
+		}
+		report.WriteString("\n")
+
+		if check.Hint != "" {
+			report.WriteString(fmt.Sprintf("       Hint: %s\n", check.Hint))
+		}
+	}
+	return report.String()
+}
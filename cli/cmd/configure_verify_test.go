@@ -0,0 +1,220 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestVerifyConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lacework-verify")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	confPath := filepath.Join(dir, ".lacework.toml")
+	checks := verifyConfigFile(confPath)
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, verifyCheckFail, checks[0].Status)
+		assert.Equal(t, "run 'lacework configure' to create it", checks[0].Hint)
+		assert.Equal(t, verifyCheckSkip, checks[1].Status)
+	}
+
+	assert.Nil(t, ioutil.WriteFile(confPath, []byte("[default]\n"), 0644))
+	assert.Nil(t, os.Chmod(confPath, 0644))
+	checks = verifyConfigFile(confPath)
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, verifyCheckPass, checks[0].Status)
+		assert.Equal(t, verifyCheckFail, checks[1].Status)
+		assert.Equal(t, "the file is accessible by other users (0644)", checks[1].Message)
+		assert.Equal(t,
+			"run 'lacework configure verify --fix-perms' or 'chmod 600 "+confPath+"'",
+			checks[1].Hint)
+	}
+
+	configureVerifyFixPerms = true
+	defer func() { configureVerifyFixPerms = false }()
+	checks = verifyConfigFile(confPath)
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, verifyCheckPass, checks[1].Status)
+		assert.Equal(t, "0600 (fixed, the file was accessible by other users 0644)", checks[1].Message)
+	}
+	configureVerifyFixPerms = false
+
+	assert.Nil(t, os.Chmod(confPath, 0600))
+	checks = verifyConfigFile(confPath)
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, verifyCheckPass, checks[1].Status)
+	}
+}
+
+func TestVerifyProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lacework-verify")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	confPath := filepath.Join(dir, ".lacework.toml")
+	assert.Nil(t, ioutil.WriteFile(confPath, []byte(`[default]
+account = 'example'
+api_key = 'KEY'
+`), 0600))
+
+	creds := lwconfig.ProfileDetails{Account: "example", ApiKey: "KEY"}
+	check := verifyProfile(confPath, "default", creds)
+	assert.Equal(t, verifyCheckFail, check.Status)
+	assert.Equal(t, "api_secret missing", check.Message)
+	assert.Equal(t, "run 'lacework configure'", check.Hint)
+
+	check = verifyProfile(confPath, "dev", lwconfig.ProfileDetails{})
+	assert.Equal(t, verifyCheckFail, check.Status)
+	assert.Equal(t, "the profile is not configured", check.Message)
+	assert.Equal(t, "run 'lacework configure --profile dev'", check.Hint)
+
+	// the settings could come from environment variables
+	creds.ApiSecret = "_secret"
+	check = verifyProfile(confPath, "default", creds)
+	assert.Equal(t, verifyCheckPass, check.Status)
+	assert.Equal(t, "account: example", check.Message)
+
+	assert.Nil(t, ioutil.WriteFile(confPath, []byte("[default\n"), 0600))
+	check = verifyProfile(confPath, "default", creds)
+	assert.Equal(t, verifyCheckFail, check.Status)
+	assert.Contains(t, check.Message, "unable to decode profiles from config")
+}
+
+func TestClockSkewCheck(t *testing.T) {
+	now := time.Now()
+
+	check := clockSkewCheck(now, now.Add(2*time.Second))
+	assert.Equal(t, verifyCheckPass, check.Status)
+	assert.Equal(t, "the local clock is 2s ahead of the Lacework API", check.Message)
+
+	check = clockSkewCheck(now, now.Add(-10*time.Minute))
+	assert.Equal(t, verifyCheckFail, check.Status)
+	assert.Equal(t, "the local clock is 10m0s behind the Lacework API", check.Message)
+	assert.NotEmpty(t, check.Hint)
+}
+
+func TestVerifyCredentials(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+	cli.nonInteractive = true
+
+	authorized := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"ok":false,"message":"unauthorized"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"data":[{"token":"TOKEN","expiresAt":"Jan 1 2030"}]}`))
+	}))
+	defer server.Close()
+
+	creds := lwconfig.ProfileDetails{
+		Account:   "example",
+		ApiKey:    "KEY",
+		ApiSecret: "_secret",
+		ApiURL:    server.URL,
+		Timeout:   5,
+	}
+
+	checks := verifyCredentials("default", creds)
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, verifyCheckPass, checks[0].Status)
+		assert.Equal(t, verifyCheckPass, checks[1].Status)
+	}
+
+	// the clock skew is measured even if the authentication failed
+	authorized = false
+	checks = verifyCredentials("default", creds)
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, verifyCheckFail, checks[0].Status)
+		assert.Contains(t, checks[0].Hint, "lacework configure")
+		assert.Equal(t, verifyCheckPass, checks[1].Status)
+	}
+	assert.Equal(t, 1, countFailedChecks(checks))
+}
+
+func TestVerifyCredentialsWithCACert(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+	cli.nonInteractive = true
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true,"data":[{"token":"TOKEN","expiresAt":"Jan 1 2030"}]}`))
+	}))
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "lacework-verify")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caCert := filepath.Join(dir, "ca.pem")
+	assert.Nil(t, ioutil.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: server.Certificate().Raw,
+	}), 0600))
+
+	creds := lwconfig.ProfileDetails{
+		Account:   "example",
+		ApiKey:    "KEY",
+		ApiSecret: "_secret",
+		ApiURL:    server.URL,
+	}
+
+	checks := verifyCredentials("default", creds)
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, verifyCheckFail, checks[0].Status,
+			"the certificate of the server should not be trusted")
+		assert.Contains(t, checks[0].Message, "certificate")
+	}
+
+	creds.CACert = caCert
+	checks = verifyCredentials("default", creds)
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, verifyCheckPass, checks[0].Status)
+		assert.Equal(t, verifyCheckPass, checks[1].Status)
+	}
+
+	creds.CACert = ""
+	cli.insecureTLS = true
+	checks = verifyCredentials("default", creds)
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, verifyCheckPass, checks[0].Status)
+	}
+}
@@ -19,9 +19,8 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
-	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -30,11 +29,15 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
 
 	"github.com/lacework/go-sdk/api"
 	"github.com/lacework/go-sdk/internal/array"
 )
 
+// eventsMaxDays is the maximum number of days that the flag --days accepts
+const eventsMaxDays = 7
+
 var (
 	eventsCmdState = struct {
 		// start time for listing events
@@ -48,6 +51,51 @@ var (
 
 		// list events with a specific severity
 		Severity string
+
+		// list only the events of the specified event types
+		Types []string
+
+		// select and order the columns of the events table
+		Columns []string
+
+		// list only the distinct event types with the number of events
+		TypesOnly bool
+
+		// list only the events newer than the events bookmark, then update it
+		SinceLast bool
+
+		// clear the events bookmark
+		ResetBookmark bool
+
+		// display every column of the IP address table of events
+		Wide bool
+
+		// display only the identity and threat columns of the IP address table
+		Narrow bool
+
+		// display every record of the event details vertically
+		Expand bool
+
+		// print the URL of an event instead of opening a web browser
+		PrintOnly bool
+
+		// copy the URL of an event to the clipboard
+		CopyLink bool
+
+		// load the filters of the events list from a saved query file
+		Query string
+
+		// show only the IP address and file entities first seen after this time
+		FirstSeenAfter string
+
+		// show only the file data hash entities flagged as known-bad
+		KnownBadOnly bool
+
+		// display one table of events per event type
+		GroupByType bool
+
+		// write one file of events per severity into this directory
+		OutputDir string
 	}{}
 
 	// easily add or remove borders to all event details tables
@@ -69,20 +117,80 @@ var (
 specify a custom time period. You can also pass --serverity to filter by a
 severity threshold.
 
-Additionally, pass --days to list events for a specified number of days.
+Additionally, pass --days to list events for a specified number of days, this
+flag cannot be combined with --start and --end.
 
 For example, to list all events from the last day with severity medium and above
 (Critical, High and Medium) run:
 
-    $ lacework events list --severity medium --days 1`,
+    $ lacework events list --severity medium --days 1
+
+To list only the events of specific event types, use the flag --type, it can
+be provided multiple times:
+
+    $ lacework events list --type NewUser --type NewVPC
+
+Use the flag --columns to select and order the columns of the table, for
+example, to display only the event id, severity and start time run:
+
+    $ lacework events list --columns id,severity,start
+
+To understand the shape of the activity in a time range, use the flag
+--types-only to list the distinct event types with the number of events
+of each type, instead of the individual events:
+
+    $ lacework events list --types-only --days 1
+
+To spot which event types generate the most noise, use the flag --group-by-type
+to display one table per event type, ordered by the number of events:
+
+    $ lacework events list --group-by-type --days 1
+
+To archive the events, use the flag --output-dir to write one file per severity,
+like critical.json or high.json, into a directory, severities without events
+are skipped. The files are in JSON format unless --output is csv or yaml:
+
+    $ lacework events list --output-dir ./out --json
+
+To list only the events that are new since the last time you checked, use
+the flag --since-last, the end of the time range is remembered per account
+as a bookmark in the state file of the Lacework CLI (~/.config/lacework/state.json
+on Linux) and every run updates it. Use the flag --reset-bookmark to clear it:
+
+    $ lacework events list --since-last
+
+To reuse the same filters, save them in a JSON or TOML (.toml) query file and
+load it with the flag --query, flags provided on the command line override the
+values of the file:
+
+    $ cat high-last-day.json
+    {
+      "severity": "high",
+      "days": 1,
+      "columns": ["id", "type", "severity", "start"]
+    }
+    $ lacework events list --query high-last-day.json
+
+The keys of a query file are: start, end, days, severity, columns, types_only
+and since_last.`,
 		Args: cobra.NoArgs,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 
 			var (
 				response api.EventsResponse
 				err      error
 			)
 
+			if eventsCmdState.Query != "" {
+				query, err := loadEventsQuery(eventsCmdState.Query)
+				if err != nil {
+					return err
+				}
+				if err := applyEventsQuery(cmd.Flags(), query); err != nil {
+					return err
+				}
+			}
+
 			if eventsCmdState.Severity != "" {
 				if !array.ContainsStr(api.ValidEventSeverities, eventsCmdState.Severity) {
 					return errors.Errorf("the severity %s is not valid, use one of %s",
@@ -91,16 +199,70 @@ For example, to list all events from the last day with severity medium and above
 				}
 			}
 
-			if eventsCmdState.Start != "" || eventsCmdState.End != "" {
+			columns, err := selectEventsTableColumns(eventsCmdState.Columns)
+			if err != nil {
+				return err
+			}
+
+			if err := validateEventsTimeRangeFlags(cmd.Flags()); err != nil {
+				return err
+			}
+
+			if eventsCmdState.TypesOnly && cmd.Flags().Changed("columns") {
+				return errors.New("cannot combine --types-only with --columns")
+			}
+
+			if eventsCmdState.TypesOnly && eventsCmdState.GroupByType {
+				return errors.New("cannot combine --types-only with --group-by-type")
+			}
+
+			if eventsCmdState.OutputDir != "" && (eventsCmdState.TypesOnly || eventsCmdState.GroupByType) {
+				return errors.New("cannot combine --output-dir with --types-only or --group-by-type")
+			}
+
+			if eventsCmdState.SinceLast &&
+				(cmd.Flags().Changed("start") || cmd.Flags().Changed("end") || cmd.Flags().Changed("days")) {
+				return errors.New("cannot combine --since-last with --start, --end or --days")
+			}
+
+			if eventsCmdState.ResetBookmark {
+				if err := storeEventsBookmark(time.Time{}); err != nil {
+					return errors.Wrap(err, "unable to reset events bookmark")
+				}
+				if !eventsCmdState.SinceLast {
+					cli.OutputHuman("The events bookmark of the account '%s' was reset.\n", eventsBookmarkKey())
+					return nil
+				}
+			}
+
+			var bookmark, bookmarkEnd time.Time
+			if eventsCmdState.SinceLast {
+				bookmark, err = loadEventsBookmark()
+				if err != nil {
+					return errors.Wrap(err, "unable to load events bookmark")
+				}
+			}
+
+			cli.StartProgress(" Fetching events...")
+			if eventsCmdState.SinceLast {
+				var start time.Time
+				start, bookmarkEnd = eventsSinceLastTimeRange(bookmark, time.Now().UTC())
+
+				cli.Log.Infow("requesting list of events since the bookmark",
+					"bookmark", bookmark, "start_time", start, "end_time", bookmarkEnd,
+				)
+				response, err = listEventsDateRange(cmd.Context(), start, bookmarkEnd)
+			} else if eventsCmdState.Start != "" || eventsCmdState.End != "" {
 				start, end, errT := parseStartAndEndTime(eventsCmdState.Start, eventsCmdState.End)
 				if errT != nil {
+					cli.StopProgress()
 					return errors.Wrap(errT, "unable to parse time range")
 				}
 
 				cli.Log.Infow("requesting list of events from custom time range",
 					"start_time", start, "end_time", end,
 				)
-				response, err = cli.LwApi.Events.ListDateRange(start, end)
+				response, err = listEventsDateRange(cmd.Context(), start, end)
 			} else if eventsCmdState.Days != 0 {
 				end := time.Now()
 				start := end.Add(time.Hour * 24 * time.Duration(eventsCmdState.Days) * -1)
@@ -108,11 +270,12 @@ For example, to list all events from the last day with severity medium and above
 				cli.Log.Infow("requesting list of events from specific days",
 					"days", eventsCmdState.Days, "start_time", start, "end_time", end,
 				)
-				response, err = cli.LwApi.Events.ListDateRange(start, end)
+				response, err = listEventsDateRange(cmd.Context(), start, end)
 			} else {
 				cli.Log.Info("requesting list of events from the last 7 days")
-				response, err = cli.LwApi.Events.List()
+				response, err = listEvents(cmd.Context())
 			}
+			cli.StopProgress()
 
 			if err != nil {
 				return errors.Wrap(err, "unable to get events")
@@ -124,38 +287,98 @@ For example, to list all events from the last day with severity medium and above
 			// the funtion will return it back without modifications
 			events := filterEventsWithSeverity(response.Events)
 
+			if eventsCmdState.SinceLast {
+				events = filterEventsStartedAfter(events, bookmark)
+				if err := storeEventsBookmark(bookmarkEnd); err != nil {
+					return errors.Wrap(err, "unable to store events bookmark")
+				}
+			}
+
 			// Sort the events by severity
 			sort.Slice(events, func(i, j int) bool {
 				return events[i].Severity < events[j].Severity
 			})
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(events)
+			if eventsCmdState.TypesOnly {
+				return outputEventTypeCounts(countEventsByType(events))
 			}
 
-			if len(events) == 0 {
-				if eventsCmdState.Severity != "" {
-					cli.OutputHuman("There are no events with the specified severity.\n")
-				} else {
-					cli.OutputHuman("There are no events in your account in the specified time range.\n")
+			if eventsCmdState.OutputDir != "" {
+				return outputEventsBySeverity(eventsCmdState.OutputDir, events, columns)
+			}
+
+			if cli.CSVOutput() {
+				return cli.OutputCSV(eventsTableHeaders(columns), eventsToTable(events, columns))
+			}
+
+			if cli.StructuredOutput() {
+				if eventsCmdState.GroupByType {
+					return cli.OutputStructured(groupEventsByType(events))
 				}
+				return cli.OutputStructured(events)
+			}
+
+			if len(events) == 0 {
+				cli.OutputHuman(noEventsMessage())
 				return nil
 			}
 
-			cli.OutputHuman(eventsToTableReport(events))
+			cli.StartPager()
+			defer cli.StopPager()
+
+			if eventsCmdState.GroupByType {
+				cli.OutputHuman(eventsGroupedByTypeReport(events, columns))
+				return nil
+			}
+
+			cli.OutputHuman(eventsToTableReport(events, columns))
 			return nil
 		},
 	}
 
 	// eventShowCmd represents the show sub-command inside the event command
 	eventShowCmd = &cobra.Command{
-		Use:   "show <event_id>",
-		Short: "show details about a specific event",
-		Long:  "Show details about a specific event.",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		Use:               "show <event_id>",
+		Short:             "show details about a specific event",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeEventIDs,
+		Long: `Show details about a specific event.
+
+The IP address table of an event has many columns, when it does not fit
+in the terminal, only the identity and threat columns are displayed. Use
+the flag --wide to always display every column, or --narrow to always
+display only the identity and threat columns.
+
+Any other table that does not fit in the terminal is expanded to display
+every record vertically as key/value pairs, use the flag --expand to always
+display the tables expanded.
+
+To highlight the indicators that appeared recently, use the flag
+--first-seen-after to only show the IP addresses, file hashes and executable
+paths first seen after the provided time:
+
+    $ lacework events show my_event_id --first-seen-after 2020-08-20T08:00:00Z
+
+During IOC hunting, use the flag --known-bad-only to only show the file hashes
+flagged as known-bad, instead of every entity of the event:
+
+    $ lacework events show my_event_id --known-bad-only`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if eventsCmdState.Wide && eventsCmdState.Narrow {
+				return errors.New("cannot combine --wide with --narrow")
+			}
+
+			var firstSeenAfter time.Time
+			if eventsCmdState.FirstSeenAfter != "" {
+				var err error
+				firstSeenAfter, err = parseTimeRangeFlag("first-seen-after", eventsCmdState.FirstSeenAfter)
+				if err != nil {
+					return err
+				}
+			}
+
 			cli.Log.Infow("requesting event details", "event_id", args[0])
-			response, err := cli.LwApi.Events.Details(args[0])
+			response, err := cli.LwApi.Events.DetailsWithContext(cmd.Context(), args[0])
 			if err != nil {
 				return errors.Wrap(err, "unable to get event details")
 			}
@@ -168,22 +391,73 @@ For example, to list all events from the last day with severity medium and above
 				return errors.Errorf("there are no details about the event '%s'", args[0])
 			}
 
-			// @afiune why do we have an array of events when we ask for details
-			// about a single event? Let us use the first one for now
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response.Events[0])
+			if !firstSeenAfter.IsZero() {
+				for i := range response.Events {
+					filterEventEntitiesFirstSeenAfter(&response.Events[i].EntityMap, firstSeenAfter)
+				}
+			}
+
+			if eventsCmdState.KnownBadOnly {
+				for i := range response.Events {
+					response.Events[i].EntityMap = knownBadEventEntities(response.Events[i].EntityMap)
+				}
 			}
 
-			cli.OutputHuman(eventDetailsSummaryReport(response.Events[0]))
-			for _, entityTable := range eventEntityMapTables(response.Events[0].EntityMap) {
-				cli.OutputHuman("\n")
-				cli.OutputHuman(entityTable)
+			// the API returns an array of events when we ask for details about
+			// a single event, display all of them to avoid hiding any data
+			if cli.StructuredOutput() {
+				if eventsCmdState.CopyLink {
+					copyEventLink(args[0])
+				}
+				return cli.OutputStructured(response.Events)
 			}
 
-			cli.OutputHuman(
-				"\nFor further investigation of this event navigate to %s\n",
-				eventLinkBuilder(args[0]),
-			)
+			cli.StartPager()
+			defer cli.StopPager()
+
+			for i, details := range response.Events {
+				if i != 0 {
+					cli.OutputHuman("\n%s\n\n", eventDetailsSeparator)
+				}
+				cli.OutputHuman(eventDetailsReport(details))
+			}
+
+			if !cli.Quiet() {
+				cli.OutputHuman(
+					"\nFor further investigation of this event navigate to %s\n",
+					eventLinkBuilder(args[0]),
+				)
+			}
+
+			if eventsCmdState.CopyLink && copyEventLink(args[0]) && !cli.Quiet() {
+				cli.OutputHuman("\nThe URL of this event has been copied to your clipboard.\n")
+			}
+			return nil
+		},
+	}
+
+	// eventLinkCmd represents the link sub-command inside the event command
+	eventLinkCmd = &cobra.Command{
+		Use:   "link <event_id>",
+		Short: "copy the URL of a specified event to the clipboard",
+		Long: `Copy the URL of a specified event to the clipboard.
+
+The clipboard is accessed via pbcopy on macOS, clip.exe on Windows and WSL,
+and wl-copy, xclip or xsel on Linux. When there is no clipboard available,
+the URL of the event is printed instead.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeEventIDs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := validateEventID(args[0]); err != nil {
+				return err
+			}
+
+			if copyEventLink(args[0]) {
+				cli.OutputHuman("The URL of the event %s has been copied to your clipboard.\n", args[0])
+				return nil
+			}
+
+			cli.OutputHuman("%s\n", eventLinkBuilder(args[0]))
 			return nil
 		},
 	}
@@ -192,33 +466,40 @@ For example, to list all events from the last day with severity medium and above
 	eventOpenCmd = &cobra.Command{
 		Use:   "open <event_id>",
 		Short: "open a specified event in a web browser",
-		Long:  "Open a specified event in a web browser.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Open a specified event in a web browser.
+
+When there is no web browser available, like on headless servers, the URL of
+the event is printed and copied to the clipboard (if possible). Under the
+Windows Subsystem for Linux (WSL), the browser of the Windows host is used.
+
+Use the flag --print-only to only print the URL without launching a browser.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeEventIDs,
 		RunE: func(_ *cobra.Command, args []string) error {
-			// Event IDs should be only numeric values
-			if _, err := strconv.Atoi(args[0]); err != nil {
-				return errors.Errorf("invalid event id %s. Event id should be a numeric value", args[0])
+			if err := validateEventID(args[0]); err != nil {
+				return err
 			}
 
-			var (
-				err error
-				url = eventLinkBuilder(args[0])
-			)
+			url := eventLinkBuilder(args[0])
+			if eventsCmdState.PrintOnly {
+				cli.OutputHuman("%s\n", url)
+				return nil
+			}
 
-			switch runtime.GOOS {
-			case "linux":
-				err = exec.Command("xdg-open", url).Start()
-			case "windows":
-				err = exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
-			case "darwin":
-				err = exec.Command("open", url).Start()
-			default:
-				err = fmt.Errorf("unsupported platform\n\nNavigate to %s", url)
+			err := openURL(url)
+			if err == nil {
+				return nil
 			}
-			if err != nil {
+			if err != errNoBrowser {
 				return errors.Wrap(err, "unable to open web browser")
 			}
 
+			cli.Log.Debugw("unable to open web browser", "error", err)
+			cli.OutputHuman("Unable to open a web browser, navigate to:\n\n  %s\n", url)
+			if copyEventLink(args[0]) && !cli.Quiet() {
+				cli.OutputHuman("\nThe URL has been copied to your clipboard.\n")
+			}
+
 			return nil
 		},
 	}
@@ -241,7 +522,7 @@ func init() {
 	)
 	// add days flag to events list command
 	eventListCmd.Flags().IntVar(&eventsCmdState.Days,
-		"days", 0, "list events for specified number of days (max: 7 days)",
+		"days", 0, fmt.Sprintf("list events for specified number of days (max: %d days)", eventsMaxDays),
 	)
 	// add severity flag to events list command
 	eventListCmd.Flags().StringVar(&eventsCmdState.Severity,
@@ -251,191 +532,611 @@ func init() {
 			strings.Join(api.ValidEventSeverities, ", "),
 		),
 	)
+	errcheckWARN(eventListCmd.RegisterFlagCompletionFunc("severity",
+		completeWithValues(api.ValidEventSeverities),
+	))
+	// add type flag to events list command
+	eventListCmd.Flags().StringSliceVar(&eventsCmdState.Types,
+		"type", []string{},
+		"list only the events of the specified event types (can be repeated)",
+	)
+	// add columns flag to events list command
+	eventListCmd.Flags().StringSliceVar(&eventsCmdState.Columns,
+		"columns", []string{},
+		fmt.Sprintf(
+			"comma separated list of columns to display (%s)",
+			strings.Join(eventsTableColumnNames(), ", "),
+		),
+	)
+	errcheckWARN(eventListCmd.RegisterFlagCompletionFunc("columns",
+		completeWithValues(eventsTableColumnNames()),
+	))
+	// add since-last and reset-bookmark flags to events list command
+	eventListCmd.Flags().BoolVar(&eventsCmdState.SinceLast,
+		"since-last", false, "list only the events newer than the last run with this flag",
+	)
+	eventListCmd.Flags().BoolVar(&eventsCmdState.ResetBookmark,
+		"reset-bookmark", false, "clear the bookmark of the flag --since-last",
+	)
+	// add query flag to events list command
+	eventListCmd.Flags().StringVar(&eventsCmdState.Query,
+		"query", "", "load the filters from a saved query file (JSON or TOML)",
+	)
+	// add types-only flag to events list command
+	eventListCmd.Flags().BoolVar(&eventsCmdState.TypesOnly,
+		"types-only", false, "list only the distinct event types with the number of events",
+	)
+	// add group-by-type flag to events list command
+	eventListCmd.Flags().BoolVar(&eventsCmdState.GroupByType,
+		"group-by-type", false, "display one table per event type, ordered by the number of events",
+	)
+	// add output-dir flag to events list command
+	eventListCmd.Flags().StringVar(&eventsCmdState.OutputDir,
+		"output-dir", "", "write one file of events per severity into the specified directory",
+	)
 
 	eventCmd.AddCommand(eventShowCmd)
 	eventCmd.AddCommand(eventOpenCmd)
+	eventOpenCmd.Flags().BoolVar(&eventsCmdState.PrintOnly,
+		"print-only", false, "only print the URL of the event, never launch a web browser",
+	)
+
+	// add wide and narrow flags to events show command
+	eventShowCmd.Flags().BoolVar(&eventsCmdState.Wide,
+		"wide", false, "display every column of the IP address table",
+	)
+	eventShowCmd.Flags().BoolVar(&eventsCmdState.Narrow,
+		"narrow", false, "display only the identity and threat columns of the IP address table",
+	)
+	eventShowCmd.Flags().BoolVar(&eventsCmdState.Expand,
+		"expand", false, "display every record of the tables vertically as key/value pairs",
+	)
+	eventShowCmd.Flags().BoolVar(&eventsCmdState.CopyLink,
+		"copy-link", false, "copy the URL of the event to the clipboard",
+	)
+	eventShowCmd.Flags().BoolVar(&eventsCmdState.KnownBadOnly,
+		"known-bad-only", false, "only show the file hashes flagged as known-bad",
+	)
+	eventShowCmd.Flags().StringVar(&eventsCmdState.FirstSeenAfter,
+		"first-seen-after", "",
+		"only show the IP addresses and files first seen after this time (format: yyyy-MM-ddTHH:mm:ssZ)",
+	)
+
+	// add the link sub-command to the event command
+	eventCmd.AddCommand(eventLinkCmd)
 }
 
+// validateEventID checks that the provided event id is a numeric value
+func validateEventID(id string) error {
+	if _, err := strconv.Atoi(id); err != nil {
+		return errors.Errorf("invalid event id %s. Event id should be a numeric value", id)
+	}
+	return nil
+}
+
+// copyEventLink copies the URL of the provided event to the clipboard,
+// it returns false when there is no clipboard available
+func copyEventLink(id string) bool {
+	if err := copyToClipboard(eventLinkBuilder(id)); err != nil {
+		cli.Log.Debugw("unable to copy url to clipboard", "error", err)
+		return false
+	}
+	return true
+}
+
+// defaultUIDomain is the domain of the Lacework UI, it can be configured
+// per profile or with the environment variable LW_DOMAIN
+const defaultUIDomain = "lacework.net"
+
 // Generates a URL similar to:
-//   => https://account.lacework.net/ui/investigate/recents/EventDossier-123
+//
+//	=> https://account.lacework.net/ui/investigate/recents/EventDossier-123
+//
+// If the profile has a custom domain, it is used instead of lacework.net,
+// otherwise, if the profile has a custom API URL, it is used as the base URL
 func eventLinkBuilder(id string) string {
-	return fmt.Sprintf("https://%s.lacework.net/ui/investigation/recents/EventDossier-%s", cli.Account, id)
-}
+	if cli.Domain == "" && cli.ApiURL != "" {
+		return fmt.Sprintf("%s/ui/investigation/recents/EventDossier-%s",
+			strings.TrimSuffix(cli.ApiURL, "/"), id)
+	}
 
-func eventsToTableReport(events []api.Event) string {
-	var (
-		eventsReport = &strings.Builder{}
-		t            = tablewriter.NewWriter(eventsReport)
-	)
+	domain := defaultUIDomain
+	if cli.Domain != "" {
+		domain = strings.Trim(cli.Domain, "./")
+	}
+	return fmt.Sprintf("https://%s.%s/ui/investigation/recents/EventDossier-%s", cli.Account, domain, id)
+}
 
-	t.SetHeader([]string{
-		"Event ID",
-		"Type",
-		"Severity",
-		"Start Time",
-		"End Time",
-	})
-	t.SetBorder(false)
-	t.AppendBulk(eventsToTable(events))
-	t.Render()
+// eventsTableColumn is a column of the table and CSV outputs of events
+type eventsTableColumn struct {
+	// Name is the name used to select the column with the flag --columns
+	Name   string
+	Header string
+	Value  func(api.Event) string
+}
 
-	return eventsReport.String()
+// eventsTableColumns are all the columns of the table and CSV outputs of
+// events, in the order they are displayed by default
+var eventsTableColumns = []eventsTableColumn{
+	{"id", "Event ID", func(e api.Event) string { return e.EventID }},
+	{"type", "Type", func(e api.Event) string { return e.EventType }},
+	{"severity", "Severity", func(e api.Event) string {
+		return cli.colorizeSeverity(e.SeverityString())
+	}},
+	{"start", "Start Time", func(e api.Event) string {
+		return cli.FormatTime(e.StartTime)
+	}},
+	{"end", "End Time", func(e api.Event) string {
+		return cli.FormatTime(e.EndTime)
+	}},
 }
 
-func eventsToTable(events []api.Event) [][]string {
-	out := [][]string{}
-	for _, event := range events {
-		out = append(out, []string{
-			event.EventID,
-			event.EventType,
-			event.SeverityString(),
-			event.StartTime.UTC().Format(time.RFC3339),
-			event.EndTime.UTC().Format(time.RFC3339),
-		})
+// eventsTableColumnNames returns the names of all the columns of events
+func eventsTableColumnNames() []string {
+	names := make([]string, len(eventsTableColumns))
+	for i, column := range eventsTableColumns {
+		names[i] = column.Name
 	}
-	return out
+	return names
 }
 
-func eventDetailsSummaryReport(details api.EventDetails) string {
+// selectEventsTableColumns returns the columns matching the provided names
+// in the same order, if no names are provided, it returns all the columns
+func selectEventsTableColumns(names []string) ([]eventsTableColumn, error) {
+	if len(names) == 0 {
+		return eventsTableColumns, nil
+	}
+
 	var (
-		report = &strings.Builder{}
-		t      = tablewriter.NewWriter(report)
+		columns  = []eventsTableColumn{}
+		selected = map[string]bool{}
 	)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if selected[name] {
+			return nil, errors.Errorf("the column '%s' was selected more than once", name)
+		}
 
-	t.SetHeader([]string{
-		"Event ID",
-		"Type",
-		"Actor",
-		"Model",
-		"Start Time",
-		"End Time",
-	})
-	t.SetBorder(eventDetailsBorder)
-	t.Append([]string{
-		details.EventID,
-		details.EventType,
-		details.EventActor,
-		details.EventModel,
-		details.StartTime.UTC().Format(time.RFC3339),
-		details.EndTime.UTC().Format(time.RFC3339),
+		found := false
+		for _, column := range eventsTableColumns {
+			if column.Name == name {
+				columns = append(columns, column)
+				selected[name] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("unknown column '%s', use one of %s",
+				name, strings.Join(eventsTableColumnNames(), ", "),
+			)
+		}
+	}
+	return columns, nil
+}
+
+// eventsTableHeaders returns the headers of the provided columns
+func eventsTableHeaders(columns []eventsTableColumn) []string {
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	return headers
+}
+
+func eventsToTableReport(events []api.Event, columns []eventsTableColumn) string {
+	return cli.RenderTable(eventsTableHeaders(columns), eventsToTable(events, columns))
+}
+
+// eventTypeGroup are the events of a single event type
+type eventTypeGroup struct {
+	EventType string      `json:"event_type"`
+	Count     int         `json:"count"`
+	Events    []api.Event `json:"events"`
+}
+
+// groupEventsByType groups the provided events by event type, the groups are
+// ordered by the number of events in descending order, then by event type,
+// the events of every group keep the order of the provided events
+func groupEventsByType(events []api.Event) []eventTypeGroup {
+	byType := map[string][]api.Event{}
+	for _, event := range events {
+		byType[event.EventType] = append(byType[event.EventType], event)
+	}
+
+	groups := make([]eventTypeGroup, 0, len(byType))
+	for eventType, typeEvents := range byType {
+		groups = append(groups, eventTypeGroup{eventType, len(typeEvents), typeEvents})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].EventType < groups[j].EventType
 	})
-	t.Render()
+	return groups
+}
 
+// eventsGroupedByTypeReport renders one section per event type, every section
+// has a header with the event type and the number of events, and a table
+func eventsGroupedByTypeReport(events []api.Event, columns []eventsTableColumn) string {
+	report := &strings.Builder{}
+	for i, group := range groupEventsByType(events) {
+		if i != 0 {
+			report.WriteString("\n")
+		}
+		report.WriteString(fmt.Sprintf("%s (%d events)\n\n", group.EventType, group.Count))
+		report.WriteString(eventsToTableReport(group.Events, columns))
+	}
 	return report.String()
 }
 
-func eventEntityMapTables(eventEntities api.EventEntityMap) []string {
-	tables := []string{}
+// eventSeverityGroup are the events of a single severity
+type eventSeverityGroup struct {
+	Severity api.Severity
+	Events   []api.Event
+}
 
-	if machineTable := eventMachineEntitiesTable(eventEntities.Machine); machineTable != "" {
-		tables = append(tables, machineTable)
+// groupEventsBySeverity groups the provided events by severity, the groups are
+// ordered from the most severe to the least severe, severities without events
+// are skipped and the events of every group keep the order of the provided events
+func groupEventsBySeverity(events []api.Event) []eventSeverityGroup {
+	bySeverity := map[api.Severity][]api.Event{}
+	for _, event := range events {
+		severity := api.ParseSeverity(event.Severity)
+		bySeverity[severity] = append(bySeverity[severity], event)
 	}
-	if containerTable := eventContainerEntitiesTable(eventEntities.Container); containerTable != "" {
-		tables = append(tables, containerTable)
+
+	groups := make([]eventSeverityGroup, 0, len(bySeverity))
+	for severity, severityEvents := range bySeverity {
+		groups = append(groups, eventSeverityGroup{severity, severityEvents})
 	}
-	if appTable := eventApplicationEntitiesTable(eventEntities.Application); appTable != "" {
-		tables = append(tables, appTable)
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Severity.Order() < groups[j].Severity.Order()
+	})
+	return groups
+}
+
+// noEventsMessage returns the message to display when there are no events
+func noEventsMessage() string {
+	if eventsCmdState.SinceLast {
+		return "There are no new events since the last time you listed them.\n"
 	}
-	if userTable := eventUserEntitiesTable(eventEntities.User); userTable != "" {
-		tables = append(tables, userTable)
+	if eventsCmdState.Severity != "" {
+		return "There are no events with the specified severity.\n"
 	}
-	if ipaddressTable := eventIpAddressEntitiesTable(eventEntities.IpAddress); ipaddressTable != "" {
-		tables = append(tables, ipaddressTable)
+	if len(eventsCmdState.Types) != 0 {
+		return "There are no events of the specified types.\n"
 	}
-	if sourceIpAddrTable := eventSourceIpAddressEntitiesTable(eventEntities.SourceIpAddress); sourceIpAddrTable != "" {
-		tables = append(tables, sourceIpAddrTable)
+	return "There are no events in your account in the specified time range.\n"
+}
+
+// listEvents lists the events of the last 7 days, when the flag --type is
+// provided, only the events of the specified types are returned
+func listEvents(ctx context.Context) (api.EventsResponse, error) {
+	if len(eventsCmdState.Types) != 0 {
+		return cli.LwApi.Events.ListByTypeWithContext(ctx, eventsCmdState.Types...)
 	}
-	if dnsTable := eventDnsNameEntitiesTable(eventEntities.DnsName); dnsTable != "" {
-		tables = append(tables, dnsTable)
+	return cli.LwApi.Events.ListWithContext(ctx)
+}
+
+// listEventsDateRange is like listEvents but for the provided date range
+func listEventsDateRange(ctx context.Context, start, end time.Time) (api.EventsResponse, error) {
+	if len(eventsCmdState.Types) != 0 {
+		return cli.LwApi.Events.ListDateRangeByTypeWithContext(ctx, start, end, eventsCmdState.Types...)
 	}
-	if apiTable := eventAPIEntitiesTable(eventEntities.API); apiTable != "" {
-		tables = append(tables, apiTable)
+	return cli.LwApi.Events.ListDateRangeWithContext(ctx, start, end)
+}
+
+// countEventsByType returns the number of events of each event type
+func countEventsByType(events []api.Event) map[string]int {
+	counts := map[string]int{}
+	for _, event := range events {
+		counts[event.EventType]++
 	}
-	if ctUserTable := eventCTUserEntitiesTable(eventEntities.CTUser); ctUserTable != "" {
-		tables = append(tables, ctUserTable)
+	return counts
+}
+
+// eventTypeCountsToTable returns the rows of the provided event type counts,
+// sorted by the number of events in descending order, then by event type
+func eventTypeCountsToTable(counts map[string]int) [][]string {
+	types := make([]string, 0, len(counts))
+	for eventType := range counts {
+		types = append(types, eventType)
 	}
-	if regionTable := eventRegionEntitiesTable(eventEntities.Region); regionTable != "" {
-		tables = append(tables, regionTable)
+	sort.Slice(types, func(i, j int) bool {
+		if counts[types[i]] != counts[types[j]] {
+			return counts[types[i]] > counts[types[j]]
+		}
+		return types[i] < types[j]
+	})
+
+	out := make([][]string, len(types))
+	for i, eventType := range types {
+		out[i] = []string{eventType, fmt.Sprintf("%d", counts[eventType])}
 	}
-	if processTable := eventProcessEntitiesTable(eventEntities.Process); processTable != "" {
-		tables = append(tables, processTable)
+	return out
+}
+
+// outputEventTypeCounts displays the provided event type counts in the
+// selected output format, the JSON output is a map of event types to counts
+func outputEventTypeCounts(counts map[string]int) error {
+	headers := []string{"Event Type", "Count"}
+	if cli.CSVOutput() {
+		return cli.OutputCSV(headers, eventTypeCountsToTable(counts))
 	}
-	if exePathTable := eventFileExePathEntitiesTable(eventEntities.FileExePath); exePathTable != "" {
-		tables = append(tables, exePathTable)
+
+	if cli.StructuredOutput() {
+		return cli.OutputStructured(counts)
 	}
-	if dataHashTable := eventFileDataHashEntitiesTable(eventEntities.FileDataHash); dataHashTable != "" {
-		tables = append(tables, dataHashTable)
+
+	if len(counts) == 0 {
+		cli.OutputHuman(noEventsMessage())
+		return nil
 	}
-	if cRuleTable := eventCustomRuleEntitiesTable(eventEntities.CustomRule); cRuleTable != "" {
-		tables = append(tables, cRuleTable)
+
+	cli.OutputHuman(cli.RenderTable(headers, eventTypeCountsToTable(counts)))
+	return nil
+}
+
+func eventsToTable(events []api.Event, columns []eventsTableColumn) [][]string {
+	out := [][]string{}
+	for _, event := range events {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = column.Value(event)
+		}
+		out = append(out, row)
 	}
-	if violationTable := eventNewViolationEntitiesTable(eventEntities.NewViolation); violationTable != "" {
-		tables = append(tables, violationTable)
+	return out
+}
+
+// eventDetailsSeparator separates the reports of multiple event details
+var eventDetailsSeparator = strings.Repeat("-", 80)
+
+// eventDetailsReport builds the summary and the entity tables of an event
+// sized to the width of the terminal
+func eventDetailsReport(details api.EventDetails) string {
+	return eventDetailsReportWithWidth(details, terminalWidth())
+}
+
+// eventDetailsReportWithWidth builds the summary and the entity tables of an
+// event, the tables that do not fit in the provided width are narrowed or
+// expanded, a width of zero means that the width is unknown
+func eventDetailsReportWithWidth(details api.EventDetails, width int) string {
+	report := &strings.Builder{}
+	report.WriteString(eventDetailsSummaryReport(details, width))
+	if eventsCmdState.KnownBadOnly && len(details.EntityMap.FileDataHash) == 0 {
+		report.WriteString("\nGood news! There are no file hashes flagged as known-bad in this event.\n")
+		return report.String()
 	}
-	if recordsTable := eventRecIDEntitiesTable(eventEntities.RecID); recordsTable != "" {
-		tables = append(tables, recordsTable)
+	for _, entityTable := range eventEntityMapTables(details.EntityMap, width) {
+		report.WriteString("\n")
+		report.WriteString(entityTable)
 	}
-	if vReasonTable := eventViolationReasonEntitiesTable(eventEntities.ViolationReason); vReasonTable != "" {
-		tables = append(tables, vReasonTable)
+	return report.String()
+}
+
+// eventDetailsSummaryReport renders the summary of an event, the summary is
+// expanded vertically when it does not fit in the provided width
+func eventDetailsSummaryReport(details api.EventDetails, width int) string {
+	var (
+		headers = []string{
+			"Event ID",
+			"Type",
+			"Actor",
+			"Model",
+			"Start Time",
+			"End Time",
+		}
+		rows = [][]string{{
+			details.EventID,
+			details.EventType,
+			details.EventActor,
+			details.EventModel,
+			cli.FormatTime(details.StartTime),
+			cli.FormatTime(details.EndTime),
+		}}
+		table = cli.RenderTable(headers, rows, withTableBorder(eventDetailsBorder))
+	)
+	if fitsInWidth(table, width) {
+		return table
 	}
-	if resourceTable := eventResourceEntitiesTable(eventEntities.Resource); resourceTable != "" {
-		tables = append(tables, resourceTable)
+	return eventEntitiesVerticalTable(headers, rows, width)
+}
+
+// eventEntityRows are the headers and rows of a table of entities of an event,
+// the name identifies the table in the files generated by 'events export'
+type eventEntityRows struct {
+	Name    string
+	Headers []string
+	Rows    [][]string
+}
+
+// newEventEntityRows names the headers and rows returned by the
+// event*EntitiesRows() helpers
+func newEventEntityRows(name string, headers []string, rows [][]string) eventEntityRows {
+	return eventEntityRows{Name: name, Headers: headers, Rows: rows}
+}
+
+// eventEntityMapRows returns the tables of the entities of an event in the
+// order they are displayed, the IP address table is always the wide table
+func eventEntityMapRows(e api.EventEntityMap) []eventEntityRows {
+	tables := []eventEntityRows{}
+
+	headers, rows := eventMachineEntitiesRows(e.Machine)
+	tables = append(tables, newEventEntityRows("machines", headers, rows))
+
+	headers, rows = eventContainerEntitiesRows(e.Container)
+	tables = append(tables, newEventEntityRows("containers", headers, rows))
+
+	headers, rows = eventApplicationEntitiesRows(e.Application)
+	tables = append(tables, newEventEntityRows("applications", headers, rows))
+
+	headers, rows = eventUserEntitiesRows(e.User)
+	tables = append(tables, newEventEntityRows("users", headers, rows))
+
+	headers, rows = eventIpAddressEntitiesRows(e.IpAddress, false)
+	tables = append(tables, newEventEntityRows("ip_addresses", headers, rows))
+
+	headers, rows = eventSourceIpAddressEntitiesRows(e.SourceIpAddress)
+	tables = append(tables, newEventEntityRows("source_ip_addresses", headers, rows))
+
+	headers, rows = eventDnsNameEntitiesRows(e.DnsName)
+	tables = append(tables, newEventEntityRows("dns_names", headers, rows))
+
+	headers, rows = eventAPIEntitiesRows(e.API)
+	tables = append(tables, newEventEntityRows("apis", headers, rows))
+
+	headers, rows = eventCTUserEntitiesRows(e.CTUser)
+	tables = append(tables, newEventEntityRows("ct_users", headers, rows))
+
+	headers, rows = eventRegionEntitiesRows(e.Region)
+	tables = append(tables, newEventEntityRows("regions", headers, rows))
+
+	headers, rows = eventProcessEntitiesRows(e.Process)
+	tables = append(tables, newEventEntityRows("processes", headers, rows))
+
+	headers, rows = eventFileExePathEntitiesRows(e.FileExePath)
+	tables = append(tables, newEventEntityRows("file_exe_paths", headers, rows))
+
+	headers, rows = eventFileDataHashEntitiesRows(e.FileDataHash)
+	tables = append(tables, newEventEntityRows("file_data_hashes", headers, rows))
+
+	headers, rows = eventCustomRuleEntitiesRows(e.CustomRule)
+	tables = append(tables, newEventEntityRows("custom_rules", headers, rows))
+
+	headers, rows = eventNewViolationEntitiesRows(e.NewViolation)
+	tables = append(tables, newEventEntityRows("new_violations", headers, rows))
+
+	headers, rows = eventRecIDEntitiesRows(e.RecID)
+	tables = append(tables, newEventEntityRows("records", headers, rows))
+
+	headers, rows = eventViolationReasonEntitiesRows(e.ViolationReason)
+	tables = append(tables, newEventEntityRows("violation_reasons", headers, rows))
+
+	headers, rows = eventResourceEntitiesRows(e.Resource)
+	tables = append(tables, newEventEntityRows("resources", headers, rows))
+
+	return tables
+}
+
+func eventEntityMapTables(eventEntities api.EventEntityMap, width int) []string {
+	tables := []string{}
+
+	for _, entity := range eventEntityMapRows(eventEntities) {
+		if len(entity.Rows) == 0 {
+			continue
+		}
+
+		// the IP address and custom rule tables have their own layout
+		switch entity.Name {
+		case "ip_addresses":
+			tables = append(tables, eventIpAddressEntitiesTable(eventEntities.IpAddress, width))
+		case "custom_rules":
+			tables = append(tables, eventCustomRuleEntitiesTable(eventEntities.CustomRule))
+		default:
+			tables = append(tables, eventEntitiesTable(entity.Headers, entity.Rows, width))
+		}
 	}
 
 	return tables
 }
 
-func eventRegionEntitiesTable(regions []api.EventRegionEntity) string {
+// eventEntitiesTable renders the provided headers and rows as a table, when
+// the table does not fit in the provided width, or when the flag --expand is
+// provided, the table is expanded to display every record vertically
+func eventEntitiesTable(headers []string, rows [][]string, width int) string {
+	if !eventsCmdState.Expand {
+		table := eventEntitiesHorizontalTable(headers, rows)
+		if fitsInWidth(table, width) {
+			return table
+		}
+	}
+	return eventEntitiesVerticalTable(headers, rows, width)
+}
+
+func eventEntitiesHorizontalTable(headers []string, rows [][]string) string {
+	return cli.RenderTable(headers, rows, withTableBorder(eventDetailsBorder))
+}
+
+// eventEntitiesVerticalTable renders every record as a key/value table where
+// the keys are the headers, long values are wrapped to fit in the provided width
+func eventEntitiesVerticalTable(headers []string, rows [][]string, width int) string {
+	keyWidth := 0
+	for _, header := range headers {
+		if len(header) > keyWidth {
+			keyWidth = len(header)
+		}
+	}
+
+	// the borders and padding of a two column table take 7 characters
+	valueWidth := width - keyWidth - 7
+	if valueWidth < tablewriter.MAX_ROW_WIDTH {
+		valueWidth = tablewriter.MAX_ROW_WIDTH
+	}
+
+	records := make([]string, len(rows))
+	for i, row := range rows {
+		keyValues := make([][]string, len(row))
+		for j, value := range row {
+			keyValues[j] = []string{headers[j], value}
+		}
+
+		records[i] = cli.RenderTable(nil, keyValues,
+			withTableBorder(eventDetailsBorder),
+			withTableColWidth(valueWidth),
+			withTableAlignment(tablewriter.ALIGN_LEFT),
+		)
+	}
+
+	return strings.Join(records, "")
+}
+
+func eventRegionEntitiesRows(regions []api.EventRegionEntity) ([]string, [][]string) {
 	if len(regions) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Region",
+			"Accounts",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Region",
-		"Accounts",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, user := range regions {
-		t.Append([]string{
+		rows = append(rows, []string{
 			user.Region,
 			strings.Join(user.AccountList, ", "),
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventCTUserEntitiesTable(users []api.EventCTUserEntity) string {
+func eventCTUserEntitiesRows(users []api.EventCTUserEntity) ([]string, [][]string) {
 	if len(users) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Username",
+			"Account ID",
+			"Principal ID",
+			"MFA",
+			"List of APIs",
+			"Regions",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Username",
-		"Account ID",
-		"Principal ID",
-		"MFA",
-		"List of APIs",
-		"Regions",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, user := range users {
 		mfa := "Disabled"
 		if user.Mfa != 0 {
 			mfa = "Enabled"
 		}
-		t.Append([]string{
+		rows = append(rows, []string{
 			user.Username,
 			user.AccountID,
 			user.PrincipalID,
@@ -444,257 +1145,297 @@ func eventCTUserEntitiesTable(users []api.EventCTUserEntity) string {
 			strings.Join(user.RegionList, ", "),
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventDnsNameEntitiesTable(dnss []api.EventDnsNameEntity) string {
+func eventDnsNameEntitiesRows(dnss []api.EventDnsNameEntity) ([]string, [][]string) {
 	if len(dnss) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"DNS Hostname",
+			"List of Ports",
+			"Inbound Bytes",
+			"Outboud Bytes",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"DNS Hostname",
-		"List of Ports",
-		"Inbound Bytes",
-		"Outboud Bytes",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, d := range dnss {
-		t.Append([]string{
+		rows = append(rows, []string{
 			d.Hostname,
 			array.JoinInt32(d.PortList, ", "),
 			fmt.Sprintf("%.3f", d.TotalInBytes),
 			fmt.Sprintf("%.3f", d.TotalOutBytes),
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventAPIEntitiesTable(apis []api.EventAPIEntity) string {
+func eventAPIEntitiesRows(apis []api.EventAPIEntity) ([]string, [][]string) {
 	if len(apis) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Service",
+			"API",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Service",
-		"API",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, a := range apis {
-		t.Append([]string{
+		rows = append(rows, []string{
 			a.Service,
 			a.Api,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventSourceIpAddressEntitiesTable(ips []api.EventSourceIpAddressEntity) string {
+func eventSourceIpAddressEntitiesRows(ips []api.EventSourceIpAddressEntity) ([]string, [][]string) {
 	if len(ips) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Source IP Address",
+			"Country",
+			"Region",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Source IP Address",
-		"Country",
-		"Region",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, ip := range ips {
-		t.Append([]string{
+		rows = append(rows, []string{
 			ip.IpAddress,
 			ip.Country,
 			ip.Region,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
+}
+
+// knownBadEventEntities returns only the entities flagged as known-bad, at
+// the moment, the file data hash entities are the only ones with such a flag
+func knownBadEventEntities(entities api.EventEntityMap) api.EventEntityMap {
+	knownBad := api.EventEntityMap{}
+	for _, dHash := range entities.FileDataHash {
+		if dHash.IsKnownBad != 0 {
+			knownBad.FileDataHash = append(knownBad.FileDataHash, dHash)
+		}
+	}
+	return knownBad
+}
+
+// filterEventEntitiesFirstSeenAfter prunes the IP address, file data hash and
+// file executable path entities first seen at or before the provided time
+func filterEventEntitiesFirstSeenAfter(entities *api.EventEntityMap, after time.Time) {
+	ips := []api.EventIpAddressEntity{}
+	for _, ip := range entities.IpAddress {
+		if ip.FirstSeenTime.After(after) {
+			ips = append(ips, ip)
+		}
+	}
+	entities.IpAddress = ips
+
+	dataHashes := []api.EventFileDataHashEntity{}
+	for _, dHash := range entities.FileDataHash {
+		if dHash.FirstSeenTime.After(after) {
+			dataHashes = append(dataHashes, dHash)
+		}
+	}
+	entities.FileDataHash = dataHashes
+
+	exePaths := []api.EventFileExePathEntity{}
+	for _, exe := range entities.FileExePath {
+		if exe.FirstSeenTime.After(after) {
+			exePaths = append(exePaths, exe)
+		}
+	}
+	entities.FileExePath = exePaths
 }
 
-func eventIpAddressEntitiesTable(ips []api.EventIpAddressEntity) string {
+// eventIpAddressEntitiesTable displays the wide or the narrow IP address table
+// depending on the flags --wide and --narrow, if none of them is provided, the
+// narrow table is displayed only when the wide table does not fit the width
+func eventIpAddressEntitiesTable(ips []api.EventIpAddressEntity, width int) string {
 	if len(ips) == 0 {
 		return ""
 	}
 
-	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
-	)
+	// dropping the low-value columns is preferred over expanding the table,
+	// unless the expanded table was explicitly requested via --expand
+	narrow := eventsCmdState.Narrow
+	if !narrow && !eventsCmdState.Wide && !eventsCmdState.Expand {
+		headers, rows := eventIpAddressEntitiesRows(ips, false)
+		narrow = !fitsInWidth(eventEntitiesHorizontalTable(headers, rows), width)
+	}
+
+	headers, rows := eventIpAddressEntitiesRows(ips, narrow)
+	return eventEntitiesTable(headers, rows, width)
+}
+
+// eventIpAddressEntitiesRows returns the headers and rows of the IP address
+// table, the narrow table drops the columns with traffic details and keeps
+// the identity and threat intelligence columns
+func eventIpAddressEntitiesRows(ips []api.EventIpAddressEntity, narrow bool) ([]string, [][]string) {
+	rows := [][]string{}
+	if narrow {
+		for _, ip := range ips {
+			rows = append(rows, []string{
+				ip.IpAddress,
+				ip.ThreatTags,
+				fmt.Sprintf("%v", ip.ThreatSource),
+				ip.Country,
+			})
+		}
+		return []string{
+			"IP Address",
+			"Threat Tags",
+			"Threat Source",
+			"Country",
+		}, rows
+	}
 
-	t.SetHeader([]string{
-		"IP Address",
-		"Inbound Bytes",
-		"Outboud Bytes",
-		"List of Ports",
-		"First Time Seen",
-		"Threat Tags",
-		"Threat Source",
-		"Country",
-		"Region",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, ip := range ips {
-		t.Append([]string{
+		rows = append(rows, []string{
 			ip.IpAddress,
 			fmt.Sprintf("%.3f", ip.TotalInBytes),
 			fmt.Sprintf("%.3f", ip.TotalOutBytes),
 			array.JoinInt32(ip.PortList, ", "),
-			ip.FirstSeenTime.UTC().Format(time.RFC3339),
+			cli.FormatTime(ip.FirstSeenTime),
 			ip.ThreatTags,
 			fmt.Sprintf("%v", ip.ThreatSource),
 			ip.Country,
 			ip.Region,
 		})
 	}
-	t.Render()
-
-	return r.String()
+	return []string{
+		"IP Address",
+		"Inbound Bytes",
+		"Outboud Bytes",
+		"List of Ports",
+		"First Time Seen",
+		"Threat Tags",
+		"Threat Source",
+		"Country",
+		"Region",
+	}, rows
 }
 
-func eventFileDataHashEntitiesTable(dataHashes []api.EventFileDataHashEntity) string {
+func eventFileDataHashEntitiesRows(dataHashes []api.EventFileDataHashEntity) ([]string, [][]string) {
 	if len(dataHashes) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Executable Paths",
+			"File Hash",
+			"Number of Machines",
+			"First Time Seen",
+			"Known Bad",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Executable Paths",
-		"File Hash",
-		"Number of Machines",
-		"First Time Seen",
-		"Known Bad",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, dHash := range dataHashes {
 		knownBad := "No"
 		if dHash.IsKnownBad != 0 {
 			knownBad = "Yes"
 		}
-		t.Append([]string{
+		rows = append(rows, []string{
 			strings.Join(dHash.ExePathList, ", "),
 			dHash.FiledataHash,
 			fmt.Sprintf("%d", dHash.MachineCount),
-			dHash.FirstSeenTime.UTC().Format(time.RFC3339),
+			cli.FormatTime(dHash.FirstSeenTime),
 			knownBad,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventFileExePathEntitiesTable(exePaths []api.EventFileExePathEntity) string {
+func eventFileExePathEntitiesRows(exePaths []api.EventFileExePathEntity) ([]string, [][]string) {
 	if len(exePaths) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Executable Path",
+			"First Time Seen",
+			"Last File Hash",
+			"Last Package Name",
+			"Last Version",
+			"Last File Owner",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Executable Path",
-		"First Time Seen",
-		"Last File Hash",
-		"Last Package Name",
-		"Last Version",
-		"Last File Owner",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, exe := range exePaths {
-		t.Append([]string{
+		rows = append(rows, []string{
 			exe.ExePath,
-			exe.FirstSeenTime.UTC().Format(time.RFC3339),
+			cli.FormatTime(exe.FirstSeenTime),
 			exe.LastFiledataHash,
 			exe.LastPackageName,
 			exe.LastVersion,
 			exe.LastFileOwner,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventProcessEntitiesTable(processes []api.EventProcessEntity) string {
+func eventProcessEntitiesRows(processes []api.EventProcessEntity) ([]string, [][]string) {
 	if len(processes) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Process ID",
+			"Hostname",
+			"Start Time",
+			"CPU Percentage",
+			"Command",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Process ID",
-		"Hostname",
-		"Start Time",
-		"CPU Percentage",
-		"Command",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, proc := range processes {
-		t.Append([]string{
+		rows = append(rows, []string{
 			fmt.Sprintf("%d", proc.ProcessID),
 			proc.Hostname,
-			proc.ProcessStartTime.UTC().Format(time.RFC3339),
+			cli.FormatTime(proc.ProcessStartTime),
 			fmt.Sprintf("%.3f", proc.CpuPercentage),
 			proc.Cmdline,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventContainerEntitiesTable(containers []api.EventContainerEntity) string {
+func eventContainerEntitiesRows(containers []api.EventContainerEntity) ([]string, [][]string) {
 	if len(containers) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Image Repo",
+			"Image Tag",
+			"External Connections",
+			"Type",
+			"First Time Seen",
+			"Pod Namespace",
+			"Pod Ipaddress",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Image Repo",
-		"Image Tag",
-		"External Connections",
-		"Type",
-		"First Time Seen",
-		"Pod Namespace",
-		"Pod Ipaddress",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, container := range containers {
 		containerType := ""
 		if container.IsClient != 0 {
@@ -707,64 +1448,56 @@ func eventContainerEntitiesTable(containers []api.EventContainerEntity) string {
 				containerType = "Server"
 			}
 		}
-		t.Append([]string{
+		rows = append(rows, []string{
 			container.ImageRepo,
 			container.ImageTag,
 			fmt.Sprintf("%d", container.HasExternalConns),
 			containerType,
-			container.FirstSeenTime.UTC().Format(time.RFC3339),
+			cli.FormatTime(container.FirstSeenTime),
 			container.PodNamespace,
 			container.PodIpAddr,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventUserEntitiesTable(users []api.EventUserEntity) string {
+func eventUserEntitiesRows(users []api.EventUserEntity) ([]string, [][]string) {
 	if len(users) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Username",
+			"Hostname",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Username",
-		"Hostname",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, user := range users {
-		t.Append([]string{
+		rows = append(rows, []string{
 			user.Username,
 			user.MachineHostname,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventApplicationEntitiesTable(applications []api.EventApplicationEntity) string {
+func eventApplicationEntitiesRows(applications []api.EventApplicationEntity) ([]string, [][]string) {
 	if len(applications) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Application",
+			"External Connections",
+			"Type",
+			"Earliest Known Time",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Application",
-		"External Connections",
-		"Type",
-		"Earliest Known Time",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, app := range applications {
 		appType := ""
 		if app.IsClient != 0 {
@@ -777,16 +1510,15 @@ func eventApplicationEntitiesTable(applications []api.EventApplicationEntity) st
 				appType = "Server"
 			}
 		}
-		t.Append([]string{
+		rows = append(rows, []string{
 			app.Application,
 			fmt.Sprintf("%d", app.HasExternalConns),
 			appType,
-			app.EarliestKnownTime.UTC().Format(time.RFC3339),
+			cli.FormatTime(app.EarliestKnownTime),
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
 func eventCustomRuleEntitiesTable(rules []api.EventCustomRuleEntity) string {
@@ -794,41 +1526,50 @@ func eventCustomRuleEntitiesTable(rules []api.EventCustomRuleEntity) string {
 		return ""
 	}
 
-	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
-	)
-	t.SetBorder(false)
-	t.SetAutoWrapText(false)
-
+	rows := [][]string{}
 	for _, rule := range rules {
-		t.Append([]string{eventCustomRuleEntityTable(rule)})
-		t.Append([]string{eventCustomRuleDisplayFilerTable(rule)})
+		rows = append(rows,
+			[]string{eventCustomRuleEntityTable(rule)},
+			[]string{eventCustomRuleDisplayFilerTable(rule)},
+		)
 	}
-	t.Render()
 
-	return r.String()
+	return cli.RenderTable(nil, rows, withTableAutoWrap(false))
 }
 
-func eventCustomRuleEntityTable(rule api.EventCustomRuleEntity) string {
-	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
-	)
-	t.SetHeader([]string{
+func eventCustomRuleEntitiesRows(rules []api.EventCustomRuleEntity) ([]string, [][]string) {
+	rows := [][]string{}
+	for _, rule := range rules {
+		rows = append(rows, []string{
+			rule.RuleGuid,
+			rule.LastUpdatedUser,
+			cli.FormatTime(rule.LastUpdatedTime),
+			rule.DisplayFilter,
+		})
+	}
+	return []string{
 		"Rule GUID",
 		"Last Updated User",
 		"Last Updated Time",
-	})
-	t.SetBorder(eventDetailsBorder)
-	t.SetAutoWrapText(false)
-	t.Append([]string{
-		rule.RuleGuid,
-		rule.LastUpdatedUser,
-		rule.LastUpdatedTime.UTC().Format(time.RFC3339),
-	})
-	t.Render()
-	return r.String()
+		"Display Filter",
+	}, rows
+}
+
+func eventCustomRuleEntityTable(rule api.EventCustomRuleEntity) string {
+	return cli.RenderTable(
+		[]string{
+			"Rule GUID",
+			"Last Updated User",
+			"Last Updated Time",
+		},
+		[][]string{{
+			rule.RuleGuid,
+			rule.LastUpdatedUser,
+			cli.FormatTime(rule.LastUpdatedTime),
+		}},
+		withTableBorder(eventDetailsBorder),
+		withTableAutoWrap(false),
+	)
 }
 
 func eventCustomRuleDisplayFilerTable(rule api.EventCustomRuleEntity) string {
@@ -841,43 +1582,32 @@ func eventCustomRuleDisplayFilerTable(rule api.EventCustomRuleEntity) string {
 }
 
 func oneLineTable(title, content string) string {
-	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+	return cli.RenderTable([]string{title}, [][]string{{content}},
+		withTableBorder(eventDetailsBorder),
+		withTableAutoWrap(false),
+		withTableAlignment(tablewriter.ALIGN_LEFT),
 	)
-
-	t.SetHeader([]string{title})
-	t.SetBorder(eventDetailsBorder)
-	t.SetAutoWrapText(false)
-	t.SetAlignment(tablewriter.ALIGN_LEFT)
-	t.Append([]string{content})
-	t.Render()
-
-	return r.String()
 }
 
-func eventRecIDEntitiesTable(records []api.EventRecIDEntity) string {
+func eventRecIDEntitiesRows(records []api.EventRecIDEntity) ([]string, [][]string) {
 	if len(records) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Record ID",
+			"Account ID",
+			"Account Alias",
+			"Description",
+			"Status",
+			"Evaluation Type",
+			"Evaluation GUID",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Record ID",
-		"Account ID",
-		"Account Alias",
-		"Description",
-		"Status",
-		"Evaluation Type",
-		"Evaluation GUID",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, rec := range records {
-		t.Append([]string{
+		rows = append(rows, []string{
 			rec.RecID,
 			rec.AccountID,
 			rec.AccountAlias,
@@ -887,112 +1617,96 @@ func eventRecIDEntitiesTable(records []api.EventRecIDEntity) string {
 			rec.EvalGuid,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventViolationReasonEntitiesTable(reasons []api.EventViolationReasonEntity) string {
+func eventViolationReasonEntitiesRows(reasons []api.EventViolationReasonEntity) ([]string, [][]string) {
 	if len(reasons) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Violation ID",
+			"Reason",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Violation ID",
-		"Reason",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, reason := range reasons {
-		t.Append([]string{
+		rows = append(rows, []string{
 			reason.RecID,
 			reason.Reason,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventResourceEntitiesTable(resources []api.EventResourceEntity) string {
+func eventResourceEntitiesRows(resources []api.EventResourceEntity) ([]string, [][]string) {
 	if len(resources) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Name",
+			"Value",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Name",
-		"Value",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, res := range resources {
-		t.Append([]string{
+		rows = append(rows, []string{
 			res.Name,
 			fmt.Sprintf("%v", res.Value),
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventNewViolationEntitiesTable(violations []api.EventNewViolationEntity) string {
+func eventNewViolationEntitiesRows(violations []api.EventNewViolationEntity) ([]string, [][]string) {
 	if len(violations) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Violation ID",
+			"Reason",
+			"Resource",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Violation ID",
-		"Reason",
-		"Resource",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, v := range violations {
-		t.Append([]string{
+		rows = append(rows, []string{
 			v.RecID,
 			v.Reason,
 			v.Resource,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
-func eventMachineEntitiesTable(machines []api.EventMachineEntity) string {
+func eventMachineEntitiesRows(machines []api.EventMachineEntity) ([]string, [][]string) {
 	if len(machines) == 0 {
-		return ""
+		return nil, nil
 	}
 
 	var (
-		r = &strings.Builder{}
-		t = tablewriter.NewWriter(r)
+		headers = []string{
+			"Hostname",
+			"External IP",
+			"Instance ID",
+			"Instance Name",
+			"CPU Percentage",
+			"Internal Ipaddress",
+		}
+		rows = [][]string{}
 	)
-
-	t.SetHeader([]string{
-		"Hostname",
-		"External IP",
-		"Instance ID",
-		"Instance Name",
-		"CPU Percentage",
-		"Internal Ipaddress",
-	})
-	t.SetBorder(eventDetailsBorder)
 	for _, m := range machines {
-		t.Append([]string{
+		rows = append(rows, []string{
 			m.Hostname,
 			m.ExternalIp,
 			m.InstanceID,
@@ -1001,9 +1715,8 @@ func eventMachineEntitiesTable(machines []api.EventMachineEntity) string {
 			m.InternalIpAddress,
 		})
 	}
-	t.Render()
 
-	return r.String()
+	return headers, rows
 }
 
 func filterEventsWithSeverity(events []api.Event) []api.Event {
@@ -1011,34 +1724,39 @@ func filterEventsWithSeverity(events []api.Event) []api.Event {
 		return events
 	}
 
-	sevThreshold, sevString := eventSeverityToProperTypes(eventsCmdState.Severity)
-	cli.Log.Debugw("filtering events", "threshold", sevThreshold, "severity", sevString)
-	eFiltered := []api.Event{}
-	for _, event := range events {
-		eventSeverity, _ := eventSeverityToProperTypes(event.Severity)
-		if eventSeverity <= sevThreshold {
-			eFiltered = append(eFiltered, event)
-		}
-	}
+	threshold := api.ParseSeverity(eventsCmdState.Severity)
+	cli.Log.Debugw("filtering events", "threshold", threshold.Order(), "severity", threshold)
+	eFiltered := api.FilterEventsBySeverity(events, threshold)
 
 	cli.Log.Debugw("filtered events", "events", eFiltered)
 
 	return eFiltered
 }
 
-func eventSeverityToProperTypes(severity string) (int, string) {
-	switch strings.ToLower(severity) {
-	case "1", "critical":
-		return 1, "Critical"
-	case "2", "high":
-		return 2, "High"
-	case "3", "medium":
-		return 3, "Medium"
-	case "4", "low":
-		return 4, "Low"
-	case "5", "info":
-		return 5, "Info"
-	default:
-		return 6, "Unknown"
+// validateEventsTimeRangeFlags verifies that the flag --days is not combined
+// with the flags --start and --end, and that it has a valid number of days
+func validateEventsTimeRangeFlags(flags *flag.FlagSet) error {
+	if !flags.Changed("days") {
+		return nil
+	}
+
+	if flags.Changed("start") || flags.Changed("end") {
+		return errors.New("cannot combine --days with --start/--end")
+	}
+
+	days, err := flags.GetInt("days")
+	if err != nil {
+		return err
+	}
+	return validateEventsDays(days)
+}
+
+// validateEventsDays verifies that the number of days provided by the
+// flag --days is within the time window supported by the events API
+func validateEventsDays(days int) error {
+	if days < 1 || days > eventsMaxDays {
+		return errors.Errorf("the number of days must be between 1 and %d, provided: %d",
+			eventsMaxDays, days)
 	}
+	return nil
 }
@@ -0,0 +1,106 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+// eventsBookmarkKey returns the key of the events bookmark of the current
+// account, every account and sub-account has its own bookmark
+func eventsBookmarkKey() string {
+	if cli.Subaccount != "" {
+		return cli.Account + "/" + cli.Subaccount
+	}
+	return cli.Account
+}
+
+// loadEventsBookmark returns the events bookmark of the current account,
+// a zero time means that there is no bookmark
+func loadEventsBookmark() (time.Time, error) {
+	statePath, err := stateFilePath()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	state, err := loadStateFile(statePath)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	bookmark := state.EventsBookmarks[eventsBookmarkKey()]
+	cli.Log.Debugw("events bookmark loaded", "path", statePath,
+		"key", eventsBookmarkKey(), "bookmark", bookmark,
+	)
+	return bookmark, nil
+}
+
+// storeEventsBookmark stores the provided time as the events bookmark of
+// the current account, a zero time removes the bookmark
+func storeEventsBookmark(bookmark time.Time) error {
+	statePath, err := stateFilePath()
+	if err != nil {
+		return err
+	}
+
+	state, err := loadStateFile(statePath)
+	if err != nil {
+		return err
+	}
+
+	if bookmark.IsZero() {
+		delete(state.EventsBookmarks, eventsBookmarkKey())
+	} else {
+		state.EventsBookmarks[eventsBookmarkKey()] = bookmark.UTC()
+	}
+
+	cli.Log.Debugw("storing events bookmark", "path", statePath,
+		"key", eventsBookmarkKey(), "bookmark", bookmark,
+	)
+	return state.writeToFile(statePath)
+}
+
+// eventsSinceLastTimeRange returns the time range to list the events newer
+// than the provided bookmark, the range is limited to the maximum number of
+// days supported by the events API, without a bookmark, it is the maximum
+func eventsSinceLastTimeRange(bookmark, now time.Time) (time.Time, time.Time) {
+	start := now.Add(time.Hour * 24 * eventsMaxDays * -1)
+	if bookmark.After(start) && bookmark.Before(now) {
+		start = bookmark
+	}
+	return start, now
+}
+
+// filterEventsStartedAfter returns the events that started after the
+// provided time, that is, the events that were not listed before
+func filterEventsStartedAfter(events []api.Event, since time.Time) []api.Event {
+	if since.IsZero() {
+		return events
+	}
+
+	filtered := []api.Event{}
+	for _, event := range events {
+		if event.StartTime.After(since) {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
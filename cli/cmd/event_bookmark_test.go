@@ -0,0 +1,143 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestStateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lacework-state")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a missing state file is an empty state
+	statePath := filepath.Join(dir, "lacework", "state.json")
+	state, err := loadStateFile(statePath)
+	if assert.Nil(t, err) {
+		assert.Empty(t, state.EventsBookmarks)
+	}
+
+	bookmark := time.Date(2020, 8, 20, 8, 0, 0, 0, time.UTC)
+	state.EventsBookmarks["example"] = bookmark
+	assert.Nil(t, state.writeToFile(statePath))
+
+	if info, err := os.Stat(statePath); assert.Nil(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(),
+			"the state file should only be readable by the current user")
+	}
+
+	state, err = loadStateFile(statePath)
+	if assert.Nil(t, err) {
+		assert.Equal(t, bookmark, state.EventsBookmarks["example"])
+	}
+
+	assert.Nil(t, ioutil.WriteFile(statePath, []byte("not json"), 0600))
+	_, err = loadStateFile(statePath)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unable to decode state file")
+	}
+}
+
+func TestEventsBookmark(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lacework-state")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(state cliState) { cli = state }(cli)
+	defer os.Setenv("LW_STATE_FILE", os.Getenv("LW_STATE_FILE"))
+	os.Setenv("LW_STATE_FILE", filepath.Join(dir, "state.json"))
+	cli.Log = lwlogger.New("").Sugar()
+	cli.Account = "example"
+
+	bookmark, err := loadEventsBookmark()
+	if assert.Nil(t, err) {
+		assert.True(t, bookmark.IsZero(), "there should be no bookmark")
+	}
+
+	expected := time.Date(2020, 8, 20, 8, 0, 0, 0, time.UTC)
+	assert.Nil(t, storeEventsBookmark(expected))
+
+	// every sub-account has its own bookmark
+	cli.Subaccount = "tenant-a"
+	assert.Equal(t, "example/tenant-a", eventsBookmarkKey())
+	bookmark, err = loadEventsBookmark()
+	if assert.Nil(t, err) {
+		assert.True(t, bookmark.IsZero(), "the sub-account should have no bookmark")
+	}
+
+	cli.Subaccount = ""
+	bookmark, err = loadEventsBookmark()
+	if assert.Nil(t, err) {
+		assert.Equal(t, expected, bookmark)
+	}
+
+	// a zero time resets the bookmark
+	assert.Nil(t, storeEventsBookmark(time.Time{}))
+	bookmark, err = loadEventsBookmark()
+	if assert.Nil(t, err) {
+		assert.True(t, bookmark.IsZero(), "the bookmark should be reset")
+	}
+}
+
+func TestEventsSinceLastTimeRange(t *testing.T) {
+	now := time.Date(2020, 8, 20, 8, 0, 0, 0, time.UTC)
+	maxStart := now.AddDate(0, 0, -eventsMaxDays)
+
+	start, end := eventsSinceLastTimeRange(time.Time{}, now)
+	assert.Equal(t, maxStart, start, "without a bookmark, the range should be the maximum")
+	assert.Equal(t, now, end)
+
+	start, _ = eventsSinceLastTimeRange(now.Add(-time.Hour), now)
+	assert.Equal(t, now.Add(-time.Hour), start)
+
+	start, _ = eventsSinceLastTimeRange(now.AddDate(0, 0, -30), now)
+	assert.Equal(t, maxStart, start, "the range should be limited to the maximum")
+
+	start, _ = eventsSinceLastTimeRange(now.Add(time.Hour), now)
+	assert.Equal(t, maxStart, start, "a bookmark in the future should be ignored")
+}
+
+func TestFilterEventsStartedAfter(t *testing.T) {
+	since := time.Date(2020, 8, 20, 8, 0, 0, 0, time.UTC)
+	events := []api.Event{
+		{EventID: "1", StartTime: since.Add(-time.Hour), EndTime: since.Add(time.Hour)},
+		{EventID: "2", StartTime: since},
+		{EventID: "3", StartTime: since.Add(time.Minute)},
+	}
+
+	filtered := filterEventsStartedAfter(events, since)
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, "3", filtered[0].EventID)
+	}
+	assert.Equal(t, events, filterEventsStartedAfter(events, time.Time{}))
+}
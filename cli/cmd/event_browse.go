@@ -0,0 +1,576 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/internal/array"
+)
+
+const (
+	// the option to remove the severity threshold of the list of events
+	eventBrowseAllSeverities = "all"
+
+	// the maximum width of the list pane, the rest of the screen is the detail pane
+	eventBrowseMaxListWidth = 76
+
+	// the keys of the browser, displayed at the bottom of the screen
+	eventBrowseHelp = "Up/Down select  Enter details  PgUp/PgDn scroll  / search  s severity  o open  q quit"
+)
+
+// eventBrowseANSI matches the escape sequences of colored text, the
+// screen of the browser is drawn cell by cell, so they are removed
+var eventBrowseANSI = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+
+var (
+	// eventBrowseCmd represents the browse sub-command inside the event command
+	eventBrowseCmd = &cobra.Command{
+		Use:   "browse",
+		Short: "browse events interactively",
+		Long: `Browse the events of the last 7 days in a terminal user interface, useful for triage.
+
+The list of events is on the left, sorted by severity and from the newest to
+the oldest, and the details of the selected event are on the right:
+
+    Up/Down, j/k    select an event
+    Enter           load the details of the selected event
+    PgUp/PgDn       scroll the details of the event
+    /               search the events by id, type, severity or start time
+    s               change the severity threshold of the list
+    o               open the selected event in the Lacework UI
+    q, Esc          quit
+
+To browse only the events with a severity threshold, use the flag --severity:
+
+    $ lacework events browse --severity high --days 2
+
+This command requires an interactive terminal.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !cli.InteractiveMode() || !stdoutIsTerminal() {
+				return errors.New("the browse command requires an interactive terminal")
+			}
+
+			if cli.StructuredOutput() {
+				return errors.New("the browse command does not support structured output formats")
+			}
+
+			if eventsCmdState.Severity != "" &&
+				!array.ContainsStr(api.ValidEventSeverities, eventsCmdState.Severity) {
+				return errors.Errorf("the severity %s is not valid, use one of %s",
+					eventsCmdState.Severity, strings.Join(api.ValidEventSeverities, ", "),
+				)
+			}
+
+			if cmd.Flags().Changed("days") {
+				if err := validateEventsDays(eventsCmdState.Days); err != nil {
+					return err
+				}
+			}
+
+			var (
+				response api.EventsResponse
+				err      error
+			)
+			cli.StartProgress(" Fetching events...")
+			if eventsCmdState.Days != 0 {
+				end := time.Now()
+				start := end.Add(time.Hour * 24 * time.Duration(eventsCmdState.Days) * -1)
+				response, err = listEventsDateRange(cmd.Context(), start, end)
+			} else {
+				response, err = listEvents(cmd.Context())
+			}
+			cli.StopProgress()
+			if err != nil {
+				return errors.Wrap(err, "unable to get events")
+			}
+
+			if len(response.Events) == 0 {
+				cli.OutputHuman(noEventsMessage())
+				return nil
+			}
+
+			browser := newEventsBrowser(response.Events, eventsCmdState.Severity)
+			return browser.Run(cmd.Context())
+		},
+	}
+)
+
+func init() {
+	// add the browse sub-command to the event command
+	eventCmd.AddCommand(eventBrowseCmd)
+
+	eventBrowseCmd.Flags().StringVar(&eventsCmdState.Severity,
+		"severity", "",
+		fmt.Sprintf(
+			"browse only the events with a severity threshold (%s)",
+			strings.Join(api.ValidEventSeverities, ", "),
+		),
+	)
+	errcheckWARN(eventBrowseCmd.RegisterFlagCompletionFunc("severity",
+		completeWithValues(api.ValidEventSeverities),
+	))
+	eventBrowseCmd.Flags().IntVar(&eventsCmdState.Days,
+		"days", 0,
+		fmt.Sprintf("browse the events of the last number of days (max: %d)", eventsMaxDays),
+	)
+	eventBrowseCmd.Flags().StringSliceVar(&eventsCmdState.Types,
+		"type", []string{},
+		"browse only the events of the specified event types (can be repeated)",
+	)
+}
+
+// eventsBrowser is a terminal user interface to browse a list of events, with
+// a list pane and a detail pane, the details of every event are fetched once
+type eventsBrowser struct {
+	events   []api.Event
+	severity string
+	details  map[string][]api.EventDetails
+
+	// the state of the screen
+	screen    tcell.Screen
+	cursor    int    // the selected event of the list
+	offset    int    // the first event displayed in the list pane
+	scroll    int    // the first line displayed in the detail pane
+	pageSize  int    // the number of lines of the panes
+	search    string // the text to search the events by
+	searching bool   // true while the user types the search
+	message   string // a message for the user, displayed instead of the help
+
+	// the functions to fetch the details of an event and to open a URL
+	fetchDetails func(context.Context, string) ([]api.EventDetails, error)
+	openURL      func(string) error
+}
+
+// newEventsBrowser creates a browser of the provided events sorted by severity,
+// and from the newest to the oldest, the severity is the initial threshold
+func newEventsBrowser(events []api.Event, severity string) *eventsBrowser {
+	sorted := make([]api.Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iOrder := api.ParseSeverity(sorted[i].Severity).Order()
+		jOrder := api.ParseSeverity(sorted[j].Severity).Order()
+		if iOrder != jOrder {
+			return iOrder < jOrder
+		}
+		return sorted[i].StartTime.After(sorted[j].StartTime)
+	})
+
+	return &eventsBrowser{
+		events:   sorted,
+		severity: severity,
+		details:  map[string][]api.EventDetails{},
+		fetchDetails: func(ctx context.Context, id string) ([]api.EventDetails, error) {
+			response, err := cli.LwApi.Events.DetailsWithContext(ctx, id)
+			return response.Events, err
+		},
+		openURL: openURL,
+	}
+}
+
+// Run displays the browser until the user quits
+func (b *eventsBrowser) Run(ctx context.Context) error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return errors.Wrap(err, "unable to start the terminal user interface")
+	}
+	if err := screen.Init(); err != nil {
+		return errors.Wrap(err, "unable to start the terminal user interface")
+	}
+	defer screen.Fini()
+
+	b.run(ctx, screen)
+	return nil
+}
+
+// run draws the browser and handles the keys pressed by the user until they quit
+func (b *eventsBrowser) run(ctx context.Context, screen tcell.Screen) {
+	b.screen = screen
+	for {
+		b.draw()
+		switch event := screen.PollEvent().(type) {
+		case nil:
+			// the screen was finalized
+			return
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventKey:
+			if quit := b.handleKey(ctx, event); quit {
+				return
+			}
+		}
+	}
+}
+
+// handleKey handles a key pressed by the user, it returns true to quit
+func (b *eventsBrowser) handleKey(ctx context.Context, key *tcell.EventKey) bool {
+	if key.Key() == tcell.KeyCtrlC {
+		return true
+	}
+	if b.searching {
+		b.handleSearchKey(key)
+		return false
+	}
+
+	b.message = ""
+	switch key.Key() {
+	case tcell.KeyUp:
+		b.moveCursor(-1)
+	case tcell.KeyDown:
+		b.moveCursor(1)
+	case tcell.KeyHome:
+		b.moveCursor(-len(b.events))
+	case tcell.KeyEnd:
+		b.moveCursor(len(b.events))
+	case tcell.KeyPgUp:
+		b.scroll -= b.pageSize
+	case tcell.KeyPgDn:
+		b.scroll += b.pageSize
+	case tcell.KeyEnter:
+		b.loadDetails(ctx)
+	case tcell.KeyEsc:
+		return true
+	case tcell.KeyRune:
+		switch key.Rune() {
+		case 'q':
+			return true
+		case 'k':
+			b.moveCursor(-1)
+		case 'j':
+			b.moveCursor(1)
+		case '/':
+			b.searching = true
+		case 's':
+			b.nextSeverity()
+		case 'o':
+			b.openEvent()
+		}
+	}
+	return false
+}
+
+// handleSearchKey handles a key pressed while the user types the search, the
+// list is searched as they type, Enter keeps the search and Esc removes it
+func (b *eventsBrowser) handleSearchKey(key *tcell.EventKey) {
+	selected, _ := b.selectedEvent()
+	switch key.Key() {
+	case tcell.KeyEnter:
+		b.searching = false
+		return
+	case tcell.KeyEsc:
+		b.searching = false
+		b.search = ""
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(b.search) != 0 {
+			runes := []rune(b.search)
+			b.search = string(runes[:len(runes)-1])
+		}
+	case tcell.KeyRune:
+		b.search += string(key.Rune())
+	}
+	b.selectEvent(selected.EventID)
+}
+
+// filteredEvents returns the events with a severity equal or higher than the
+// severity threshold of the browser, that match the search of the user
+func (b *eventsBrowser) filteredEvents() []api.Event {
+	events := b.events
+	if b.severity != "" && b.severity != eventBrowseAllSeverities {
+		events = api.FilterEventsBySeverity(events, api.ParseSeverity(b.severity))
+	}
+	if b.search == "" {
+		return events
+	}
+
+	var (
+		search  = strings.ToLower(b.search)
+		matches = []api.Event{}
+	)
+	for _, event := range events {
+		if strings.Contains(strings.ToLower(eventBrowseLine(event)), search) {
+			matches = append(matches, event)
+		}
+	}
+	return matches
+}
+
+func (b *eventsBrowser) listMessage(count int) string {
+	message := fmt.Sprintf("Events (%d)", count)
+	if b.severity != "" && b.severity != eventBrowseAllSeverities {
+		message = fmt.Sprintf("Events with severity %s and above (%d)", b.severity, count)
+	}
+	if b.search != "" {
+		message += fmt.Sprintf(" matching '%s'", b.search)
+	}
+	return message
+}
+
+// selectedEvent returns the selected event of the list, if any
+func (b *eventsBrowser) selectedEvent() (api.Event, bool) {
+	events := b.filteredEvents()
+	if b.cursor < 0 || b.cursor >= len(events) {
+		return api.Event{}, false
+	}
+	return events[b.cursor], true
+}
+
+// selectEvent selects the provided event, after the list changed, so that the
+// user does not lose their place, if the event is no longer in the list, the
+// first event is selected
+func (b *eventsBrowser) selectEvent(id string) {
+	b.cursor = 0
+	for i, event := range b.filteredEvents() {
+		if event.EventID == id {
+			b.cursor = i
+			return
+		}
+	}
+	b.scroll = 0
+}
+
+func (b *eventsBrowser) moveCursor(delta int) {
+	cursor := b.cursor + delta
+	if max := len(b.filteredEvents()) - 1; cursor > max {
+		cursor = max
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor != b.cursor {
+		b.cursor = cursor
+		b.scroll = 0
+	}
+}
+
+// nextSeverity changes the severity threshold of the list to the next one,
+// from all the severities to only the critical ones, and then back to all
+func (b *eventsBrowser) nextSeverity() {
+	var (
+		selected, _ = b.selectedEvent()
+		thresholds  = append([]string{eventBrowseAllSeverities}, api.ValidEventSeverities...)
+		current     = 0
+	)
+	for i, threshold := range thresholds {
+		if threshold == b.severity {
+			current = i
+		}
+	}
+
+	// the thresholds go from the lowest to the highest severity
+	next := current - 1
+	if current == 0 {
+		next = len(thresholds) - 1
+	}
+	b.severity = thresholds[next]
+	b.selectEvent(selected.EventID)
+}
+
+// loadDetails fetches the details of the selected event, unless they were
+// already fetched, they are displayed in the detail pane
+func (b *eventsBrowser) loadDetails(ctx context.Context) {
+	event, ok := b.selectedEvent()
+	if !ok {
+		return
+	}
+	if _, ok := b.details[event.EventID]; ok {
+		return
+	}
+
+	b.message = "Fetching event details..."
+	b.draw()
+	details, err := b.fetchDetails(ctx, event.EventID)
+	if err != nil {
+		cli.Log.Debugw("unable to get event details", "id", event.EventID, "error", err)
+		b.message = fmt.Sprintf("Unable to get event details: %s", err)
+		return
+	}
+	b.message = ""
+	b.scroll = 0
+	b.details[event.EventID] = details
+}
+
+// openEvent opens the selected event in the Lacework UI
+func (b *eventsBrowser) openEvent() {
+	event, ok := b.selectedEvent()
+	if !ok {
+		return
+	}
+
+	url := eventLinkBuilder(event.EventID)
+	if err := b.openURL(url); err != nil {
+		cli.Log.Debugw("unable to open web browser", "error", err)
+		b.message = fmt.Sprintf("Unable to open a web browser, navigate to: %s", url)
+		return
+	}
+	b.message = fmt.Sprintf("Event %s opened in the web browser.", event.EventID)
+}
+
+// detailLines returns the lines of the detail pane, the details of the selected
+// event, or a summary of it if its details were not fetched yet, the tables of
+// the details are sized to the provided width of the pane
+func (b *eventsBrowser) detailLines(width int) []string {
+	event, ok := b.selectedEvent()
+	if !ok {
+		return []string{}
+	}
+
+	details, ok := b.details[event.EventID]
+	if !ok {
+		return []string{
+			fmt.Sprintf("Event ID    %s", event.EventID),
+			fmt.Sprintf("Type        %s", event.EventType),
+			fmt.Sprintf("Severity    %s", event.SeverityString()),
+			fmt.Sprintf("Start Time  %s", cli.FormatTime(event.StartTime)),
+			fmt.Sprintf("End Time    %s", cli.FormatTime(event.EndTime)),
+			"",
+			"Press Enter to load the details of the event.",
+		}
+	}
+
+	if len(details) == 0 {
+		return []string{fmt.Sprintf("There are no details about the event '%s'.", event.EventID)}
+	}
+
+	var (
+		report    = &strings.Builder{}
+		separator = eventDetailsSeparator
+	)
+	if width < len(separator) {
+		separator = separator[:width]
+	}
+	for i, detail := range details {
+		if i != 0 {
+			report.WriteString(fmt.Sprintf("\n%s\n\n", separator))
+		}
+		report.WriteString(eventDetailsReportWithWidth(detail, width))
+	}
+	text := eventBrowseANSI.ReplaceAllString(report.String(), "")
+	text = strings.Replace(text, "\t", "    ", -1)
+	return strings.Split(strings.TrimRight(text, "\n"), "\n")
+}
+
+// draw draws the header, the list pane, the detail pane and the help of the
+// browser, the list pane keeps the selected event visible
+func (b *eventsBrowser) draw() {
+	var (
+		width, height = b.screen.Size()
+		events        = b.filteredEvents()
+		listWidth     = eventBrowseListWidth(width)
+		paneHeight    = height - 2
+	)
+	b.screen.Clear()
+	if paneHeight < 1 {
+		b.screen.Show()
+		return
+	}
+	b.pageSize = paneHeight
+
+	drawBrowserText(b.screen, 0, 0, width, tcell.StyleDefault.Bold(true), b.listMessage(len(events)))
+
+	// the list pane
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+paneHeight {
+		b.offset = b.cursor - paneHeight + 1
+	}
+	if len(events) == 0 {
+		drawBrowserText(b.screen, 0, 1, listWidth, tcell.StyleDefault, "There are no events matching the filters.")
+	}
+	for row := 0; row < paneHeight && b.offset+row < len(events); row++ {
+		style := tcell.StyleDefault
+		if b.offset+row == b.cursor {
+			style = style.Reverse(true)
+		}
+		drawBrowserText(b.screen, 0, row+1, listWidth, style, eventBrowseLine(events[b.offset+row]))
+	}
+
+	for y := 1; y <= paneHeight; y++ {
+		b.screen.SetContent(listWidth, y, tcell.RuneVLine, nil, tcell.StyleDefault)
+	}
+
+	// the detail pane
+	detailWidth := width - listWidth - 2
+	lines := b.detailLines(detailWidth)
+	if max := len(lines) - paneHeight; b.scroll > max {
+		b.scroll = max
+	}
+	if b.scroll < 0 {
+		b.scroll = 0
+	}
+	for row := 0; row < paneHeight && b.scroll+row < len(lines); row++ {
+		drawBrowserText(b.screen, listWidth+2, row+1, detailWidth, tcell.StyleDefault, lines[b.scroll+row])
+	}
+
+	footer := eventBrowseHelp
+	if b.searching {
+		footer = fmt.Sprintf("Search: %s_", b.search)
+	} else if b.message != "" {
+		footer = b.message
+	}
+	drawBrowserText(b.screen, 0, height-1, width, tcell.StyleDefault.Reverse(true), footer)
+
+	b.screen.Show()
+}
+
+// eventBrowseListWidth returns the width of the list pane, half of the screen
+func eventBrowseListWidth(width int) int {
+	listWidth := width / 2
+	if listWidth > eventBrowseMaxListWidth {
+		listWidth = eventBrowseMaxListWidth
+	}
+	return listWidth
+}
+
+// drawBrowserText draws the provided text at the provided position of the
+// screen, the text is truncated to the provided width
+func drawBrowserText(screen tcell.Screen, x, y, width int, style tcell.Style, text string) {
+	end := x + width
+	for _, r := range text {
+		w := runewidth.RuneWidth(r)
+		if w == 0 {
+			continue
+		}
+		if x+w > end {
+			return
+		}
+		screen.SetContent(x, y, r, nil, style)
+		x += w
+	}
+}
+
+// eventBrowseLine returns the line of an event in the list pane with its id,
+// severity, type and start time
+func eventBrowseLine(event api.Event) string {
+	return fmt.Sprintf("%-10s %-8s %-32s %s",
+		event.EventID, event.SeverityString(), event.EventType, cli.FormatTime(event.StartTime),
+	)
+}
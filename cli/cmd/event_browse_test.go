@@ -0,0 +1,267 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestEventsBrowserSortAndFilter(t *testing.T) {
+	now := time.Now()
+	events := []api.Event{
+		{EventID: "1", Severity: "3", StartTime: now.Add(-2 * time.Hour)},
+		{EventID: "2", Severity: "1", StartTime: now.Add(-3 * time.Hour)},
+		{EventID: "3", Severity: "3", StartTime: now.Add(-1 * time.Hour)},
+		{EventID: "4", Severity: "4", StartTime: now},
+	}
+
+	browser := newEventsBrowser(events, "")
+	assert.Equal(t, []string{"2", "3", "1", "4"}, eventBrowseIDs(browser.filteredEvents()),
+		"events should be sorted by severity, then from the newest to the oldest")
+	assert.Equal(t, "1", events[0].EventID, "the provided events should not be modified")
+
+	browser.severity = "medium"
+	assert.Len(t, browser.filteredEvents(), 3)
+	assert.Equal(t, "Events with severity medium and above (3)", browser.listMessage(3))
+
+	browser.severity = eventBrowseAllSeverities
+	assert.Len(t, browser.filteredEvents(), 4)
+	assert.Equal(t, "Events (4)", browser.listMessage(4))
+
+	browser.search = "Critical"
+	assert.Equal(t, []string{"2"}, eventBrowseIDs(browser.filteredEvents()),
+		"the search should be case insensitive")
+	assert.Equal(t, "Events (1) matching 'Critical'", browser.listMessage(1))
+}
+
+func TestEventsBrowserNextSeverity(t *testing.T) {
+	browser := newEventsBrowser([]api.Event{}, "")
+	thresholds := []string{}
+	for i := 0; i < 6; i++ {
+		browser.nextSeverity()
+		thresholds = append(thresholds, browser.severity)
+	}
+	assert.Equal(t,
+		[]string{"info", "low", "medium", "high", "critical", "all"}, thresholds,
+		"the severity threshold should go from the lowest to the highest severity",
+	)
+}
+
+func TestEventsBrowserPanes(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+
+	browser := newEventsBrowser(eventBrowseTestEvents(), "")
+	fetched := []string{}
+	browser.fetchDetails = func(_ context.Context, id string) ([]api.EventDetails, error) {
+		fetched = append(fetched, id)
+		return []api.EventDetails{{EventID: id, EventType: "NewExternalServerIp"}}, nil
+	}
+
+	screen := runEventsBrowser(t, browser, eventBrowseKey(tcell.KeyDown))
+	lines := strings.Split(screen, "\n")
+	require.True(t, len(lines) > 2)
+	assert.Contains(t, lines[1], "1          Critical",
+		"the list pane should display the events on the left")
+	assert.Contains(t, lines[2], "3          High")
+	require.Contains(t, lines[1], string(tcell.RuneVLine),
+		"the panes should be separated")
+	assert.Contains(t, lines[1][strings.Index(lines[1], string(tcell.RuneVLine)):], "Event ID    3",
+		"the detail pane should display a summary of the selected event on the right")
+	assert.Contains(t, screen, "Press Enter to load the details of the event.")
+	assert.Empty(t, fetched, "the details should be fetched only when requested")
+
+	screen = runEventsBrowser(t, browser,
+		eventBrowseKey(tcell.KeyEnter),
+		eventBrowseKey(tcell.KeyEnter),
+	)
+
+	assert.Equal(t, []string{"3"}, fetched, "the details should be fetched once per event")
+	assert.Contains(t, screen, "Events (3)")
+	assert.Contains(t, screen, "NewExternalServerIp")
+	assert.Contains(t, screen, eventBrowseHelp)
+	assert.NotContains(t, screen, "Press Enter to load the details of the event.")
+}
+
+func TestEventsBrowserDetailsFitInPane(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+
+	browser := newEventsBrowser(eventBrowseTestEvents(), "")
+	browser.details["1"] = []api.EventDetails{{
+		EventID:    "1",
+		EventType:  "NewExternalServerIp",
+		EventActor: "Application",
+		EventModel: "AppConn",
+		EntityMap: api.EventEntityMap{
+			IpAddress: []api.EventIpAddressEntity{{
+				IpAddress:    "10.0.0.1",
+				TotalInBytes: 1024,
+				ThreatTags:   "botnet",
+				Country:      "Mexico",
+				Region:       "Jalisco",
+			}},
+			User: []api.EventUserEntity{{Username: "alice", MachineHostname: "host-1"}},
+		},
+	}}
+
+	lines := browser.detailLines(70)
+	assert.Contains(t, strings.Join(lines, "\n"), "10.0.0.1")
+	for _, line := range lines {
+		assert.True(t, utf8.RuneCountInString(line) <= 70,
+			"the details should be sized to the width of the pane: %s", line)
+	}
+}
+
+func TestEventsBrowserSearch(t *testing.T) {
+	browser := newEventsBrowser(eventBrowseTestEvents(), "")
+	screen := runEventsBrowser(t, browser,
+		eventBrowseRune('/'),
+		eventBrowseRune('l'),
+		eventBrowseRune('o'),
+		eventBrowseRune('x'),
+		eventBrowseKey(tcell.KeyBackspace2),
+		eventBrowseRune('w'),
+		eventBrowseKey(tcell.KeyEnter),
+	)
+
+	assert.Equal(t, "low", browser.search)
+	assert.False(t, browser.searching)
+	assert.Equal(t, []string{"2"}, eventBrowseIDs(browser.filteredEvents()))
+	assert.Contains(t, screen, "Events (1) matching 'low'")
+
+	browser = newEventsBrowser(eventBrowseTestEvents(), "")
+	runEventsBrowser(t, browser,
+		eventBrowseRune('/'),
+		eventBrowseRune('l'),
+		eventBrowseKey(tcell.KeyEsc),
+	)
+	assert.Empty(t, browser.search, "Esc should remove the search")
+	assert.Len(t, browser.filteredEvents(), 3)
+}
+
+func TestEventsBrowserKeepsSelectedEvent(t *testing.T) {
+	browser := newEventsBrowser(eventBrowseTestEvents(), "")
+	runEventsBrowser(t, browser,
+		eventBrowseKey(tcell.KeyDown),
+		eventBrowseRune('s'), // info
+		eventBrowseRune('s'), // low
+		eventBrowseRune('s'), // medium
+	)
+	assert.Equal(t, "medium", browser.severity)
+	event, ok := browser.selectedEvent()
+	require.True(t, ok)
+	assert.Equal(t, "3", event.EventID, "the selected event should not change")
+
+	runEventsBrowser(t, browser,
+		eventBrowseRune('s'), // high
+		eventBrowseRune('s'), // critical
+	)
+	event, ok = browser.selectedEvent()
+	require.True(t, ok)
+	assert.Equal(t, "1", event.EventID,
+		"the first event should be selected when the selected event is filtered out")
+}
+
+func TestEventsBrowserErrors(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+
+	browser := newEventsBrowser(eventBrowseTestEvents(), "")
+	browser.fetchDetails = func(context.Context, string) ([]api.EventDetails, error) {
+		return nil, errors.New("boom")
+	}
+	browser.openURL = func(string) error {
+		return errors.New("no browser")
+	}
+
+	screen := runEventsBrowser(t, browser, eventBrowseKey(tcell.KeyEnter))
+	assert.Contains(t, screen, "Unable to get event details: boom")
+	assert.Empty(t, browser.details)
+
+	screen = runEventsBrowser(t, browser, eventBrowseRune('o'))
+	assert.Contains(t, screen, "Unable to open a web browser, navigate to: ")
+}
+
+func eventBrowseTestEvents() []api.Event {
+	now := time.Now()
+	return []api.Event{
+		{EventID: "1", EventType: "NewExternalServerIp", Severity: "1", StartTime: now},
+		{EventID: "2", EventType: "NewUser", Severity: "4", StartTime: now},
+		{EventID: "3", EventType: "NewVPC", Severity: "2", StartTime: now},
+	}
+}
+
+func eventBrowseIDs(events []api.Event) []string {
+	ids := []string{}
+	for _, event := range events {
+		ids = append(ids, event.EventID)
+	}
+	return ids
+}
+
+func eventBrowseKey(key tcell.Key) *tcell.EventKey {
+	return tcell.NewEventKey(key, 0, tcell.ModNone)
+}
+
+func eventBrowseRune(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+// runEventsBrowser runs the browser on a simulation screen, it handles the
+// provided keys and returns the contents of the screen before quitting
+func runEventsBrowser(t *testing.T, browser *eventsBrowser, keys ...*tcell.EventKey) string {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	require.Nil(t, screen.Init())
+	defer screen.Fini()
+	screen.SetSize(160, 20)
+
+	browser.screen = screen
+	for _, key := range keys {
+		browser.draw()
+		require.False(t, browser.handleKey(context.Background(), key))
+	}
+	browser.draw()
+
+	cells, width, height := screen.GetContents()
+	lines := make([]string, height)
+	for y := 0; y < height; y++ {
+		line := &strings.Builder{}
+		for x := 0; x < width; x++ {
+			line.WriteRune(cells[y*width+x].Runes[0])
+		}
+		lines[y] = strings.TrimRight(line.String(), " ")
+	}
+
+	assert.True(t, browser.handleKey(context.Background(), eventBrowseRune('q')),
+		"q should quit the browser")
+	return strings.Join(lines, "\n")
+}
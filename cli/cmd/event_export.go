@@ -0,0 +1,195 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+var (
+	// the directory where the event artifacts are exported
+	eventExportDir string
+
+	// eventExportCmd represents the export sub-command inside the event command
+	eventExportCmd = &cobra.Command{
+		Use:   "export <event_id>",
+		Short: "export the details of an event into a directory",
+		Long: `Export every artifact of an event into a directory, useful for incident reports.
+
+The directory contains the following files:
+
+  details.json    the raw details of the event
+  summary.txt     the details of the event as displayed by 'lacework event show'
+  <entity>.csv    one CSV file per entity table, like machines.csv or users.csv
+  event.url       the URL to further investigate the event in the Lacework UI
+
+When the flag --dir is not provided, the artifacts are exported into the
+directory 'event-<event_id>' of the current working directory:
+
+    $ lacework event export 123 --dir ./evidence/`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeEventIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateEventID(args[0]); err != nil {
+				return err
+			}
+
+			dir := eventExportDir
+			if dir == "" {
+				dir = fmt.Sprintf("event-%s", args[0])
+			}
+
+			cli.Log.Infow("requesting event details", "event_id", args[0])
+			response, err := cli.LwApi.Events.DetailsWithContext(cmd.Context(), args[0])
+			if err != nil {
+				return errors.Wrap(err, "unable to get event details")
+			}
+
+			cli.Log.Debugw("event details",
+				"event_id", args[0],
+				"raw", response,
+			)
+			if len(response.Events) == 0 {
+				return errors.Errorf("there are no details about the event '%s'", args[0])
+			}
+
+			files, err := exportEventDetails(dir, args[0], response.Events)
+			if err != nil {
+				return err
+			}
+
+			if cli.JSONOutput() {
+				return cli.OutputJSON(struct {
+					EventID string   `json:"event_id"`
+					Dir     string   `json:"dir"`
+					Files   []string `json:"files"`
+				}{args[0], dir, files})
+			}
+
+			cli.OutputHuman("The event %s has been exported to %s\n", args[0], dir)
+			if !cli.Quiet() {
+				cli.OutputHuman("\n  %s\n", strings.Join(files, "\n  "))
+			}
+			return nil
+		},
+	}
+)
+
+func init() {
+	// add the export sub-command to the event command
+	eventCmd.AddCommand(eventExportCmd)
+
+	eventExportCmd.Flags().StringVar(&eventExportDir,
+		"dir", "",
+		"directory where the event artifacts are exported (default \"event-<event_id>\")",
+	)
+}
+
+// exportEventDetails writes the artifacts of the provided event details into
+// the specified directory and returns the names of the files written, when
+// the API returns multiple details, the entity tables of every detail after
+// the first one are suffixed with their position, like users_2.csv
+func exportEventDetails(dir, id string, events []api.EventDetails) ([]string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "unable to create export directory")
+	}
+
+	files := []string{}
+	write := func(name string, data []byte) error {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return errors.Wrapf(err, "unable to write %s", name)
+		}
+		files = append(files, name)
+		return nil
+	}
+
+	details, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encode event details")
+	}
+	if err := write("details.json", append(details, '\n')); err != nil {
+		return nil, err
+	}
+
+	summary := &strings.Builder{}
+	for i, event := range events {
+		if i != 0 {
+			summary.WriteString(fmt.Sprintf("\n%s\n\n", eventDetailsSeparator))
+		}
+		summary.WriteString(eventDetailsReport(event))
+	}
+	if err := write("summary.txt", []byte(summary.String())); err != nil {
+		return nil, err
+	}
+
+	for i, event := range events {
+		for _, entity := range eventEntityMapRows(event.EntityMap) {
+			if len(entity.Rows) == 0 {
+				continue
+			}
+
+			name := entity.Name
+			if i != 0 {
+				name = fmt.Sprintf("%s_%d", name, i+1)
+			}
+
+			data, err := eventEntityRowsCSV(entity)
+			if err != nil {
+				return nil, err
+			}
+			if err := write(name+".csv", data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	url := fmt.Sprintf("[InternetShortcut]\r\nURL=%s\r\n", eventLinkBuilder(id))
+	if err := write("event.url", []byte(url)); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// eventEntityRowsCSV renders the headers and rows of an entity table as CSV
+func eventEntityRowsCSV(entity eventEntityRows) ([]byte, error) {
+	var (
+		out = &bytes.Buffer{}
+		w   = csv.NewWriter(out)
+	)
+	if err := w.Write(entity.Headers); err != nil {
+		return nil, errors.Wrapf(err, "unable to encode %s table", entity.Name)
+	}
+	if err := w.WriteAll(entity.Rows); err != nil {
+		return nil, errors.Wrapf(err, "unable to encode %s table", entity.Name)
+	}
+	return out.Bytes(), nil
+}
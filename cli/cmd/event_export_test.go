@@ -0,0 +1,89 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+func TestExportEventDetails(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Account = "account"
+
+	dir, err := ioutil.TempDir("", "lacework-event-export")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	files, err := exportEventDetails(filepath.Join(dir, "evidence"), "123", []api.EventDetails{
+		{
+			EventID:   "123",
+			EventType: "NewUser",
+			EntityMap: api.EventEntityMap{
+				User: []api.EventUserEntity{{Username: "alice", MachineHostname: "web, 01"}},
+			},
+		},
+		{
+			EventID: "123",
+			EntityMap: api.EventEntityMap{
+				User: []api.EventUserEntity{{Username: "bob"}},
+			},
+		},
+	})
+	require.Nil(t, err)
+	assert.Equal(t,
+		[]string{"details.json", "summary.txt", "users.csv", "users_2.csv", "event.url"},
+		files,
+	)
+
+	read := func(name string) string {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "evidence", name))
+		require.Nil(t, err)
+		return string(data)
+	}
+	assert.Contains(t, read("details.json"), "\"event_id\": \"123\"")
+	assert.Contains(t, read("summary.txt"), "NewUser")
+	assert.Equal(t, "Username,Hostname\nalice,\"web, 01\"\n", read("users.csv"))
+	assert.Equal(t, "Username,Hostname\nbob,\n", read("users_2.csv"))
+	assert.Equal(t,
+		"[InternetShortcut]\r\nURL=https://account.lacework.net/ui/investigation/recents/EventDossier-123\r\n",
+		read("event.url"),
+	)
+}
+
+func TestEventEntityMapRows(t *testing.T) {
+	tables := eventEntityMapRows(api.EventEntityMap{
+		IpAddress: []api.EventIpAddressEntity{{IpAddress: "10.0.0.1"}},
+	})
+	if assert.Len(t, tables, 18) {
+		assert.Equal(t, "machines", tables[0].Name)
+		assert.Empty(t, tables[0].Rows)
+		assert.Equal(t, "ip_addresses", tables[4].Name)
+		assert.Len(t, tables[4].Rows, 1)
+		assert.Len(t, tables[4].Headers, len(tables[4].Rows[0]),
+			"the exported IP address table should include every column")
+	}
+}
@@ -0,0 +1,134 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+// eventsSeverityFile is a file of events written by the flag --output-dir
+type eventsSeverityFile struct {
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Count    int    `json:"count"`
+}
+
+// outputEventsBySeverity writes the events into one file per severity and
+// displays the files that were written
+func outputEventsBySeverity(dir string, events []api.Event, columns []eventsTableColumn) error {
+	files, err := writeEventsBySeverity(dir, events, columns)
+	if err != nil {
+		return err
+	}
+
+	if cli.JSONOutput() {
+		return cli.OutputJSON(struct {
+			Dir   string               `json:"dir"`
+			Files []eventsSeverityFile `json:"files"`
+		}{dir, files})
+	}
+
+	if len(files) == 0 {
+		cli.OutputHuman(noEventsMessage())
+		return nil
+	}
+
+	cli.OutputHuman("The events have been written to %s\n", dir)
+	if !cli.Quiet() {
+		rows := make([][]string, len(files))
+		for i, file := range files {
+			rows[i] = []string{file.File, fmt.Sprintf("%d", file.Count)}
+		}
+		cli.OutputHuman("\n%s", cli.RenderTable([]string{"File", "Events"}, rows))
+	}
+	return nil
+}
+
+// writeEventsBySeverity writes the events of every severity into its own file
+// of the provided directory, like critical.json or high.json, the files are
+// written in JSON format unless the output format of the cli is CSV or YAML
+func writeEventsBySeverity(dir string, events []api.Event, columns []eventsTableColumn) ([]eventsSeverityFile, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "unable to create output directory")
+	}
+
+	extension := outputFormatJSON
+	if cli.OutputFormat() == outputFormatCSV || cli.OutputFormat() == outputFormatYAML {
+		extension = cli.OutputFormat()
+	}
+
+	files := []eventsSeverityFile{}
+	for _, group := range groupEventsBySeverity(events) {
+		var (
+			name = fmt.Sprintf("%s.%s", strings.ToLower(group.Severity.String()), extension)
+			data = &bytes.Buffer{}
+			err  error
+		)
+		switch extension {
+		case outputFormatCSV:
+			err = writeCSV(data, eventsTableHeaders(columns), eventsToTable(group.Events, columns))
+		case outputFormatYAML:
+			err = writeYAML(data, group.Events)
+		default:
+			err = writeEventsJSON(data, group.Events)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to encode %s events", group.Severity)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data.Bytes(), 0600); err != nil {
+			return nil, errors.Wrapf(err, "unable to write %s", name)
+		}
+		files = append(files, eventsSeverityFile{
+			Severity: strings.ToLower(group.Severity.String()),
+			File:     name,
+			Count:    len(group.Events),
+		})
+	}
+	return files, nil
+}
+
+// writeEventsJSON writes the events in the JSON style of the cli, the pretty
+// style is indented without colors since it is not written to a terminal
+func writeEventsJSON(w io.Writer, events []api.Event) error {
+	switch cli.JSONStyle() {
+	case jsonStyleCompact:
+		return writeJSONCompact(w, events)
+	case jsonStyleLines:
+		return writeJSONLines(w, events)
+	}
+
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to encode JSON object")
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,96 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestGroupEventsBySeverity(t *testing.T) {
+	events := []api.Event{
+		{EventID: "1", Severity: "3"},
+		{EventID: "2", Severity: "1"},
+		{EventID: "3", Severity: "3"},
+		{EventID: "4", Severity: "4"},
+	}
+
+	groups := groupEventsBySeverity(events)
+	if assert.Len(t, groups, 3, "severities without events should be skipped") {
+		assert.Equal(t, api.SeverityCritical, groups[0].Severity)
+		assert.Equal(t, []api.Event{events[1]}, groups[0].Events)
+		assert.Equal(t, api.SeverityMedium, groups[1].Severity)
+		assert.Equal(t, []api.Event{events[0], events[2]}, groups[1].Events)
+		assert.Equal(t, api.SeverityLow, groups[2].Severity)
+	}
+	assert.Empty(t, groupEventsBySeverity([]api.Event{}))
+}
+
+func TestWriteEventsBySeverity(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+
+	dir, err := ioutil.TempDir("", "lacework-events")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	events := []api.Event{
+		{EventID: "1", EventType: "NewUser", Severity: "2"},
+		{EventID: "2", EventType: "NewVPC", Severity: "1"},
+		{EventID: "3", EventType: "NewUser", Severity: "2"},
+	}
+	columns, err := selectEventsTableColumns([]string{"id", "type"})
+	require.Nil(t, err)
+
+	require.Nil(t, cli.SetOutputFormat(outputFormatJSON))
+	files, err := writeEventsBySeverity(filepath.Join(dir, "json"), events, columns)
+	require.Nil(t, err)
+	assert.Equal(t, []eventsSeverityFile{
+		{Severity: "critical", File: "critical.json", Count: 1},
+		{Severity: "high", File: "high.json", Count: 2},
+	}, files)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "json", "high.json"))
+	require.Nil(t, err)
+	var high []api.Event
+	if assert.Nil(t, json.Unmarshal(data, &high)) {
+		assert.Equal(t, []api.Event{events[0], events[2]}, high)
+	}
+	assert.NoFileExists(t, filepath.Join(dir, "json", "medium.json"))
+
+	// the CSV files honor the selected columns
+	require.Nil(t, cli.SetOutputFormat(outputFormatCSV))
+	files, err = writeEventsBySeverity(filepath.Join(dir, "csv"), events, columns)
+	require.Nil(t, err)
+	if assert.Len(t, files, 2) {
+		assert.Equal(t, "critical.csv", files[0].File)
+	}
+	data, err = ioutil.ReadFile(filepath.Join(dir, "csv", "high.csv"))
+	require.Nil(t, err)
+	assert.Equal(t, "Event ID,Type\n1,NewUser\n3,NewUser\n", string(data))
+}
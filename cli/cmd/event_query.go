@@ -0,0 +1,130 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	flag "github.com/spf13/pflag"
+)
+
+// eventsQuery is a saved set of filters of the command 'events list' that is
+// loaded with the flag --query, the file can be in JSON or TOML format (.toml)
+//
+// Example of a query file in JSON format:
+//
+//   {
+//     "severity": "high",
+//     "days": 1,
+//     "columns": ["id", "type", "severity", "start"]
+//   }
+type eventsQuery struct {
+	Start     string   `json:"start" toml:"start"`
+	End       string   `json:"end" toml:"end"`
+	Days      int      `json:"days" toml:"days"`
+	Severity  string   `json:"severity" toml:"severity"`
+	Columns   []string `json:"columns" toml:"columns"`
+	TypesOnly bool     `json:"types_only" toml:"types_only"`
+	SinceLast bool     `json:"since_last" toml:"since_last"`
+}
+
+// eventsQueryTimeRangeFlags are the flags that select the time range of the
+// events, they are applied as a group, if any of them is provided on the
+// command line, the time range of the query file is ignored
+var eventsQueryTimeRangeFlags = []string{"start", "end", "days", "since-last"}
+
+func loadEventsQuery(queryPath string) (eventsQuery, error) {
+	var query eventsQuery
+
+	data, err := ioutil.ReadFile(queryPath)
+	if err != nil {
+		return query, errors.Wrap(err, "unable to read query file")
+	}
+
+	if strings.EqualFold(filepath.Ext(queryPath), ".toml") {
+		md, err := toml.Decode(string(data), &query)
+		if err != nil {
+			return query, errors.Wrapf(err, "unable to decode query file %s", queryPath)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) != 0 {
+			return query, errors.Errorf("unknown key '%s' in query file %s", undecoded[0], queryPath)
+		}
+		return query, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&query); err != nil {
+		return query, errors.Wrapf(err, "unable to decode query file %s", queryPath)
+	}
+	return query, nil
+}
+
+// applyEventsQuery sets the flags of the command 'events list' from the values
+// of the query, the flags provided explicitly on the command line win
+func applyEventsQuery(flags *flag.FlagSet, query eventsQuery) error {
+	timeRangeFromFlags := false
+	for _, name := range eventsQueryTimeRangeFlags {
+		if flags.Changed(name) {
+			timeRangeFromFlags = true
+		}
+	}
+
+	values := map[string]string{}
+	if !timeRangeFromFlags {
+		if query.Start != "" {
+			values["start"] = query.Start
+		}
+		if query.End != "" {
+			values["end"] = query.End
+		}
+		if query.Days != 0 {
+			values["days"] = fmt.Sprint(query.Days)
+		}
+		if query.SinceLast {
+			values["since-last"] = "true"
+		}
+	}
+	if query.Severity != "" {
+		values["severity"] = query.Severity
+	}
+	if len(query.Columns) != 0 {
+		values["columns"] = strings.Join(query.Columns, ",")
+	}
+	if query.TypesOnly {
+		values["types-only"] = "true"
+	}
+
+	for name, value := range values {
+		if flags.Changed(name) {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return errors.Wrapf(err, "invalid value '%s' of the query key '%s'", value, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	flag "github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEventsQueryTestFlags(args ...string) *flag.FlagSet {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	flags.String("start", "", "")
+	flags.String("end", "", "")
+	flags.Int("days", 0, "")
+	flags.String("severity", "", "")
+	flags.StringSlice("columns", []string{}, "")
+	flags.Bool("types-only", false, "")
+	flags.Bool("since-last", false, "")
+	if err := flags.Parse(args); err != nil {
+		panic(err)
+	}
+	return flags
+}
+
+func TestLoadEventsQuery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lacework-events-query")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	jsonQuery := filepath.Join(dir, "query.json")
+	require.Nil(t, ioutil.WriteFile(jsonQuery,
+		[]byte(`{"severity": "high", "days": 1, "columns": ["id", "type"]}`), 0600))
+	query, err := loadEventsQuery(jsonQuery)
+	if assert.Nil(t, err) {
+		assert.Equal(t, eventsQuery{Severity: "high", Days: 1, Columns: []string{"id", "type"}}, query)
+	}
+
+	tomlQuery := filepath.Join(dir, "query.toml")
+	require.Nil(t, ioutil.WriteFile(tomlQuery,
+		[]byte("severity = \"low\"\ntypes_only = true\n"), 0600))
+	query, err = loadEventsQuery(tomlQuery)
+	if assert.Nil(t, err) {
+		assert.Equal(t, eventsQuery{Severity: "low", TypesOnly: true}, query)
+	}
+
+	require.Nil(t, ioutil.WriteFile(jsonQuery, []byte(`{"sevrity": "high"}`), 0600))
+	_, err = loadEventsQuery(jsonQuery)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unknown field \"sevrity\"")
+	}
+
+	require.Nil(t, ioutil.WriteFile(tomlQuery, []byte("dayz = 1\n"), 0600))
+	_, err = loadEventsQuery(tomlQuery)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unknown key 'dayz'")
+	}
+}
+
+func TestApplyEventsQuery(t *testing.T) {
+	query := eventsQuery{Severity: "high", Days: 1, Columns: []string{"id", "type"}}
+
+	flags := newEventsQueryTestFlags()
+	assert.Nil(t, applyEventsQuery(flags, query))
+	severity, _ := flags.GetString("severity")
+	days, _ := flags.GetInt("days")
+	columns, _ := flags.GetStringSlice("columns")
+	assert.Equal(t, "high", severity)
+	assert.Equal(t, 1, days)
+	assert.Equal(t, []string{"id", "type"}, columns)
+
+	// explicit flags override the values of the query file, and any
+	// time range flag replaces the whole time range of the file
+	flags = newEventsQueryTestFlags("--severity", "low", "--start", "2020-08-20T08:00:00Z")
+	assert.Nil(t, applyEventsQuery(flags, query))
+	severity, _ = flags.GetString("severity")
+	assert.Equal(t, "low", severity)
+	assert.False(t, flags.Changed("days"))
+	assert.True(t, flags.Changed("columns"))
+
+	flags = newEventsQueryTestFlags("--days", "3")
+	assert.Nil(t, applyEventsQuery(flags, eventsQuery{SinceLast: true}))
+	assert.False(t, flags.Changed("since-last"))
+}
@@ -0,0 +1,210 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+var (
+	// the number of days of the window of the stats of events
+	eventStatsDays int
+
+	// eventStatsCmd represents the stats sub-command inside the event command
+	eventStatsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "show statistics of the events of a time window",
+		Long: `Show an aggregate report of the events of the last number of days, useful as
+a quick weekly posture summary.
+
+The report contains the total number of events, the number of events per
+severity and per event type, and the day with the most events:
+
+    $ lacework events stats --days 7
+
+The days are computed in the time zone set with the flag --timezone.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if eventStatsDays < 1 || eventStatsDays > eventsMaxDays {
+				return errors.Errorf("the number of days must be between 1 and %d", eventsMaxDays)
+			}
+
+			end := time.Now()
+			start := end.Add(time.Hour * 24 * time.Duration(eventStatsDays) * -1)
+
+			cli.Log.Infow("requesting list of events for stats",
+				"days", eventStatsDays, "start_time", start, "end_time", end,
+			)
+			cli.StartProgress(" Fetching events...")
+			response, err := cli.LwApi.Events.ListDateRangeWithContext(cmd.Context(), start, end)
+			cli.StopProgress()
+			if err != nil {
+				return errors.Wrap(err, "unable to get events")
+			}
+
+			stats := buildEventsStats(response.Events, start, end)
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(stats)
+			}
+
+			cli.OutputHuman(eventsStatsReport(stats))
+			return nil
+		},
+	}
+)
+
+func init() {
+	// add the stats sub-command to the event command
+	eventCmd.AddCommand(eventStatsCmd)
+
+	eventStatsCmd.Flags().IntVar(&eventStatsDays,
+		"days", eventsMaxDays,
+		fmt.Sprintf("number of days of the window of events (max: %d)", eventsMaxDays),
+	)
+}
+
+// eventsStats is an aggregate report of the events of a time window
+type eventsStats struct {
+	Start      time.Time            `json:"start_time"`
+	End        time.Time            `json:"end_time"`
+	Total      int                  `json:"total"`
+	Severities eventsSeverityCounts `json:"severities"`
+	Types      []eventTypeCount     `json:"types"`
+	BusiestDay *eventsDayCount      `json:"busiest_day"`
+}
+
+// eventsSeverityCounts is the number of events per severity
+type eventsSeverityCounts struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Info     int `json:"info"`
+}
+
+// eventTypeCount is the number of events of a single event type
+type eventTypeCount struct {
+	EventType string `json:"event_type"`
+	Count     int    `json:"count"`
+}
+
+// eventsDayCount is the number of events that started on a single day
+type eventsDayCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// buildEventsStats aggregates the provided events of the time window, the
+// event types are sorted by the number of events in descending order, and
+// the busiest day is the earliest day with the most events, or nil if there
+// are no events
+func buildEventsStats(events []api.Event, start, end time.Time) eventsStats {
+	stats := eventsStats{
+		Start: start,
+		End:   end,
+		Total: len(events),
+		Types: []eventTypeCount{},
+	}
+
+	days := map[string]int{}
+	for _, event := range events {
+		switch api.ParseSeverity(event.Severity) {
+		case api.SeverityCritical:
+			stats.Severities.Critical++
+		case api.SeverityHigh:
+			stats.Severities.High++
+		case api.SeverityMedium:
+			stats.Severities.Medium++
+		case api.SeverityLow:
+			stats.Severities.Low++
+		case api.SeverityInfo:
+			stats.Severities.Info++
+		}
+		days[event.StartTime.In(cli.Location()).Format("2006-01-02")]++
+	}
+
+	for _, group := range groupEventsByType(events) {
+		stats.Types = append(stats.Types, eventTypeCount{group.EventType, group.Count})
+	}
+
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, date := range dates {
+		if stats.BusiestDay == nil || days[date] > stats.BusiestDay.Count {
+			stats.BusiestDay = &eventsDayCount{date, days[date]}
+		}
+	}
+
+	return stats
+}
+
+// eventsStatsReport renders the stats of events as a report with one section
+// for the summary, the severities and the event types
+func eventsStatsReport(stats eventsStats) string {
+	busiestDay := "-"
+	if stats.BusiestDay != nil {
+		busiestDay = fmt.Sprintf("%s (%d events)", stats.BusiestDay.Date, stats.BusiestDay.Count)
+	}
+
+	report := &strings.Builder{}
+	report.WriteString(cli.RenderTable(nil,
+		[][]string{
+			{"From", cli.FormatTime(stats.Start)},
+			{"To", cli.FormatTime(stats.End)},
+			{"Total Events", fmt.Sprintf("%d", stats.Total)},
+			{"Busiest Day", busiestDay},
+		},
+		withTableColumnSeparator(""),
+		withTableAlignment(tablewriter.ALIGN_LEFT),
+	))
+
+	report.WriteString("\n")
+	report.WriteString(cli.RenderTable([]string{"Severity", "Count"},
+		[][]string{
+			{cli.colorizeSeverity("Critical"), fmt.Sprintf("%d", stats.Severities.Critical)},
+			{cli.colorizeSeverity("High"), fmt.Sprintf("%d", stats.Severities.High)},
+			{cli.colorizeSeverity("Medium"), fmt.Sprintf("%d", stats.Severities.Medium)},
+			{cli.colorizeSeverity("Low"), fmt.Sprintf("%d", stats.Severities.Low)},
+			{cli.colorizeSeverity("Info"), fmt.Sprintf("%d", stats.Severities.Info)},
+		},
+	))
+
+	if len(stats.Types) != 0 {
+		rows := make([][]string, len(stats.Types))
+		for i, eventType := range stats.Types {
+			rows[i] = []string{eventType.EventType, fmt.Sprintf("%d", eventType.Count)}
+		}
+		report.WriteString("\n")
+		report.WriteString(cli.RenderTable([]string{"Event Type", "Count"}, rows))
+	}
+
+	return report.String()
+}
@@ -0,0 +1,82 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestBuildEventsStats(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+	cli.timezone = nil
+
+	var (
+		end    = time.Date(2020, 6, 8, 12, 0, 0, 0, time.UTC)
+		start  = end.Add(-7 * 24 * time.Hour)
+		events = []api.Event{
+			{EventID: "1", EventType: "NewUser", Severity: "3", StartTime: time.Date(2020, 6, 2, 10, 0, 0, 0, time.UTC)},
+			{EventID: "2", EventType: "NewPort", Severity: "1", StartTime: time.Date(2020, 6, 3, 23, 0, 0, 0, time.UTC)},
+			{EventID: "3", EventType: "NewUser", Severity: "3", StartTime: time.Date(2020, 6, 3, 20, 0, 0, 0, time.UTC)},
+			{EventID: "4", EventType: "NewUser", Severity: "5", StartTime: time.Date(2020, 6, 2, 16, 0, 0, 0, time.UTC)},
+			{EventID: "5", EventType: "NewPort", Severity: "2", StartTime: time.Date(2020, 6, 5, 8, 0, 0, 0, time.UTC)},
+		}
+	)
+
+	stats := buildEventsStats(events, start, end)
+	assert.Equal(t, 5, stats.Total)
+	assert.Equal(t, eventsSeverityCounts{Critical: 1, High: 1, Medium: 2, Info: 1}, stats.Severities)
+	assert.Equal(t, []eventTypeCount{{"NewUser", 3}, {"NewPort", 2}}, stats.Types)
+	assert.Equal(t, &eventsDayCount{"2020-06-02", 2}, stats.BusiestDay,
+		"the earliest day with the most events should be the busiest day")
+
+	// the days are computed in the time zone of the cli
+	assert.Nil(t, cli.SetTimezone("Asia/Tokyo"))
+	stats = buildEventsStats(events, start, end)
+	assert.Equal(t, &eventsDayCount{"2020-06-04", 2}, stats.BusiestDay)
+
+	stats = buildEventsStats([]api.Event{}, start, end)
+	assert.Equal(t, 0, stats.Total)
+	assert.Empty(t, stats.Types)
+	assert.Nil(t, stats.BusiestDay)
+}
+
+func TestEventsStatsReport(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+	cli.timezone = nil
+	cli.DisableColors()
+
+	end := time.Date(2020, 6, 8, 12, 0, 0, 0, time.UTC)
+	report := eventsStatsReport(buildEventsStats([]api.Event{
+		{EventID: "1", EventType: "NewUser", Severity: "3", StartTime: end.Add(-time.Hour)},
+	}, end.Add(-24*time.Hour), end))
+
+	assert.Contains(t, report, "2020-06-07T12:00:00Z")
+	assert.Contains(t, report, "Total Events  1")
+	assert.Contains(t, report, "Busiest Day   2020-06-08 (1 events)")
+	assert.Contains(t, report, "NewUser")
+	assert.Contains(t, report, "Medium   |     1")
+}
@@ -0,0 +1,341 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+func TestEventLinkBuilder(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+
+	cli.Account = "account"
+	assert.Equal(t,
+		"https://account.lacework.net/ui/investigation/recents/EventDossier-123",
+		eventLinkBuilder("123"),
+	)
+
+	cli.Domain = "lacework-gov.net"
+	assert.Equal(t,
+		"https://account.lacework-gov.net/ui/investigation/recents/EventDossier-123",
+		eventLinkBuilder("123"),
+	)
+
+	cli.Domain = ""
+	cli.ApiURL = "https://staging.example.com/"
+	assert.Equal(t,
+		"https://staging.example.com/ui/investigation/recents/EventDossier-123",
+		eventLinkBuilder("123"),
+	)
+}
+
+func TestValidateEventsDays(t *testing.T) {
+	for _, days := range []int{1, 3, 7} {
+		assert.Nil(t, validateEventsDays(days))
+	}
+
+	assert.EqualError(t, validateEventsDays(0),
+		"the number of days must be between 1 and 7, provided: 0")
+	assert.EqualError(t, validateEventsDays(-1),
+		"the number of days must be between 1 and 7, provided: -1")
+	assert.EqualError(t, validateEventsDays(30),
+		"the number of days must be between 1 and 7, provided: 30")
+}
+
+func TestValidateEventsTimeRangeFlags(t *testing.T) {
+	cases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{[]string{}, ""},
+		{[]string{"--days", "3"}, ""},
+		{[]string{"--start", "2020-06-01T00:00:00Z"}, ""},
+		{[]string{"--end", "2020-06-07T00:00:00Z"}, ""},
+		{[]string{"--start", "2020-06-01T00:00:00Z", "--end", "2020-06-07T00:00:00Z"}, ""},
+		{[]string{"--days", "3", "--start", "2020-06-01T00:00:00Z"},
+			"cannot combine --days with --start/--end"},
+		{[]string{"--days", "3", "--end", "2020-06-07T00:00:00Z"},
+			"cannot combine --days with --start/--end"},
+		{[]string{"--days", "3", "--start", "2020-06-01T00:00:00Z", "--end", "2020-06-07T00:00:00Z"},
+			"cannot combine --days with --start/--end"},
+		{[]string{"--days", "0"}, "the number of days must be between 1 and 7, provided: 0"},
+		{[]string{"--days", "30"}, "the number of days must be between 1 and 7, provided: 30"},
+	}
+
+	for _, kase := range cases {
+		flags := flag.NewFlagSet("list", flag.ContinueOnError)
+		flags.String("start", "", "")
+		flags.String("end", "", "")
+		flags.Int("days", 0, "")
+		assert.Nil(t, flags.Parse(kase.args))
+
+		err := validateEventsTimeRangeFlags(flags)
+		if kase.expectedErr == "" {
+			assert.Nil(t, err, kase.args)
+		} else {
+			assert.EqualError(t, err, kase.expectedErr, kase.args)
+		}
+	}
+}
+
+func TestEventDetailsReport(t *testing.T) {
+	report := eventDetailsReport(api.EventDetails{
+		EventID:   "123",
+		EventType: "NewUser",
+		EntityMap: api.EventEntityMap{
+			User: []api.EventUserEntity{{Username: "alice"}},
+		},
+	})
+	assert.Contains(t, report, "123")
+	assert.Contains(t, report, "NewUser")
+	assert.Contains(t, report, "alice", "the report should include the entity tables")
+}
+
+func TestEventIpAddressEntitiesTable(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	defer func(wide, narrow bool) {
+		eventsCmdState.Wide, eventsCmdState.Narrow = wide, narrow
+	}(eventsCmdState.Wide, eventsCmdState.Narrow)
+	defer os.Setenv("COLUMNS", os.Getenv("COLUMNS"))
+
+	ips := []api.EventIpAddressEntity{{
+		IpAddress:    "10.0.0.1",
+		TotalInBytes: 1024,
+		ThreatTags:   "botnet",
+		Country:      "Mexico",
+		Region:       "Jalisco",
+	}}
+	assert.Empty(t, eventIpAddressEntitiesTable([]api.EventIpAddressEntity{}, terminalWidth()))
+
+	// the wide table is displayed when the width of the terminal is unknown
+	os.Unsetenv("COLUMNS")
+	table := eventIpAddressEntitiesTable(ips, terminalWidth())
+	assert.Contains(t, table, "INBOUND BYTES")
+	assert.Contains(t, table, "Jalisco")
+
+	// and the narrow table when the wide table does not fit
+	os.Setenv("COLUMNS", "80")
+	table = eventIpAddressEntitiesTable(ips, terminalWidth())
+	assert.NotContains(t, table, "INBOUND BYTES")
+	assert.Contains(t, table, "THREAT TAGS")
+	assert.Contains(t, table, "botnet")
+
+	// every column is displayed, expanded since the table does not fit
+	eventsCmdState.Wide = true
+	assert.Contains(t, eventIpAddressEntitiesTable(ips, terminalWidth()), "| Inbound Bytes   | 1024.000")
+
+	eventsCmdState.Wide = false
+	eventsCmdState.Narrow = true
+	os.Unsetenv("COLUMNS")
+	assert.NotContains(t, eventIpAddressEntitiesTable(ips, terminalWidth()), "INBOUND BYTES")
+}
+
+func TestEventEntitiesTable(t *testing.T) {
+	defer func(expand bool) { eventsCmdState.Expand = expand }(eventsCmdState.Expand)
+	defer os.Setenv("COLUMNS", os.Getenv("COLUMNS"))
+	os.Unsetenv("COLUMNS")
+
+	var (
+		headers = []string{"Username", "Hostname"}
+		rows    = [][]string{{"root", "host-1"}, {"admin", "host-2"}}
+	)
+
+	assert.Equal(t, `+----------+----------+
+| USERNAME | HOSTNAME |
++----------+----------+
+| root     | host-1   |
+| admin    | host-2   |
++----------+----------+
+`, eventEntitiesTable(headers, rows, terminalWidth()))
+
+	// tables that do not fit in the terminal are expanded
+	os.Setenv("COLUMNS", "20")
+	expanded := `+----------+--------+
+| Username | root   |
+| Hostname | host-1 |
++----------+--------+
++----------+--------+
+| Username | admin  |
+| Hostname | host-2 |
++----------+--------+
+`
+	assert.Equal(t, expanded, eventEntitiesTable(headers, rows, terminalWidth()))
+
+	os.Unsetenv("COLUMNS")
+	eventsCmdState.Expand = true
+	assert.Equal(t, expanded, eventEntitiesTable(headers, rows, terminalWidth()))
+}
+
+func TestValidateEventID(t *testing.T) {
+	assert.Nil(t, validateEventID("123"))
+	err := validateEventID("123abc")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "invalid event id 123abc. Event id should be a numeric value", err.Error())
+	}
+}
+
+func TestSelectEventsTableColumns(t *testing.T) {
+	columns, err := selectEventsTableColumns([]string{})
+	if assert.Nil(t, err) {
+		assert.Equal(t,
+			[]string{"Event ID", "Type", "Severity", "Start Time", "End Time"},
+			eventsTableHeaders(columns),
+		)
+	}
+
+	columns, err = selectEventsTableColumns([]string{"severity", " ID ", "start"})
+	if assert.Nil(t, err) {
+		assert.Equal(t,
+			[]string{"Severity", "Event ID", "Start Time"},
+			eventsTableHeaders(columns),
+		)
+	}
+
+	_, err = selectEventsTableColumns([]string{"id", "actor"})
+	assert.EqualError(t, err,
+		"unknown column 'actor', use one of id, type, severity, start, end")
+
+	_, err = selectEventsTableColumns([]string{"id", "id"})
+	assert.EqualError(t, err, "the column 'id' was selected more than once")
+}
+
+func TestEventsToTableWithColumns(t *testing.T) {
+	columns, err := selectEventsTableColumns([]string{"severity", "id"})
+	assert.Nil(t, err)
+
+	events := []api.Event{
+		{EventID: "123", EventType: "NewUser", Severity: "5"},
+		{EventID: "456", EventType: "NewVPC", Severity: "2"},
+	}
+	assert.Equal(t,
+		[][]string{{"Info", "123"}, {"High", "456"}},
+		eventsToTable(events, columns),
+	)
+}
+
+func TestCountEventsByType(t *testing.T) {
+	events := []api.Event{
+		{EventID: "1", EventType: "NewUser"},
+		{EventID: "2", EventType: "NewVPC"},
+		{EventID: "3", EventType: "NewUser"},
+		{EventID: "4", EventType: "CloudTrailDefaultAlert"},
+	}
+
+	counts := countEventsByType(events)
+	assert.Equal(t,
+		map[string]int{"NewUser": 2, "NewVPC": 1, "CloudTrailDefaultAlert": 1},
+		counts,
+	)
+	assert.Equal(t,
+		[][]string{{"NewUser", "2"}, {"CloudTrailDefaultAlert", "1"}, {"NewVPC", "1"}},
+		eventTypeCountsToTable(counts),
+	)
+	assert.Empty(t, countEventsByType([]api.Event{}))
+}
+
+func TestGroupEventsByType(t *testing.T) {
+	events := []api.Event{
+		{EventID: "1", EventType: "NewUser"},
+		{EventID: "2", EventType: "NewVPC"},
+		{EventID: "3", EventType: "NewUser"},
+		{EventID: "4", EventType: "CloudTrailDefaultAlert"},
+	}
+
+	groups := groupEventsByType(events)
+	if assert.Len(t, groups, 3) {
+		assert.Equal(t, "NewUser", groups[0].EventType)
+		assert.Equal(t, 2, groups[0].Count)
+		assert.Equal(t, []api.Event{events[0], events[2]}, groups[0].Events)
+		assert.Equal(t, "CloudTrailDefaultAlert", groups[1].EventType)
+		assert.Equal(t, "NewVPC", groups[2].EventType)
+	}
+	assert.Empty(t, groupEventsByType([]api.Event{}))
+
+	columns, err := selectEventsTableColumns([]string{"id"})
+	if assert.Nil(t, err) {
+		report := eventsGroupedByTypeReport(events, columns)
+		assert.Contains(t, report, "NewUser (2 events)\n")
+		assert.True(t,
+			strings.Index(report, "NewUser") < strings.Index(report, "NewVPC"),
+			"the event types should be ordered by the number of events",
+		)
+	}
+}
+
+func TestFilterEventEntitiesFirstSeenAfter(t *testing.T) {
+	var (
+		after    = time.Date(2020, 8, 20, 8, 0, 0, 0, time.UTC)
+		old      = after.Add(-time.Hour)
+		recent   = after.Add(time.Hour)
+		entities = api.EventEntityMap{
+			IpAddress: []api.EventIpAddressEntity{
+				{IpAddress: "10.0.0.1", FirstSeenTime: old},
+				{IpAddress: "10.0.0.2", FirstSeenTime: recent},
+			},
+			FileDataHash: []api.EventFileDataHashEntity{
+				{FiledataHash: "abc", FirstSeenTime: after},
+			},
+			FileExePath: []api.EventFileExePathEntity{
+				{ExePath: "/bin/new", FirstSeenTime: recent},
+				{ExePath: "/bin/old", FirstSeenTime: old},
+			},
+			Machine: []api.EventMachineEntity{{Hostname: "host"}},
+		}
+	)
+
+	filterEventEntitiesFirstSeenAfter(&entities, after)
+	if assert.Len(t, entities.IpAddress, 1) {
+		assert.Equal(t, "10.0.0.2", entities.IpAddress[0].IpAddress)
+	}
+	assert.Empty(t, entities.FileDataHash)
+	if assert.Len(t, entities.FileExePath, 1) {
+		assert.Equal(t, "/bin/new", entities.FileExePath[0].ExePath)
+	}
+	assert.Len(t, entities.Machine, 1, "other entities should not be filtered")
+}
+
+func TestKnownBadEventEntities(t *testing.T) {
+	defer func(knownBadOnly bool) { eventsCmdState.KnownBadOnly = knownBadOnly }(eventsCmdState.KnownBadOnly)
+	eventsCmdState.KnownBadOnly = true
+
+	entities := knownBadEventEntities(api.EventEntityMap{
+		FileDataHash: []api.EventFileDataHashEntity{
+			{FiledataHash: "good", IsKnownBad: 0},
+			{FiledataHash: "bad", IsKnownBad: 1},
+		},
+		Machine: []api.EventMachineEntity{{Hostname: "host"}},
+	})
+	if assert.Len(t, entities.FileDataHash, 1) {
+		assert.Equal(t, "bad", entities.FileDataHash[0].FiledataHash)
+	}
+	assert.Empty(t, entities.Machine)
+
+	report := eventDetailsReport(api.EventDetails{EventID: "123", EntityMap: knownBadEventEntities(api.EventEntityMap{
+		FileDataHash: []api.EventFileDataHashEntity{{FiledataHash: "good"}},
+	})})
+	assert.Contains(t, report, "There are no file hashes flagged as known-bad in this event.")
+}
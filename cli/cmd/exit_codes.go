@@ -0,0 +1,115 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+// The exit codes of the Lacework CLI, scripts can rely on them to know why
+// a command failed, they are documented in the help of the root command
+//
+// The exit code 3 is reserved for the policies or thresholds that are not
+// met, it will be documented once a flag like --fail-on exists
+const (
+	exitCodeSuccess   = 0   // the command succeeded
+	exitCodeError     = 1   // generic error
+	exitCodeUsage     = 2   // unknown command or flag, invalid arguments
+	exitCodeAuth      = 4   // the API rejected the credentials (401 or 403)
+	exitCodeNotFound  = 5   // the requested resource could not be found
+	exitCodeNoCommand = 127 // no command was provided, the help was printed
+)
+
+// exitCodesHelp documents the exit codes of the Lacework CLI
+const exitCodesHelp = `Exit Codes:
+  0    success
+  1    generic error
+  2    usage error (unknown command or flag, invalid arguments)
+  4    authentication error, the credentials are invalid or have no access
+  5    the requested resource was not found`
+
+// exitError is an error that carries the exit code of the Lacework CLI
+type exitError struct {
+	err  error
+	code int
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Cause() error  { return e.err }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode annotates the provided error with an exit code, the code is
+// kept when the error is wrapped with more context
+func withExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{err: err, code: code}
+}
+
+// exitCode returns the exit code that corresponds to the provided error,
+// this is the only place where errors are mapped to exit codes
+func exitCode(err error) int {
+	if err == nil {
+		return exitCodeSuccess
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	switch {
+	case api.IsAuthError(err):
+		return exitCodeAuth
+	case api.IsNotFound(err):
+		return exitCodeNotFound
+	case strings.HasPrefix(err.Error(), "unknown command "):
+		// returned by cobra when the command doesn't exist
+		return exitCodeUsage
+	default:
+		return exitCodeError
+	}
+}
+
+// setUsageExitCodes annotates the errors of unknown or invalid flags and
+// invalid arguments of the provided command and all its subcommands with
+// the usage exit code
+func setUsageExitCodes(cmd *cobra.Command) {
+	if !cmd.HasParent() {
+		cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+			return withExitCode(err, exitCodeUsage)
+		})
+	}
+
+	if validator := cmd.Args; validator != nil {
+		cmd.Args = func(c *cobra.Command, args []string) error {
+			return withExitCode(validator(c, args), exitCodeUsage)
+		}
+	}
+
+	for _, subCmd := range cmd.Commands() {
+		setUsageExitCodes(subCmd)
+	}
+}
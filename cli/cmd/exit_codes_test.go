@@ -0,0 +1,101 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/internal/lacework"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, exitCodeSuccess, exitCode(nil))
+	assert.Equal(t, exitCodeError, exitCode(errors.New("boom")))
+	assert.Equal(t, exitCodeUsage,
+		exitCode(errors.New(`unknown command "foo" for "lacework"`)))
+
+	err := withExitCode(errors.New("not found"), exitCodeNotFound)
+	assert.Equal(t, exitCodeNotFound, exitCode(err))
+	assert.Equal(t, exitCodeNotFound, exitCode(errors.Wrap(err, "unable to scan")),
+		"the exit code should be kept when the error is wrapped")
+	assert.Equal(t, "unable to scan: not found",
+		errors.Wrap(err, "unable to scan").Error())
+	assert.Nil(t, withExitCode(nil, exitCodeUsage))
+
+	assert.Equal(t, exitCodeNotFound, exitCode(profileNotFoundError("foo")))
+}
+
+func TestExitCodeOfAPIErrors(t *testing.T) {
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI("external/integrations/UNAUTHORIZED",
+		func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"ok": false, "message": "Unauthorized"}`, http.StatusUnauthorized)
+		},
+	)
+	fakeServer.MockAPI("external/integrations/NOTFOUND",
+		func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"ok": false, "message": "Not Found"}`, http.StatusNotFound)
+		},
+	)
+	defer fakeServer.Close()
+
+	c, err := api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	_, err = c.Integrations.Get("UNAUTHORIZED")
+	assert.Equal(t, exitCodeAuth, exitCode(errors.Wrap(err, "unable to get integration")))
+
+	_, err = c.Integrations.Get("NOTFOUND")
+	assert.Equal(t, exitCodeNotFound, exitCode(errors.Wrap(err, "unable to get integration")))
+}
+
+func TestSetUsageExitCodes(t *testing.T) {
+	root := &cobra.Command{Use: "root"}
+	sub := &cobra.Command{
+		Use:  "sub",
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, _ []string) error { return nil },
+	}
+	root.AddCommand(sub)
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	setUsageExitCodes(root)
+
+	root.SetArgs([]string{"sub"})
+	assert.Equal(t, exitCodeUsage, exitCode(root.Execute()),
+		"invalid arguments should be usage errors")
+
+	root.SetArgs([]string{"sub", "one", "--bogus"})
+	assert.Equal(t, exitCodeUsage, exitCode(root.Execute()),
+		"unknown flags should be usage errors")
+
+	root.SetArgs([]string{"sub", "one"})
+	assert.Equal(t, exitCodeSuccess, exitCode(root.Execute()))
+}
@@ -24,27 +24,53 @@ import (
 	"github.com/pkg/errors"
 )
 
-// parse the start and end time provided by the user
+const (
+	// the format of the flags --start and --end, in the notation used by the
+	// Lacework API, it is the Go layout time.RFC3339
+	timeRangeFormat = "yyyy-MM-ddTHH:mm:ssZ"
+
+	// an example of the format of the flags --start and --end
+	timeRangeExample = "2020-08-20T08:00:00Z"
+)
+
+// parseStartAndEndTime parses the start and end time provided by the user,
+// the end time defaults to now when only the start time is provided, the
+// returned times are always in UTC and the start time precedes the end time
 func parseStartAndEndTime(s, e string) (start time.Time, end time.Time, err error) {
 	if s == "" {
 		err = errors.New("when providing an end time, start time should be provided (--start)")
 		return
 	}
-	start, err = time.Parse(time.RFC3339, s)
+	start, err = parseTimeRangeFlag("start", s)
 	if err != nil {
-		err = errors.Wrap(err, "unable to parse start time")
 		return
 	}
 
 	if e == "" {
-		end = time.Now()
-		return
-	}
-	end, err = time.Parse(time.RFC3339, e)
-	if err != nil {
-		err = errors.Wrap(err, "unable to parse end time")
-		return
+		end = time.Now().UTC()
+	} else {
+		end, err = parseTimeRangeFlag("end", e)
+		if err != nil {
+			return
+		}
 	}
 
+	if !start.Before(end) {
+		err = errors.Errorf("the start time %s should be before the end time %s",
+			start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
 	return
 }
+
+// parseTimeRangeFlag parses the value of the provided time range flag
+// (start or end) and returns it in UTC
+func parseTimeRangeFlag(name, value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, errors.Errorf(
+			"unable to parse %s time '%s', use the format %s (i.e. %s)",
+			name, value, timeRangeFormat, timeRangeExample,
+		)
+	}
+	return t.UTC(), nil
+}
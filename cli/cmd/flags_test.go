@@ -0,0 +1,94 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStartAndEndTime(t *testing.T) {
+	start, end, err := parseStartAndEndTime("2020-08-20T08:00:00Z", "2020-08-21T08:00:00Z")
+	if assert.Nil(t, err) {
+		assert.Equal(t, time.Date(2020, 8, 20, 8, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2020, 8, 21, 8, 0, 0, 0, time.UTC), end)
+	}
+
+	// times with an offset are converted to UTC
+	start, end, err = parseStartAndEndTime("2020-08-20T08:00:00-07:00", "2020-08-21T08:00:00+02:00")
+	if assert.Nil(t, err) {
+		assert.Equal(t, time.Date(2020, 8, 20, 15, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2020, 8, 21, 6, 0, 0, 0, time.UTC), end)
+		assert.Equal(t, time.UTC, start.Location())
+		assert.Equal(t, time.UTC, end.Location())
+	}
+
+	// one second is the smallest valid time range
+	_, _, err = parseStartAndEndTime("2020-08-20T08:00:00Z", "2020-08-20T08:00:01Z")
+	assert.Nil(t, err)
+}
+
+func TestParseStartAndEndTimeDefaultEnd(t *testing.T) {
+	before := time.Now().UTC()
+	start, end, err := parseStartAndEndTime("2020-08-20T08:00:00Z", "")
+	if assert.Nil(t, err) {
+		assert.Equal(t, time.Date(2020, 8, 20, 8, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.UTC, end.Location())
+		assert.False(t, end.Before(before), "the end time should default to now")
+		assert.False(t, end.After(time.Now().UTC()), "the end time should default to now")
+	}
+
+	future := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+	_, _, err = parseStartAndEndTime(future, "")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "should be before the end time")
+	}
+}
+
+func TestParseStartAndEndTimeErrors(t *testing.T) {
+	cases := []struct {
+		start, end, expected string
+	}{
+		{"", "2020-08-21T08:00:00Z",
+			"when providing an end time, start time should be provided (--start)"},
+		{"2020-08-20", "",
+			"unable to parse start time '2020-08-20', use the format yyyy-MM-ddTHH:mm:ssZ (i.e. 2020-08-20T08:00:00Z)"},
+		{"yesterday", "2020-08-21T08:00:00Z",
+			"unable to parse start time 'yesterday', use the format yyyy-MM-ddTHH:mm:ssZ (i.e. 2020-08-20T08:00:00Z)"},
+		{"2020-08-20T08:00:00Z", "2020-08-21 08:00:00",
+			"unable to parse end time '2020-08-21 08:00:00', use the format yyyy-MM-ddTHH:mm:ssZ (i.e. 2020-08-20T08:00:00Z)"},
+		{"2020-08-20T08:00:00Z", "2020-13-01T08:00:00Z",
+			"unable to parse end time '2020-13-01T08:00:00Z', use the format yyyy-MM-ddTHH:mm:ssZ (i.e. 2020-08-20T08:00:00Z)"},
+		{"2020-08-21T08:00:00Z", "2020-08-20T08:00:00Z",
+			"the start time 2020-08-21T08:00:00Z should be before the end time 2020-08-20T08:00:00Z"},
+		{"2020-08-20T08:00:00Z", "2020-08-20T08:00:00Z",
+			"the start time 2020-08-20T08:00:00Z should be before the end time 2020-08-20T08:00:00Z"},
+		{"2020-08-20T08:00:00Z", "2020-08-20T01:00:00-07:00",
+			"the start time 2020-08-20T08:00:00Z should be before the end time 2020-08-20T08:00:00Z"},
+	}
+
+	for _, c := range cases {
+		_, _, err := parseStartAndEndTime(c.start, c.end)
+		if assert.NotNil(t, err, "start: %s end: %s", c.start, c.end) {
+			assert.Equal(t, c.expected, err.Error())
+		}
+	}
+}
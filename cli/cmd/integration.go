@@ -67,8 +67,8 @@ var (
 				return errors.Wrap(err, "unable to get integrations")
 			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(integrations.Data)
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(integrations.Data)
 			}
 
 			if len(integrations.Data) == 0 {
@@ -92,14 +92,14 @@ var (
 				return errors.Wrap(err, "unable to get integration")
 			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(integration.Data)
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(integration.Data)
 			}
 
 			if len(integration.Data) == 0 {
 				msg := "the provided integration GUID was not found\n\n"
 				msg += "To list the available integrations in your account run 'lacework integrations list'"
-				return errors.New(msg)
+				return withExitCode(errors.New(msg), exitCodeNotFound)
 			}
 
 			cli.OutputHuman(buildIntegrationsTable(integration.Data))
@@ -157,8 +157,8 @@ GUIDs can be found by using the 'lacework integration list' command.`,
 				return errors.Wrap(err, "unable to delete integration")
 			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response.Data)
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(response.Data)
 			}
 
 			cli.OutputHuman("The integration %s was deleted.\n", args[0])
@@ -19,15 +19,120 @@
 package cmd
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
 )
 
+const (
+	outputFormatTable = "table"
+	outputFormatJSON  = "json"
+	outputFormatCSV   = "csv"
+	outputFormatYAML  = "yaml"
+)
+
+// styles of the JSON output, pretty is the default, the flag --json-compact
+// prints it in a single line and the flag --jsonl prints lists as JSON Lines
+const (
+	jsonStylePretty  = "pretty"
+	jsonStyleCompact = "compact"
+	jsonStyleLines   = "lines"
+)
+
+// validOutputFormats are the formats accepted by the global flag --output
+var validOutputFormats = []string{
+	outputFormatTable,
+	outputFormatJSON,
+	outputFormatCSV,
+	outputFormatYAML,
+}
+
+// OutputStructured will print out the provided data in the machine readable
+// format that the cli is configured to display, JSON or YAML, commands that
+// support the CSV format must call OutputCSV() before calling this function
+func (c *cliState) OutputStructured(v interface{}) error {
+	switch c.OutputFormat() {
+	case outputFormatYAML:
+		return c.OutputYAML(v)
+	case outputFormatCSV:
+		return errors.Errorf(
+			"output format '%s' is not supported by this command (supported formats: %s, %s, %s)",
+			outputFormatCSV, outputFormatTable, outputFormatJSON, outputFormatYAML,
+		)
+	default:
+		return c.OutputJSON(v)
+	}
+}
+
+// OutputYAML will print out the YAML representation of the provided data,
+// the keys of the YAML document are the same as the ones of the JSON output
+func (c *cliState) OutputYAML(v interface{}) error {
+	if err := writeYAML(os.Stdout, v); err != nil {
+		c.Log.Debugw("unable to print YAML object", "raw", v)
+		return err
+	}
+	return nil
+}
+
+// OutputCSV will print out the provided headers and rows in CSV format
+func (c *cliState) OutputCSV(headers []string, rows [][]string) error {
+	return writeCSV(os.Stdout, headers, rows)
+}
+
+// writeYAML writes the YAML representation of the provided data, the keys
+// of the YAML document are the same as the ones of the JSON representation
+func writeYAML(w io.Writer, v interface{}) error {
+	// convert the data to JSON first to honor the json struct tags
+	jsonData, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var data interface{}
+	if err := yaml.Unmarshal(jsonData, &data); err != nil {
+		return err
+	}
+
+	yamlData, err := yaml.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(w, string(yamlData))
+	return err
+}
+
+// writeCSV writes the provided headers and rows in CSV format
+func writeCSV(w io.Writer, headers []string, rows [][]string) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(headers); err != nil {
+		return err
+	}
+	if err := csvWriter.WriteAll(rows); err != nil {
+		return errors.Wrap(err, "unable to write CSV output")
+	}
+	return nil
+}
+
 // OutputJSON will print out the JSON representation of the provided data
+// in the style that the cli is configured to display, pretty by default
 func (c *cliState) OutputJSON(v interface{}) error {
+	switch c.JSONStyle() {
+	case jsonStyleCompact:
+		return writeJSONCompact(os.Stdout, v)
+	case jsonStyleLines:
+		return writeJSONLines(os.Stdout, v)
+	}
+
 	pretty, err := c.JsonF.Marshal(v)
 	if err != nil {
 		c.Log.Debugw("unable to pretty print JSON object", "raw", v)
@@ -38,15 +143,53 @@ func (c *cliState) OutputJSON(v interface{}) error {
 }
 
 // OutputHumanRead will print out the provided message if the cli state is
-// configured to talk to humans, to switch to json format use --json
+// configured to talk to humans, to switch to a different format use --output,
+// the message is written to the pager while it is running, see StartPager()
 func (c *cliState) OutputHuman(format string, a ...interface{}) {
 	if c.HumanOutput() {
-		fmt.Fprintf(os.Stdout, format, a...)
+		fmt.Fprintf(c.humanOutput(), format, a...)
+	}
+}
+
+// writeJSONCompact writes the JSON representation of the provided data
+// into a single line
+func writeJSONCompact(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode JSON object")
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// writeJSONLines writes every element of the provided list into its own line
+// without an enclosing array (https://jsonlines.org), any other data that is
+// not a list is written into a single line
+func writeJSONLines(w io.Writer, v interface{}) error {
+	list := reflect.ValueOf(v)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return writeJSONCompact(w, v)
+	}
+
+	for i := 0; i < list.Len(); i++ {
+		if err := writeJSONCompact(w, list.Index(i).Interface()); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // OutputJSONString is just like OutputJSON but from a JSON string
 func (c *cliState) OutputJSONString(s string) error {
+	if c.JSONStyle() != jsonStylePretty {
+		var v interface{}
+		if err := json.Unmarshal([]byte(strings.Trim(s, "'")), &v); err != nil {
+			c.Log.Debugw("unable to decode JSON string", "raw", s)
+			return err
+		}
+		return c.OutputJSON(v)
+	}
+
 	pretty, err := c.FormatJSONString(s)
 	if err != nil {
 		return err
@@ -64,3 +207,28 @@ func (c *cliState) FormatJSONString(s string) (string, error) {
 	}
 	return string(pretty), nil
 }
+
+// terminalWidth returns the number of columns of the terminal where the cli
+// is running, the environment variable COLUMNS takes precedence, it returns
+// zero when the width is unknown, like when the output is not a terminal
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return stdoutTerminalWidth()
+}
+
+// fitsInWidth returns true if every line of the provided text fits in the
+// provided width, or if the width is unknown (zero)
+func fitsInWidth(text string, width int) bool {
+	if width == 0 {
+		return true
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if utf8.RuneCountInString(line) > width {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultPager is the pager used when the environment variable PAGER is not
+// set, the flag -R passes the colors of the output through
+const defaultPager = "less -R"
+
+// pager is a running process, like less, where the human-readable output
+// of the cli is written while it is active, see StartPager()
+type pager struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+}
+
+// Paginate returns true if the long human-readable output of the commands
+// should be piped through a pager, see SetPaginate()
+func (c *cliState) Paginate() bool {
+	return c.paginate
+}
+
+// SetPaginate turns on or off the pager of the long human-readable output
+func (c *cliState) SetPaginate(paginate bool) {
+	c.Log.Infow("switch pager", "paginate", paginate)
+	c.paginate = paginate
+}
+
+// StartPager starts the pager configured with the environment variable PAGER,
+// or 'less -R' by default, the human-readable output is written to the pager
+// until StopPager() is called. Like git, the pager is not started when it is
+// turned off, when the output is not a terminal or when the output is not for
+// humans, if the pager cannot be started, the output is written to stdout
+func (c *cliState) StartPager() {
+	if !c.paginate || c.pager != nil || !c.HumanOutput() || !stdoutIsTerminal() {
+		return
+	}
+
+	args := pagerCommand()
+	if len(args) == 0 {
+		c.Log.Debugw("pager turned off by environment variable", "PAGER", os.Getenv("PAGER"))
+		return
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = pagerEnviron(os.Environ())
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		c.Log.Debugw("unable to start pager", "pager", args, "error", err)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		c.Log.Debugw("unable to start pager", "pager", args, "error", err)
+		return
+	}
+
+	c.Log.Debugw("pager started", "pager", args)
+	c.pager = &pager{cmd: cmd, stdin: stdin}
+}
+
+// StopPager waits for the user to exit the running pager, if any
+func (c *cliState) StopPager() {
+	if c.pager == nil {
+		return
+	}
+
+	c.pager.stdin.Close()
+	if err := c.pager.cmd.Wait(); err != nil {
+		c.Log.Debugw("pager exited with error", "error", err)
+	}
+	c.pager = nil
+}
+
+// humanOutput returns where the human-readable output is written, the
+// running pager or stdout
+func (c *cliState) humanOutput() io.Writer {
+	if c.pager != nil {
+		return c.pager.stdin
+	}
+	return os.Stdout
+}
+
+// pagerCommand returns the command and arguments of the pager, an empty
+// PAGER or 'cat' turns off the pager, just like git does
+func pagerCommand() []string {
+	command, ok := os.LookupEnv("PAGER")
+	if !ok {
+		command = defaultPager
+	}
+
+	args := strings.Fields(command)
+	if len(args) == 0 || args[0] == "cat" {
+		return nil
+	}
+	return args
+}
+
+// pagerEnviron returns the environment of the pager, when LESS is not set it
+// is set to FRX, that is, exit if the output fits in one screen, pass the
+// colors through and do not clear the screen on exit
+func pagerEnviron(environ []string) []string {
+	for _, env := range environ {
+		if strings.HasPrefix(env, "LESS=") {
+			return environ
+		}
+	}
+	return append(environ, "LESS=FRX")
+}
@@ -0,0 +1,74 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestPagerCommand(t *testing.T) {
+	defer func(pager string, ok bool) {
+		if ok {
+			os.Setenv("PAGER", pager)
+		} else {
+			os.Unsetenv("PAGER")
+		}
+	}(os.LookupEnv("PAGER"))
+
+	os.Unsetenv("PAGER")
+	assert.Equal(t, []string{"less", "-R"}, pagerCommand())
+
+	os.Setenv("PAGER", "more -d")
+	assert.Equal(t, []string{"more", "-d"}, pagerCommand())
+
+	os.Setenv("PAGER", "cat")
+	assert.Nil(t, pagerCommand(), "cat turns off the pager")
+
+	os.Setenv("PAGER", "")
+	assert.Nil(t, pagerCommand(), "an empty PAGER turns off the pager")
+}
+
+func TestPagerEnviron(t *testing.T) {
+	assert.Equal(t,
+		[]string{"HOME=/root", "LESS=FRX"},
+		pagerEnviron([]string{"HOME=/root"}),
+	)
+	assert.Equal(t,
+		[]string{"LESS=S"},
+		pagerEnviron([]string{"LESS=S"}),
+		"an existing LESS should not be overridden",
+	)
+}
+
+func TestStartPagerNotTerminal(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+
+	// the output of the tests is not a terminal
+	cli.SetPaginate(true)
+	cli.StartPager()
+	assert.Nil(t, cli.pager)
+	assert.Equal(t, os.Stdout, cli.humanOutput())
+	cli.StopPager()
+}
@@ -21,12 +21,15 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwconfig"
 	"github.com/lacework/go-sdk/lwlogger"
 )
 
@@ -48,8 +51,15 @@ Start by configuring the Lacework CLI with the command:
 
     $ lacework configure
 
-This will prompt you for your Lacework account and a set of API access keys.`,
+This will prompt you for your Lacework account and a set of API access keys.
+
+` + exitCodesHelp,
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			// completion functions that need the API create their own client
+			if isCompletionCommand() {
+				return nil
+			}
+
 			switch cmd.Use {
 			case "help [command]", "configure", "version", "generate-pkg-manifest":
 				return nil
@@ -58,6 +68,10 @@ This will prompt you for your Lacework account and a set of API access keys.`,
 				if cmd.HasParent() && cmd.Parent().Use == "configure" {
 					return nil
 				}
+				// offline scans use a local vulnerability database
+				if cmd == vulHostScanPkgManifestCmd && pkgManifestOffline {
+					return nil
+				}
 				return cli.NewClient()
 			}
 		},
@@ -71,9 +85,10 @@ func Execute() {
 	// if no command was provided, only print out the usage message
 	if noCommandProvided() {
 		errcheckWARN(rootCmd.Help())
-		os.Exit(127)
+		os.Exit(exitCodeNoCommand)
 	}
 
+	setUsageExitCodes(rootCmd)
 	errcheckEXIT(rootCmd.Execute())
 }
 
@@ -81,20 +96,53 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().Bool("debug", false,
-		"turn on debug logging",
+		"turn on debug logging, same as --log-level debug",
+	)
+	rootCmd.PersistentFlags().Bool("debug-http", false,
+		"log the API requests and responses (URLs, headers and bodies) with their secrets redacted",
+	)
+	rootCmd.PersistentFlags().String("log-level", "",
+		"level of the logs written to stderr: info or debug",
+	)
+	rootCmd.PersistentFlags().String("log-format", "console",
+		"format of the logs written to stderr: console or json",
+	)
+	rootCmd.PersistentFlags().Bool("no-color", false,
+		"turn off colors, also set with the environment variable NO_COLOR",
 	)
 	rootCmd.PersistentFlags().Bool("nocolor", false,
-		"turn off colors",
+		"(deprecated) alias of --no-color",
 	)
 	rootCmd.PersistentFlags().Bool("noninteractive", false,
 		"turn off interactive mode (disable spinners, prompts, etc.)",
 	)
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false,
+		"suppress informational output like hints and banners, print only the data",
+	)
 	rootCmd.PersistentFlags().Bool("json", false,
-		"switch commands output from human-readable to json format",
+		"(deprecated) alias of --output json",
+	)
+	rootCmd.PersistentFlags().StringP("output", "o", outputFormatTable,
+		"output format of the commands: table, json, csv or yaml",
+	)
+	rootCmd.PersistentFlags().Bool("json-compact", false,
+		"print the JSON output in a single line",
+	)
+	rootCmd.PersistentFlags().Bool("jsonl", false,
+		"print lists in the JSON output one element per line (JSON Lines)",
+	)
+	rootCmd.PersistentFlags().Bool("paginate", false,
+		"pipe long tables through $PAGER or 'less -R' when the output is a terminal",
+	)
+	rootCmd.PersistentFlags().Bool("no-pager", false,
+		"do not pipe the output through a pager, overrides --paginate",
 	)
 	rootCmd.PersistentFlags().StringP("profile", "p", "",
 		"switch between profiles configured at ~/.lacework.toml",
 	)
+	rootCmd.PersistentFlags().String("config", "",
+		"path to the config file, also set with LW_CONFIG (default ~/.lacework.toml)",
+	)
 	rootCmd.PersistentFlags().StringP("api_key", "k", "",
 		"access key id",
 	)
@@ -102,94 +150,176 @@ func init() {
 		"secret access key",
 	)
 	rootCmd.PersistentFlags().StringP("account", "a", "",
-		"account subdomain of URL (i.e. <ACCOUNT>.lacework.net)",
+		"account subdomain of URL (i.e. <ACCOUNT>.lacework.net), overrides the account of the profile",
+	)
+	rootCmd.PersistentFlags().String("subaccount", "",
+		"sub-account name inside your organization (org admins only)",
+	)
+	rootCmd.PersistentFlags().String("ca-cert", "",
+		"path to a PEM file of certificate authorities to trust, like the CA of a TLS-intercepting proxy",
+	)
+	rootCmd.PersistentFlags().Bool("insecure-skip-verify", false,
+		"turn off the verification of TLS certificates (insecure, prefer --ca-cert)",
+	)
+	rootCmd.PersistentFlags().Int("concurrency", api.DefaultConcurrency,
+		"number of API requests that bulk operations, like a directory scan, send at once",
+	)
+	rootCmd.PersistentFlags().Bool("no-retry", false,
+		"turn off retries of API requests on transient errors",
+	)
+	rootCmd.PersistentFlags().String("timeout", "",
+		"timeout of the API requests in seconds or as a duration like 2m (default 60s)",
+	)
+	rootCmd.PersistentFlags().String("timezone", "",
+		"time zone of the timestamps in the output, an IANA name like America/New_York (default UTC)",
 	)
 
 	errcheckWARN(viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug")))
+	errcheckWARN(viper.BindPFlag("debug_http", rootCmd.PersistentFlags().Lookup("debug-http")))
+	errcheckWARN(viper.BindPFlag("log_level", rootCmd.PersistentFlags().Lookup("log-level")))
+	errcheckWARN(viper.BindPFlag("log_format", rootCmd.PersistentFlags().Lookup("log-format")))
 	errcheckWARN(viper.BindPFlag("nocolor", rootCmd.PersistentFlags().Lookup("nocolor")))
+	errcheckWARN(viper.BindPFlag("no_color", rootCmd.PersistentFlags().Lookup("no-color")))
 	errcheckWARN(viper.BindPFlag("noninteractive", rootCmd.PersistentFlags().Lookup("noninteractive")))
+	errcheckWARN(viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet")))
 	errcheckWARN(viper.BindPFlag("json", rootCmd.PersistentFlags().Lookup("json")))
+	errcheckWARN(viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output")))
+	errcheckWARN(viper.BindPFlag("json_compact", rootCmd.PersistentFlags().Lookup("json-compact")))
+	errcheckWARN(viper.BindPFlag("jsonl", rootCmd.PersistentFlags().Lookup("jsonl")))
+	errcheckWARN(viper.BindPFlag("paginate", rootCmd.PersistentFlags().Lookup("paginate")))
+	errcheckWARN(viper.BindPFlag("no_pager", rootCmd.PersistentFlags().Lookup("no-pager")))
 	errcheckWARN(viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile")))
+	errcheckWARN(viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config")))
 	errcheckWARN(viper.BindPFlag("account", rootCmd.PersistentFlags().Lookup("account")))
+	errcheckWARN(viper.BindPFlag("subaccount", rootCmd.PersistentFlags().Lookup("subaccount")))
+	errcheckWARN(viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout")))
+	errcheckWARN(viper.BindPFlag("timezone", rootCmd.PersistentFlags().Lookup("timezone")))
+	errcheckWARN(viper.BindPFlag("concurrency", rootCmd.PersistentFlags().Lookup("concurrency")))
+	errcheckWARN(viper.BindPFlag("no_retry", rootCmd.PersistentFlags().Lookup("no-retry")))
+	errcheckWARN(viper.BindPFlag("ca_cert", rootCmd.PersistentFlags().Lookup("ca-cert")))
+	errcheckWARN(viper.BindPFlag("insecure_skip_verify", rootCmd.PersistentFlags().Lookup("insecure-skip-verify")))
 	errcheckWARN(viper.BindPFlag("api_key", rootCmd.PersistentFlags().Lookup("api_key")))
 	errcheckWARN(viper.BindPFlag("api_secret", rootCmd.PersistentFlags().Lookup("api_secret")))
 }
 
 // initConfig reads in config file and ENV variables if set
 func initConfig() {
-	// Find home directory
-	home, err := homedir.Dir()
-	errcheckEXIT(err)
-
-	// Search config in home directory with name ".lacework" (without extension)
-	viper.AddConfigPath(home)
-	viper.SetConfigName(".lacework")
-
 	viper.SetConfigType("toml") // set TOML as the config format
 	viper.SetEnvPrefix("LW")    // set prefix for all env variables LW_ABC
 	viper.AutomaticEnv()        // read in environment variables that match
 
-	if viper.GetBool("debug") {
-		cli.LogLevel = "DEBUG"
+	// the config file provided via --config or LW_CONFIG takes precedence,
+	// otherwise, search config in home directory with name ".lacework"
+	if configFile := viper.GetString("config"); configFile != "" {
+		viper.SetConfigFile(configFile)
+	} else {
+		home, err := homedir.Dir()
+		errcheckEXIT(err)
+
+		viper.AddConfigPath(home)
+		viper.SetConfigName(".lacework")
 	}
 
 	// initialize a Lacework logger
-	cli.Log = lwlogger.New(cli.LogLevel).Sugar()
+	errcheckEXIT(initLogger())
 
-	if viper.GetBool("nocolor") {
+	// colors are also disabled automatically when the output is not a terminal,
+	// see https://no-color.org for the NO_COLOR environment variable
+	if viper.GetBool("no_color") || viper.GetBool("nocolor") || os.Getenv("NO_COLOR") != "" {
 		cli.Log.Info("turning off colors")
-		cli.JsonF.DisabledColor = true
+		cli.DisableColors()
 	}
 
 	if viper.GetBool("noninteractive") {
 		cli.NonInteractive()
 	}
 
+	if viper.GetBool("quiet") {
+		cli.SetQuiet(true)
+	}
+
+	// the pager can be turned on per environment with LW_PAGINATE=true,
+	// the flag --no-pager always turns it off
+	if viper.GetBool("paginate") && !viper.GetBool("no_pager") {
+		cli.SetPaginate(true)
+	}
+
+	if timezone := viper.GetString("timezone"); timezone != "" {
+		errcheckEXIT(cli.SetTimezone(timezone))
+	}
+
+	// the flag --json is deprecated but still works as an alias of --output json,
+	// an explicit --output flag takes precedence over it
 	if viper.GetBool("json") {
 		cli.EnableJSONOutput()
 	}
+	if viper.IsSet("output") {
+		errcheckEXIT(cli.SetOutputFormat(viper.GetString("output")))
+	}
 
-	// by default the cli logs are going to be visualized in
-	// a console format unless the user wants the opposite
-	if os.Getenv("LW_LOG_FORMAT") == "" {
-		if cli.JSONOutput() {
-			os.Setenv("LW_LOG_FORMAT", "JSON")
+	// the flags --json-compact and --jsonl change the style of the JSON output,
+	// they switch to JSON output unless a different format was requested
+	if viper.GetBool("json_compact") || viper.GetBool("jsonl") {
+		if viper.IsSet("output") && !cli.JSONOutput() {
+			exitwith(errors.New("the flags --json-compact and --jsonl can only be used with JSON output"))
+		}
+		cli.EnableJSONOutput()
+
+		if viper.GetBool("jsonl") {
+			cli.SetJSONStyle(jsonStyleLines)
 		} else {
-			os.Setenv("LW_LOG_FORMAT", "CONSOLE")
+			cli.SetJSONStyle(jsonStyleCompact)
 		}
 	}
 
 	// try to read config file
 	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			// the config file was not found; ignore error
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok || os.IsNotExist(err) {
+			// the config file was not found; ignore error, a config file provided
+			// via --config is created at that location by 'lacework configure'
 			cli.Log.Debugw("configuration file not found")
 		} else {
 			// the config file was found but another error was produced
 			errcheckWARN(rootCmd.Help())
 			cli.OutputHuman("\n")
-			exitwith(errors.Wrap(err, "unable to read in config file ~/.lacework.toml"))
+			exitwith(errors.Wrapf(err, "unable to read in config file %s", viper.ConfigFileUsed()))
 		}
 	} else {
 		cli.Log.Debugw("using configuration file",
 			"path", viper.ConfigFileUsed(),
 		)
+
+		// the config file contains API secrets, warn the user if other users
+		// can read it, unless we are about to fix it with 'configure verify'
+		if err := lwconfig.CheckPermissions(viper.ConfigFileUsed()); lwconfig.IsInsecurePermissions(err) &&
+			!configureVerifyFixPerms {
+			errcheckWARN(errors.Errorf("%s\n\n  Run 'lacework configure verify --fix-perms' to fix it.\n", err))
+		}
 	}
 
-	// get the profile passed as a parameter or environment variable
-	// if any, set it into the CLI state, that will trigger to load the
-	// state, if no profile was specified just load the default state
+	// get the profile passed as a parameter or environment variable, or
+	// the default profile stored in the config file, if any, set it into
+	// the CLI state, that will trigger to load the state, if no profile
+	// was specified just load the default state
+	//
+	// viper reads LW_PROFILE as the flag --profile, the alias LACEWORK_PROFILE
+	// is read below, both take precedence over the stored default profile
+	var err error
 	if p := viper.GetString("profile"); len(p) != 0 {
 		err = cli.SetProfile(p)
+	} else if p, _ := lwconfig.EnvProfile(); len(p) != 0 {
+		err = cli.SetProfile(p)
+	} else if p := viper.GetString("default_profile"); len(p) != 0 {
+		err = cli.SetProfile(p)
 	} else {
 		err = cli.LoadState()
 	}
 
 	if err != nil {
-		if isCommand("configure") {
+		if isCommand("configure") || isCompletionCommand() {
 			cli.Log.Debugw(
 				"error ignored",
-				"reason", "running configure cmd",
+				"reason", "running configure or completion cmd",
 				"error", err,
 			)
 		} else {
@@ -198,6 +328,48 @@ func initConfig() {
 	}
 }
 
+// initLogger initializes the logger of the cli with the level and format
+// provided by the flags --debug, --log-level and --log-format, the logs are
+// written to STDERR and the API client inherits their level and format, or
+// logs at debug level when --debug-http is provided
+func initLogger() error {
+	if viper.GetBool("debug") {
+		cli.LogLevel = "DEBUG"
+	}
+	cli.debugHTTP = viper.GetBool("debug_http")
+
+	if viper.IsSet("log_level") {
+		level := strings.ToUpper(viper.GetString("log_level"))
+		if level == "" || !lwlogger.ValidLevel(level) {
+			return errors.Errorf("invalid log level '%s' (valid levels: info, debug)",
+				viper.GetString("log_level"))
+		}
+		cli.LogLevel = level
+
+		// the logger gives priority to the environment variable, override
+		// it since the flag is more specific and the API client reads it
+		os.Setenv(lwlogger.LogLevelEnv, level)
+	}
+
+	// by default the cli logs are going to be visualized in a console
+	// format unless the user wants the opposite, or the output is JSON
+	if viper.IsSet("log_format") {
+		format := strings.ToUpper(viper.GetString("log_format"))
+		if format != "JSON" && format != "CONSOLE" {
+			return errors.Errorf("invalid log format '%s' (valid formats: console, json)",
+				viper.GetString("log_format"))
+		}
+		os.Setenv(lwlogger.LogFormatEnv, format)
+	} else if viper.GetBool("json") || strings.ToLower(viper.GetString("output")) == outputFormatJSON {
+		os.Setenv(lwlogger.LogFormatEnv, "JSON")
+	} else {
+		os.Setenv(lwlogger.LogFormatEnv, "CONSOLE")
+	}
+
+	cli.Log = lwlogger.New(cli.LogLevel).Sugar()
+	return nil
+}
+
 // isCommand checks the overall arguments passed to the lacework cli
 // and returns true if the provided command name is the one running
 func isCommand(cmd string) bool {
@@ -212,6 +384,14 @@ func isCommand(cmd string) bool {
 	return false
 }
 
+// isCompletionCommand returns true if the command running generates
+// shell completions, these commands do not need a configured profile
+func isCompletionCommand() bool {
+	return isCommand("completion") ||
+		isCommand(cobra.ShellCompRequestCmd) ||
+		isCommand(cobra.ShellCompNoDescRequestCmd)
+}
+
 // noCommandProvided checks if a command or argument was provided
 func noCommandProvided() bool {
 	return len(os.Args) <= 1
@@ -235,14 +415,35 @@ func errcheckWARN(err error) {
 	}
 }
 
-// exitwith prints out an error message and exits the program with exit code 1
+// exitwith prints out an error message and exits the program with the exit
+// code that corresponds to the error, see exitCode()
 func exitwith(err error) {
-	exitwithCode(err, 1)
+	exitwithCode(err, exitCode(err))
 }
 
 // exitwithCode prints out an error message and exits the program with
 // the provided exit code
 func exitwithCode(err error, code int) {
+	if requestID := api.RequestID(err); requestID != "" && cli.Log != nil {
+		cli.Log.Debugw("api request failed", "request_id", requestID)
+	}
 	fmt.Fprintf(os.Stderr, "ERROR %s\n", err)
+	if hint := apiErrorHint(err); hint != "" {
+		fmt.Fprintf(os.Stderr, "\n%s\n", hint)
+	}
 	os.Exit(code)
 }
+
+// apiErrorHint returns a tailored guidance for the user depending on the
+// type of API error, or an empty string if there is nothing to suggest
+func apiErrorHint(err error) string {
+	switch {
+	case api.IsAuthError(err):
+		return "Verify that your credentials are valid and have access to the account,\n" +
+			"to configure new credentials run the command: 'lacework configure'"
+	case api.IsRateLimited(err):
+		return "The Lacework API rate limit has been reached, please try again later."
+	default:
+		return ""
+	}
+}
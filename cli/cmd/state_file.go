@@ -0,0 +1,92 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cliStateFile is the state that the Lacework CLI keeps between runs, it is
+// stored in a state file separate from the config file ~/.lacework.toml
+// since it does not hold any settings or credentials, see stateFilePath()
+type cliStateFile struct {
+	// EventsBookmarks are the end times of the last 'events list --since-last'
+	// run of every account, see eventsBookmarkKey()
+	EventsBookmarks map[string]time.Time `json:"events_bookmarks,omitempty"`
+}
+
+// stateFilePath returns the path of the state file of the Lacework CLI, the
+// location can be changed with the environment variable LW_STATE_FILE
+func stateFilePath() (string, error) {
+	if statePath := os.Getenv("LW_STATE_FILE"); statePath != "" {
+		return statePath, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.Wrap(err, "unable to find the state directory")
+	}
+	return filepath.Join(configDir, "lacework", "state.json"), nil
+}
+
+// loadStateFile loads the state from the provided file, if the file does
+// not exist, it returns an empty state
+func loadStateFile(statePath string) (cliStateFile, error) {
+	state := cliStateFile{EventsBookmarks: map[string]time.Time{}}
+
+	data, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, errors.Wrap(err, "unable to read state file")
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, errors.Wrapf(err, "unable to decode state file %s", statePath)
+	}
+	if state.EventsBookmarks == nil {
+		state.EventsBookmarks = map[string]time.Time{}
+	}
+	return state, nil
+}
+
+// writeToFile writes the state into the provided file, only readable by
+// the current user, the directory of the file is created if needed
+func (s cliStateFile) writeToFile(statePath string) error {
+	if err := os.MkdirAll(filepath.Dir(statePath), 0700); err != nil {
+		return errors.Wrap(err, "unable to create state directory")
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to encode state file")
+	}
+
+	if err := ioutil.WriteFile(statePath, append(data, '\n'), 0600); err != nil {
+		return errors.Wrap(err, "unable to write state file")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// tableOption configures how a table is rendered by RenderTable()
+type tableOption func(*tablewriter.Table)
+
+// withTableBorder turns on or off the border of the table
+func withTableBorder(border bool) tableOption {
+	return func(t *tablewriter.Table) { t.SetBorder(border) }
+}
+
+// withTableAlignment sets the alignment of the cells of the table,
+// i.e. tablewriter.ALIGN_LEFT
+func withTableAlignment(align int) tableOption {
+	return func(t *tablewriter.Table) { t.SetAlignment(align) }
+}
+
+// withTableAutoWrap turns on or off the wrapping of long cells
+func withTableAutoWrap(wrap bool) tableOption {
+	return func(t *tablewriter.Table) { t.SetAutoWrapText(wrap) }
+}
+
+// withTableColWidth sets the width at which long cells are wrapped
+func withTableColWidth(width int) tableOption {
+	return func(t *tablewriter.Table) { t.SetColWidth(width) }
+}
+
+// withTableColumnSeparator sets the separator between the columns of the table
+func withTableColumnSeparator(separator string) tableOption {
+	return func(t *tablewriter.Table) { t.SetColumnSeparator(separator) }
+}
+
+// RenderTable renders the provided headers and rows as a table, by default,
+// the table has no border, cells are wrapped and the headers are formatted
+// in uppercase, use the table options to change these defaults. Tables
+// without headers, like key/value tables, are rendered with nil headers
+func (c *cliState) RenderTable(headers []string, rows [][]string, opts ...tableOption) string {
+	var (
+		r = &strings.Builder{}
+		t = tablewriter.NewWriter(r)
+	)
+
+	t.SetBorder(false)
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if len(headers) != 0 {
+		t.SetHeader(headers)
+	}
+	t.AppendBulk(rows)
+	t.Render()
+
+	return r.String()
+}
@@ -0,0 +1,57 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTable(t *testing.T) {
+	assert.Equal(t,
+		`  NAME  | VALUE  
+--------+--------
+  alice |     1  
+  bob   |     2  
+`,
+		cli.RenderTable([]string{"Name", "Value"}, [][]string{{"alice", "1"}, {"bob", "2"}}),
+		"tables should have no border by default",
+	)
+}
+
+func TestRenderTableWithOptions(t *testing.T) {
+	assert.Equal(t,
+		`+-----+------+
+| key | long |
++-----+------+
+`,
+		cli.RenderTable(nil, [][]string{{"key", "long"}},
+			withTableBorder(true),
+			withTableAlignment(tablewriter.ALIGN_LEFT),
+		),
+		"tables without headers should not render an empty header",
+	)
+
+	assert.Equal(t,
+		"  a  b  \n",
+		cli.RenderTable(nil, [][]string{{"a", "b"}}, withTableColumnSeparator("")),
+	)
+}
@@ -48,30 +48,36 @@ var (
 Prints out the installed version of the Lacework CLI and checks for newer
 versions available for update.
 
-Set the environment variable 'LW_UPDATES_DISABLE=1' to avoid checking for updates.`,
+To avoid checking for updates, turn off the check with the command
+'lacework configure updates off', or set the environment variable
+'LW_UPDATES_DISABLE=1'.`,
 		Args: cobra.NoArgs,
 		Run: func(_ *cobra.Command, _ []string) {
-			if cli.JSONOutput() {
+			if cli.StructuredOutput() {
 				errcheckEXIT(
-					cli.OutputJSONString(
-						fmt.Sprintf(
-							`{"version":"v%s","git_sha":"%s","build_time":"%s"}`,
-							Version, GitSHA, BuildTime,
-						),
-					),
+					cli.OutputStructured(struct {
+						Version   string `json:"version"`
+						GitSHA    string `json:"git_sha"`
+						BuildTime string `json:"build_time"`
+					}{fmt.Sprintf("v%s", Version), GitSHA, BuildTime}),
 				)
 				return
 			}
 			cli.OutputHuman("lacework v%s (sha:%s) (time:%s)\n", Version, GitSHA, BuildTime)
 
+			if !cli.UpdatesEnabled() {
+				cli.Log.Debugw("check for updates turned off", "setting", "updates")
+				return
+			}
+
 			// check the latest version of the cli
 			cli.StartProgress(" Checking available updates...")
 			sdk, err := lwupdater.Check("go-sdk", fmt.Sprintf("v%s", Version))
 			cli.StopProgress()
 			if err != nil {
-				exitwithCode(errors.Wrap(err, "unable to check for updates"), 4)
+				exitwith(errors.Wrap(err, "unable to check for updates"))
 			}
-			if sdk.Outdated {
+			if sdk.Outdated && !cli.Quiet() {
 				cli.OutputHuman(fmt.Sprintf(
 					"\nA newer version of the Lacework CLI is available! The latest version is %s,\n"+
 						"to update execute the following command:\n%s\n",
@@ -84,3 +90,8 @@ Set the environment variable 'LW_UPDATES_DISABLE=1' to avoid checking for update
 func init() {
 	rootCmd.AddCommand(versionCmd)
 }
+
+// userAgent returns the User-Agent that the cli sends to every API request
+func userAgent() string {
+	return fmt.Sprintf("lacework-cli/%s", Version)
+}
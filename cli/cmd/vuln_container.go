@@ -24,7 +24,6 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
@@ -125,8 +124,8 @@ filter on containers with vulnerabilities that have fixes available.`,
 				})
 			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(assessments)
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(assessments)
 			}
 
 			cli.OutputHuman(vulAssessmentsToTableReport(assessments))
@@ -264,12 +263,14 @@ func requestOnDemandContainerVulnerabilityScan(args []string) error {
 		return pollScanStatus(scan.Data.RequestID)
 	}
 
-	if cli.JSONOutput() {
-		return cli.OutputJSON(scan.Data)
+	if cli.StructuredOutput() {
+		return cli.OutputStructured(scan.Data)
 	}
 
-	cli.OutputHuman("To track the progress of the scan, use the command:\n")
-	cli.OutputHuman("  $ lacework vulnerability container scan-status %s\n", scan.Data.RequestID)
+	if !cli.Quiet() {
+		cli.OutputHuman("To track the progress of the scan, use the command:\n")
+		cli.OutputHuman("  $ lacework vulnerability container scan-status %s\n", scan.Data.RequestID)
+	}
 	return nil
 }
 
@@ -287,8 +288,8 @@ func checkOnDemandContainerVulnerabilityStatus(reqID string) error {
 		return err
 	}
 
-	if cli.JSONOutput() {
-		return cli.OutputJSON(results)
+	if cli.StructuredOutput() {
+		return cli.OutputStructured(results)
 	}
 
 	// if the scan is still running, display a nice message
@@ -297,7 +298,9 @@ func checkOnDemandContainerVulnerabilityStatus(reqID string) error {
 			"The vulnerability scan is still running. (request_id: %s)\n\n",
 			reqID,
 		)
-		cli.OutputHuman("Use '--poll' to poll until the vulnerability scan completes.\n")
+		if !cli.Quiet() {
+			cli.OutputHuman("Use '--poll' to poll until the vulnerability scan completes.\n")
+		}
 		return nil
 	}
 
@@ -328,8 +331,8 @@ func showContainerAssessmentsWithSha256(sha string) error {
 	status := assessment.CheckStatus()
 	switch status {
 	case "Success":
-		if cli.JSONOutput() {
-			return cli.OutputJSON(assessment.Data)
+		if cli.StructuredOutput() {
+			return cli.OutputStructured(assessment.Data)
 		}
 
 		cli.OutputHuman(buildVulnerabilityReport(&assessment.Data))
@@ -416,9 +419,11 @@ func buildVulnerabilityReport(assessment *api.VulnContainerAssessment) string {
 		} else {
 			mainReport.WriteString(buildVulnerabilityReportDetails(assessment))
 			mainReport.WriteString("\n")
-			mainReport.WriteString("Try adding '--packages' to show a list of packages with CVE count.\n")
+			if !cli.Quiet() {
+				mainReport.WriteString("Try adding '--packages' to show a list of packages with CVE count.\n")
+			}
 		}
-	} else {
+	} else if !cli.Quiet() {
 		mainReport.WriteString(
 			"Try adding '--details' to increase details shown about the vulnerability assessment.\n",
 		)
@@ -523,7 +528,7 @@ func vulContainerImagePackagesToTable(image *api.VulnContainerImage) [][]string
 
 	// order by severity
 	sort.Slice(out, func(i, j int) bool {
-		return severityOrder(out[i][1]) < severityOrder(out[j][1])
+		return api.ParseSeverity(out[i][1]).Order() < api.ParseSeverity(out[j][1]).Order()
 	})
 
 	return out
@@ -557,7 +562,7 @@ func vulContainerImageLayersToTable(image *api.VulnContainerImage) [][]string {
 	}
 
 	sort.Slice(out, func(i, j int) bool {
-		return severityOrder(out[i][1]) < severityOrder(out[j][1])
+		return api.ParseSeverity(out[i][1]).Order() < api.ParseSeverity(out[j][1]).Order()
 	})
 
 	return out
@@ -623,6 +628,10 @@ func vulAssessmentsToTableReport(assessments []api.VulnContainerAssessmentSummar
 	t.AppendBulk(rows)
 	t.Render()
 
+	if cli.Quiet() {
+		return assessmentsTable.String()
+	}
+
 	if !vulCmdState.Active {
 		assessmentsTable.WriteString(
 			"\nTry adding '--active' to only show assessments of containers actively running with vulnerabilities.\n",
@@ -690,7 +699,7 @@ func vulAssessmentsToTable(assessments []api.VulnContainerAssessmentSummary) [][
 		out = append(out, []string{
 			assessment.ImageRegistry,
 			assessment.ImageRepo,
-			assessment.StartTime.UTC().Format(time.RFC3339),
+			cli.FormatTime(assessment.StartTime.ToTime()),
 			assessment.ImageScanStatus,
 			assessment.NdvContainers,
 			assessmentSummary,
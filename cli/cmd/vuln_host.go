@@ -22,9 +22,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/olekukonko/tablewriter"
@@ -32,15 +34,41 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/internal/array"
 )
 
 var (
+	// errHostVulnDisabled is returned when the API reports that the
+	// host vulnerability feature is not enabled on the account
+	errHostVulnDisabled = errors.New(
+		"host vulnerability monitoring appears disabled on this account, contact your Lacework administrator to enable it",
+	)
+
+	// hostVulnNoAgentsMessage is displayed when the API reports that
+	// there are no agents deployed on any host of the account
+	hostVulnNoAgentsMessage = "No agents found in your environment, install the Lacework agent on your hosts to assess their vulnerabilities.\n"
+
 	// the package manifest file
 	pkgManifestFile string
 
 	// automatically generate the package manifest from the local host
 	pkgManifestLocal bool
 
+	// assess the package manifest against a local vulnerability database
+	pkgManifestOffline bool
+
+	// path to the local vulnerability database used by offline scans
+	pkgManifestDB string
+
+	// directory of package manifests to scan in bulk
+	pkgManifestDir string
+
+	// directory where the results of a bulk scan are stored
+	pkgManifestRunDir string
+
+	// resume an interrupted bulk scan from the results of the run directory
+	pkgManifestResume bool
+
 	vulHostGenPkgManifestCmd = &cobra.Command{
 		Use:   "generate-pkg-manifest",
 		Args:  cobra.NoArgs,
@@ -86,12 +114,55 @@ To generate a package-manifest from the local host and scan it automatically:
 
     $ lacework vulnerability host scan-pkg-manifest --local
 
+On air-gapped hosts, use the flags --offline and --db to assess the packages
+against a local vulnerability database instead of calling the Lacework API:
+
+    $ lacework vulnerability host scan-pkg-manifest --local --offline --db vulns.json
+
+The database is a JSON file with a list of vulnerabilities, a package is
+vulnerable when its installed version is lower than the fixed version:
+
+    {
+      "vulnerabilities": [
+        {
+          "id": "CVE-2020-1967",
+          "namespace": "ubuntu:18.04",
+          "package": "openssl",
+          "severity": "High",
+          "score": 7.5,
+          "fixed_version": "1.1.1-1ubuntu2.1~18.04.6"
+        }
+      ]
+    }
+
+To scan every package manifest (*.json files) of a directory, use the flag --dir,
+the result of each manifest is stored in a run directory (--run-dir) so that an
+interrupted scan can be resumed with the flag --resume, only the manifests that
+failed or were not yet scanned are sent to the Lacework API:
+
+    $ lacework vulnerability host scan-pkg-manifest --dir manifests/ --resume
+
 (*) NOTE:
  - Only packages managed by a package manager for supported OS's are reported.
+ - The assessment is returned synchronously, there is no need to poll for results.
  - Calls to this operation are rate limited to 10 calls per hour, per access key.
  - This operation is limited to 1k of packages per payload. If you require a payload
    larger than 1k, you must make multiple requests.`,
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOfflineScanFlags(); err != nil {
+				return err
+			}
+
+			if err := validateScanDirFlags(args); err != nil {
+				return err
+			}
+
+			if pkgManifestDir != "" {
+				// the report of the scan already explains which manifests failed
+				cmd.SilenceUsage = true
+				return scanPkgManifestDir(pkgManifestDir, pkgManifestRunDir, pkgManifestResume)
+			}
+
 			var pkgManifest = ""
 			if len(args) != 0 && args[0] != "" {
 				pkgManifest = args[0]
@@ -124,13 +195,13 @@ To generate a package-manifest from the local host and scan it automatically:
 				}
 			}
 
-			response, err := cli.LwApi.Vulnerabilities.Host.Scan(pkgManifest)
+			response, err := scanPkgManifest(pkgManifest)
 			if err != nil {
-				return errors.Wrap(err, "unable to request an on-demand host vulnerability scan")
+				return err
 			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response)
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(response)
 			}
 
 			if len(response.Vulns) == 0 {
@@ -153,32 +224,123 @@ To generate a package-manifest from the local host and scan it automatically:
 Filter results to only show vulnerabilities actively running in your environment
 with fixes:
 
-    $ lacework vulnerability host list-cves --active --fixable`,
+    $ lacework vulnerability host list-cves --active --fixable
+
+Use the flag --status to only show vulnerabilities with specific statuses, for
+example, to ignore vulnerabilities that have already been fixed:
+
+    $ lacework vulnerability host list-cves --status Active,Reopened
+
+Use the flag --namespace to only show vulnerabilities of packages from specific
+namespaces (OS versions), the flag can be provided multiple times:
+
+    $ lacework vulnerability host list-cves --namespace ubuntu:18.04
+
+Use the flag --min-score to only show vulnerabilities with a CVSS score at or
+above a threshold, vulnerabilities without a score are excluded unless the
+flag --include-unscored is provided:
+
+    $ lacework vulnerability host list-cves --min-score 7.0
+
+Use the flag --stats to show a summary with the number of vulnerabilities per
+severity at the end of the listing, or as a 'stats' object in JSON format:
+
+    $ lacework vulnerability host list-cves --active --stats
+
+Use the flag --cve to only show specific vulnerabilities across all your hosts,
+the flag can be provided multiple times:
+
+    $ lacework vulnerability host list-cves --cve CVE-2021-3156 --cve CVE-2021-4034
+
+Use the flag --columns to select and order the columns of the table and CSV
+outputs, for example, to display only the CVE ids and their fix versions:
+
+    $ lacework vulnerability host list-cves --fixable --columns cve,fix`,
 		RunE: func(_ *cobra.Command, args []string) error {
+			if err := validateSortByFlag(); err != nil {
+				return err
+			}
+
+			if err := validateMinScoreFlags(); err != nil {
+				return err
+			}
+
+			if err := validateColumnsFlag(hostVulnCVEsTableHeaders); err != nil {
+				return err
+			}
+
 			response, err := cli.LwApi.Vulnerabilities.Host.ListCves()
 			if err != nil {
+				if api.IsFeatureDisabled(err) {
+					return errHostVulnDisabled
+				}
 				return errors.Wrap(err, "unable to get CVEs from hosts")
 			}
+			if response.Status() == api.HostVulnStatusDisabled {
+				return errHostVulnDisabled
+			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response.CVEs)
+			cves, filteredOut := filterHostVulnCVEsByNamespaces(response.CVEs)
+			cves = filterHostVulnCVEsByIDs(cves, vulCmdState.CVEs)
+			cves, _ = filterHostVulnCVEsByStatuses(cves)
+			response.CVEs, _ = filterHostVulnCVEsByScore(cves)
+
+			if cli.CSVOutput() {
+				if vulCmdState.Packages {
+					return cli.OutputCSV(hostVulnPackagesTableHeaders(true),
+						hostVulnPackagesTable(response.CVEs, true))
+				}
+				return cli.OutputCSV(selectHostVulnCVEsTableColumns(
+					hostVulnCVEsTableHeaders, hostVulnCVEsTable(response.CVEs),
+				))
+			}
+
+			if cli.StructuredOutput() {
+				if vulCmdState.Stats {
+					return cli.OutputStructured(struct {
+						CVEs  []api.HostVulnCVE     `json:"cves"`
+						Stats hostVulnSeverityStats `json:"stats"`
+					}{response.CVEs, buildHostVulnSeverityStats(response.CVEs)})
+				}
+				return cli.OutputStructured(response.CVEs)
+			}
+
+			if len(response.CVEs) == 0 && (len(vulCmdState.Namespaces) != 0 ||
+				len(vulCmdState.VulnStatuses) != 0 || vulCmdState.MinScore != 0 ||
+				len(vulCmdState.CVEs) != 0) {
+				cli.OutputHuman(buildHostVulnCVEsToTableError())
+				return nil
 			}
 
 			if len(response.CVEs) == 0 {
-				// @afiune add a helpful message, possible things are:
-				// 1) host vuln feature is not enabled on the account
-				// 2) user doesn't have agents deployed
-				// 3) there are actually NO vulnerabilities on any host
+				if response.Status() == api.HostVulnStatusNoAgents {
+					cli.OutputHuman(hostVulnNoAgentsMessage)
+					return nil
+				}
 				cli.OutputHuman("There are no vulnerabilities on any host in your environment.\n")
 				return nil
 			}
 
+			cli.StartPager()
+			defer cli.StopPager()
+
 			if vulCmdState.Packages {
 				cli.OutputHuman(hostVulnCVEsPackagesSummary(response.CVEs, true))
 			} else {
 				cli.OutputHuman(hostVulnCVEsToTable(response.CVEs))
 			}
 
+			if vulCmdState.Stats {
+				cli.OutputHuman("\n%s\n", buildHostVulnSeverityStats(response.CVEs))
+			}
+
+			if filteredOut != 0 && !cli.Quiet() {
+				cli.OutputHuman(
+					"\n%d vulnerabilities of packages from other namespaces were filtered out.\n",
+					filteredOut,
+				)
+			}
+
 			return nil
 		},
 	}
@@ -191,27 +353,106 @@ with fixes:
 
 To list the CVEs found in the hosts of your environment run:
 
-    $ lacework vulnerability host list-cves`,
+    $ lacework vulnerability host list-cves
+
+To only show hosts with a specific machine status (Online, Offline), use the
+flag --status, or the shortcuts --online and --offline:
+
+    $ lacework vulnerability host list-hosts my_cve_id --status Online
+
+To only show hosts with specific machine tags, use the flag --filter with the
+format key=value, multiple filters must all match (case-insensitive):
+
+    $ lacework vulnerability host list-hosts my_cve_id --filter os=Ubuntu --filter provider=AWS
+
+Valid filter keys: ` + strings.Join(hostVulnTagFilterKeys(), ", ") + `
+
+To only show hosts with specific architectures, use the flag --arch, it can
+be repeated to match any of the provided architectures (case-insensitive):
+
+    $ lacework vulnerability host list-hosts my_cve_id --arch amd64 --arch arm64`,
+		ValidArgsFunction: completeHostCVEIDs,
 		RunE: func(_ *cobra.Command, args []string) error {
+			if err := validateHostVulnStatusFlags(); err != nil {
+				return err
+			}
+
+			filters, err := parseHostTagFilters(vulCmdState.HostFilters)
+			if err != nil {
+				return err
+			}
+
 			response, err := cli.LwApi.Vulnerabilities.Host.ListHostsWithCVE(args[0])
 			if err != nil {
+				if api.IsFeatureDisabled(err) {
+					return errHostVulnDisabled
+				}
 				return errors.Wrap(err, "unable to get hosts with CVE "+args[0])
 			}
+			if response.Status() == api.HostVulnStatusDisabled {
+				return errHostVulnDisabled
+			}
+
+			hosts, filteredOut := filterHostVulnHostsByTags(response.Hosts, filters)
+			hosts, archFilteredOut := filterHostVulnHostsByArch(hosts, vulCmdState.Archs)
+			hosts, statusFilteredOut := filterHostVulnHostsByStatus(hosts, vulCmdState.MachineStatuses)
+			hosts, onlineFilteredOut := filterHostVulnHostsByStatus(hosts, hostVulnOnlineStatuses())
+			response.Hosts = hosts
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response.Hosts)
+			output := buildHostVulnListHostsOutput(response.Hosts)
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(output)
+			}
+
+			if len(response.Hosts) == 0 &&
+				(len(filters) != 0 || len(vulCmdState.Archs) != 0 ||
+					len(vulCmdState.MachineStatuses) != 0 || len(hostVulnOnlineStatuses()) != 0) {
+				appliedFilters := append([]string{}, vulCmdState.HostFilters...)
+				if len(vulCmdState.Archs) != 0 {
+					appliedFilters = append(appliedFilters, "arch="+strings.Join(vulCmdState.Archs, ","))
+				}
+				if len(vulCmdState.MachineStatuses) != 0 {
+					appliedFilters = append(appliedFilters,
+						"status="+strings.Join(vulCmdState.MachineStatuses, ","))
+				}
+				if statuses := hostVulnOnlineStatuses(); len(statuses) != 0 {
+					appliedFilters = append(appliedFilters, "status="+strings.Join(statuses, ","))
+				}
+				cli.OutputHuman(
+					"There are no hosts in your environment with the CVE id '%s' matching the filters '%s'\n",
+					args[0], strings.Join(appliedFilters, ", "),
+				)
+				return nil
 			}
 
 			if len(response.Hosts) == 0 {
-				// @afiune add a helpful message, possible things are:
-				// 1) host vuln feature is not enabled on the account
-				// 2) user doesn't have agents deployed
-				// 3) there are actually NO vulnerabilities on any host
+				if response.Status() == api.HostVulnStatusNoAgents {
+					cli.OutputHuman(hostVulnNoAgentsMessage)
+					return nil
+				}
 				cli.OutputHuman("There are no hosts in your environment with the CVE id '%s'\n", args[0])
 				return nil
 			}
 
+			cli.OutputHuman(output.Summary.String())
 			cli.OutputHuman(hostVulnHostsToTable(response.Hosts))
+
+			if filteredOut != 0 && !cli.Quiet() {
+				cli.OutputHuman("\n%d host(s) hidden by the '--filter' flag.\n", filteredOut)
+			}
+			if archFilteredOut != 0 && !cli.Quiet() {
+				cli.OutputHuman("\n%d host(s) hidden by the '--arch' flag.\n", archFilteredOut)
+			}
+			if statusFilteredOut != 0 && !cli.Quiet() {
+				cli.OutputHuman("\n%d host(s) hidden by the '--status' flag.\n", statusFilteredOut)
+			}
+			if onlineFilteredOut != 0 && !cli.Quiet() {
+				flag := "--online"
+				if vulCmdState.Offline {
+					flag = "--offline"
+				}
+				cli.OutputHuman("\n%d host(s) hidden by the '%s' flag.\n", onlineFilteredOut, flag)
+			}
 			return nil
 		},
 	}
@@ -229,18 +470,126 @@ To find the machine id from hosts in your environment, use the command:
 
 Grab a CVE id and feed it to the command:
 
-    $ lacework vulnerability host list-hosts my_cve_id`,
+    $ lacework vulnerability host list-hosts my_cve_id
+
+To export the assessment as a Software Bill of Materials (SBOM), use the
+flag --sbom with one of the supported formats (cyclonedx, spdx):
+
+    $ lacework vulnerability host show-assessment my_machine_id --sbom cyclonedx
+
+To display one row per package with the list of CVEs affecting it, instead
+of one row per CVE, use the flag --by-package:
+
+    $ lacework vulnerability host show-assessment my_machine_id --by-package
+
+To write the assessment as a JUnit XML report for CI dashboards, use the flag
+--junit, every package and CVE is a test case that fails when its severity is
+equal or higher than the threshold (default high):
+
+    $ lacework vulnerability host show-assessment my_machine_id \
+        --junit report.xml --junit-threshold medium
+
+To write the number of vulnerabilities per severity as Prometheus metrics for
+the textfile collector of the node-exporter, use the flag --prometheus:
+
+    $ lacework vulnerability host show-assessment my_machine_id \
+        --prometheus /var/lib/node_exporter/textfile/lacework.prom
+
+To avoid fetching the same assessment on every run, like during a remediation
+session, use the flag --cache to store the assessment on disk for the duration
+of --cache-ttl (default 15m), use the flag --no-cache to fetch it again:
+
+    $ lacework vulnerability host show-assessment my_machine_id --cache
+
+Use the flag --columns to display the vulnerabilities with the selected columns
+in the provided order, for example, for a patch ticket:
+
+    $ lacework vulnerability host show-assessment my_machine_id \
+        --fixable --columns cve,package,fix
+
+For reporting, use the flag --fixable-count to print only the number of CVEs
+that have a fix available and the total number of CVEs of the host:
+
+    $ lacework vulnerability host show-assessment my_machine_id --fixable-count --json`,
 		RunE: func(_ *cobra.Command, args []string) error {
-			response, err := cli.LwApi.Vulnerabilities.Host.GetHostAssessment(args[0])
+			if err := validateSortByFlag(); err != nil {
+				return err
+			}
+
+			if vulCmdState.Packages && vulCmdState.ByPackage {
+				return errors.New("cannot combine --packages with --by-package")
+			}
+
+			if vulCmdState.ByPackage && len(vulCmdState.Columns) != 0 {
+				return errors.New("cannot combine --by-package with --columns")
+			}
+
+			if vulCmdState.FixableCount && vulCmdState.Sbom != "" {
+				return errors.New("cannot combine --fixable-count with --sbom")
+			}
+
+			if err := validateColumnsFlag(hostVulnHostAssessmentCVEsTableHeaders); err != nil {
+				return err
+			}
+
+			if !array.ContainsStr(validJUnitThresholds, vulCmdState.JUnitThreshold) {
+				return errors.Errorf("the JUnit threshold %s is not valid, use one of %s",
+					vulCmdState.JUnitThreshold, strings.Join(validJUnitThresholds, ", "),
+				)
+			}
+
+			if vulCmdState.Sbom != "" && !array.ContainsStr(validSbomFormats, vulCmdState.Sbom) {
+				return errors.Errorf("the SBOM format %s is not valid, use one of %s",
+					vulCmdState.Sbom, strings.Join(validSbomFormats, ", "),
+				)
+			}
+
+			if useHostAssessmentCache() && vulCmdState.CacheTTL <= 0 {
+				return errors.New("the flag --cache-ttl must be a positive duration")
+			}
+
+			assessment, cachedAt, err := getHostAssessment(args[0])
 			if err != nil {
+				if api.IsFeatureDisabled(err) {
+					return errHostVulnDisabled
+				}
 				return errors.Wrap(err, "unable to get host assessment with id "+args[0])
 			}
+			assessment.CVEs, _ = filterHostVulnCVEsByStatuses(assessment.CVEs)
+
+			if vulCmdState.JUnit != "" {
+				if err := writeHostAssessmentJUnit(vulCmdState.JUnit, assessment); err != nil {
+					return err
+				}
+			}
+
+			if vulCmdState.Prometheus != "" {
+				if err := writeHostAssessmentPrometheus(vulCmdState.Prometheus, assessment); err != nil {
+					return err
+				}
+			}
+
+			if vulCmdState.FixableCount {
+				return outputHostVulnFixableCount(buildHostVulnFixableCount(assessment.CVEs))
+			}
 
-			if cli.JSONOutput() {
-				return cli.OutputJSON(response.Assessment)
+			if vulCmdState.Sbom != "" {
+				sbom, err := buildHostAssessmentSBOM(vulCmdState.Sbom, assessment)
+				if err != nil {
+					return err
+				}
+				return writeSBOM(os.Stdout, sbom)
 			}
 
-			cli.OutputHuman(hostVulnHostDetailsToTable(response.Assessment))
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(assessment)
+			}
+
+			cli.OutputHuman(hostVulnHostDetailsToTable(assessment))
+			if !cachedAt.IsZero() && !cli.Quiet() {
+				cli.OutputHuman("\nAssessment cached %s ago, use '--no-cache' to fetch it again.\n",
+					time.Since(cachedAt).Round(time.Second))
+			}
 			return nil
 		},
 	}
@@ -288,6 +637,79 @@ func init() {
 		vulHostListCvesCmd.Flags(),
 	)
 
+	setSortByFlag(
+		vulHostShowAssessmentCmd.Flags(),
+		vulHostListCvesCmd.Flags(),
+	)
+
+	setVulnStatusFlag(
+		vulHostShowAssessmentCmd.Flags(),
+		vulHostListCvesCmd.Flags(),
+	)
+	for _, cmd := range []*cobra.Command{vulHostShowAssessmentCmd, vulHostListCvesCmd} {
+		errcheckWARN(cmd.RegisterFlagCompletionFunc("sort-by",
+			completeWithValues(hostVulnSortByFields),
+		))
+	}
+
+	// add by-package flag to host show-assessment command
+	vulHostShowAssessmentCmd.Flags().BoolVar(&vulCmdState.ByPackage,
+		"by-package", false, "show one row per package with the CVEs affecting it",
+	)
+
+	// add fixable-count flag to host show-assessment command
+	vulHostShowAssessmentCmd.Flags().BoolVar(&vulCmdState.FixableCount,
+		"fixable-count", false,
+		"print only the number of fixable CVEs and the total number of CVEs",
+	)
+
+	// add junit flags to host show-assessment command
+	vulHostShowAssessmentCmd.Flags().StringVar(&vulCmdState.JUnit,
+		"junit", "", "write the assessment as a JUnit XML report to the specified file",
+	)
+	vulHostShowAssessmentCmd.Flags().StringVar(&vulCmdState.JUnitThreshold,
+		"junit-threshold", "high",
+		fmt.Sprintf("severity threshold to fail the test cases of the JUnit report (%s)",
+			strings.Join(validJUnitThresholds, ", ")),
+	)
+	errcheckWARN(vulHostShowAssessmentCmd.RegisterFlagCompletionFunc("junit-threshold",
+		completeWithValues(validJUnitThresholds),
+	))
+
+	// add prometheus flag to host show-assessment command
+	vulHostShowAssessmentCmd.Flags().StringVar(&vulCmdState.Prometheus,
+		"prometheus", "",
+		"write the vulnerability counts as Prometheus metrics to the specified file",
+	)
+
+	// add namespace flag to host list-cves command
+	vulHostListCvesCmd.Flags().StringSliceVar(&vulCmdState.Namespaces,
+		"namespace", []string{},
+		"only show vulnerabilities of packages from the specified namespaces (e.g. ubuntu:18.04)",
+	)
+
+	// add cve flag to host list-cves command
+	vulHostListCvesCmd.Flags().StringSliceVar(&vulCmdState.CVEs,
+		"cve", []string{},
+		"only show the vulnerabilities with the specified CVE ids (can be repeated)",
+	)
+
+	// add min-score and include-unscored flags to host list-cves command
+	vulHostListCvesCmd.Flags().Float64Var(&vulCmdState.MinScore,
+		"min-score", 0,
+		"only show vulnerabilities with a CVSS score at or above the threshold (0-10)",
+	)
+	vulHostListCvesCmd.Flags().BoolVar(&vulCmdState.IncludeUnscored,
+		"include-unscored", false,
+		"keep the vulnerabilities without a CVSS score when using --min-score",
+	)
+
+	// add stats flag to host list-cves command
+	vulHostListCvesCmd.Flags().BoolVar(&vulCmdState.Stats,
+		"stats", false,
+		"show a summary with the number of vulnerabilities per severity",
+	)
+
 	// add online flag to host list-hosts command
 	vulHostListHostsCmd.Flags().BoolVar(&vulCmdState.Online,
 		"online", false, "only show hosts that are online",
@@ -296,6 +718,72 @@ func init() {
 	vulHostListHostsCmd.Flags().BoolVar(&vulCmdState.Offline,
 		"offline", false, "only show hosts that are offline",
 	)
+	// add status flag to host list-hosts command
+	vulHostListHostsCmd.Flags().StringSliceVar(&vulCmdState.MachineStatuses,
+		"status", []string{},
+		"only show hosts with the specified machine status (Online, Offline)",
+	)
+	errcheckWARN(vulHostListHostsCmd.RegisterFlagCompletionFunc("status",
+		completeWithValues([]string{"Online", "Offline"}),
+	))
+	// add filter flag to host list-hosts command
+	vulHostListHostsCmd.Flags().StringArrayVar(&vulCmdState.HostFilters,
+		"filter", []string{},
+		"only show hosts with a machine tag matching the key=value filter (can be repeated)",
+	)
+	// add arch flag to host list-hosts command
+	vulHostListHostsCmd.Flags().StringSliceVar(&vulCmdState.Archs,
+		"arch", []string{},
+		"only show hosts with the specified architecture, like amd64 (can be repeated)",
+	)
+
+	// add columns flag to host list-cves and show-assessment commands
+	for cmd, headers := range map[*cobra.Command][]string{
+		vulHostListCvesCmd:       hostVulnCVEsTableHeaders,
+		vulHostShowAssessmentCmd: hostVulnHostAssessmentCVEsTableHeaders,
+	} {
+		cmd.Flags().StringSliceVar(&vulCmdState.Columns,
+			"columns", []string{},
+			fmt.Sprintf(
+				"comma separated list of columns to display (%s)",
+				strings.Join(hostVulnCVEsTableColumnNames(headers), ", "),
+			),
+		)
+		errcheckWARN(cmd.RegisterFlagCompletionFunc("columns",
+			completeWithValues(hostVulnCVEsTableColumnNames(headers)),
+		))
+	}
+
+	// add no-dedupe flag to host list-cves and show-assessment commands
+	vulHostListCvesCmd.Flags().BoolVar(&vulCmdState.NoDedupe,
+		"no-dedupe", false,
+		"do not collapse identical CVE/package/version rows",
+	)
+	vulHostShowAssessmentCmd.Flags().BoolVar(&vulCmdState.NoDedupe,
+		"no-dedupe", false,
+		"do not collapse identical CVE/package/version rows",
+	)
+
+	// add cache flags to host show-assessment command
+	vulHostShowAssessmentCmd.Flags().BoolVar(&vulCmdState.Cache,
+		"cache", false,
+		"cache the assessment on disk and reuse it while it is fresh",
+	)
+	vulHostShowAssessmentCmd.Flags().DurationVar(&vulCmdState.CacheTTL,
+		"cache-ttl", defaultHostAssessmentCacheTTL,
+		"time that a cached assessment is fresh, used with --cache",
+	)
+	vulHostShowAssessmentCmd.Flags().BoolVar(&vulCmdState.NoCache,
+		"no-cache", false,
+		"do not use the cache of assessments, overrides --cache",
+	)
+
+	// add sbom flag to host show-assessment command
+	vulHostShowAssessmentCmd.Flags().StringVar(&vulCmdState.Sbom,
+		"sbom", "",
+		fmt.Sprintf("export the assessment as a SBOM document (%s)",
+			strings.Join(validSbomFormats, ", ")),
+	)
 
 	// the package manifest file
 	vulHostScanPkgManifestCmd.Flags().StringVarP(&pkgManifestFile,
@@ -308,41 +796,53 @@ func init() {
 		"local", "l", false,
 		"automatically generate the package manifest from the local host",
 	)
+
+	// offline scans against a local vulnerability database
+	vulHostScanPkgManifestCmd.Flags().BoolVar(&pkgManifestOffline,
+		"offline", false,
+		"assess the packages against a local vulnerability database (requires --db)",
+	)
+	vulHostScanPkgManifestCmd.Flags().StringVar(&pkgManifestDB,
+		"db", "",
+		"path to the local vulnerability database used with --offline",
+	)
+
+	// bulk scans of a directory of package manifests
+	vulHostScanPkgManifestCmd.Flags().StringVar(&pkgManifestDir,
+		"dir", "",
+		"scan every package manifest (*.json files) of the specified directory",
+	)
+	vulHostScanPkgManifestCmd.Flags().StringVar(&pkgManifestRunDir,
+		"run-dir", "",
+		"directory where the results of a --dir scan are stored (default <dir>/.lacework-scan)",
+	)
+	vulHostScanPkgManifestCmd.Flags().BoolVar(&pkgManifestResume,
+		"resume", false,
+		"skip the manifests of a --dir scan that already have results in the run directory",
+	)
 }
 
 func hostVulnHostsToTable(hosts []api.HostVulnDetail) string {
 	var (
 		tableBuilder = &strings.Builder{}
-		t            = tablewriter.NewWriter(tableBuilder)
 		rows         = hostVulnHostsTable(hosts)
 	)
 
-	// if the user wants to show only online or
-	// offline hosts, show a friendly message
-	if len(rows) == 0 {
-		if vulCmdState.Online {
-			return "There are no online hosts.\n"
-		}
-		if vulCmdState.Offline {
-			return "There are no offline hosts.\n"
-		}
-	}
-
-	t.SetHeader([]string{
-		"Machine ID",
-		"Hostname",
-		"External IP",
-		"Internal IP",
-		"Os/Arch",
-		"Provider",
-		"Instance ID",
-		"Vulnerabilities",
-		"Status",
-	})
-	t.SetBorder(false)
-	t.SetAlignment(tablewriter.ALIGN_LEFT)
-	t.AppendBulk(rows)
-	t.Render()
+	tableBuilder.WriteString(cli.RenderTable(
+		[]string{
+			"Machine ID",
+			"Hostname",
+			"External IP",
+			"Internal IP",
+			"Os/Arch",
+			"Provider",
+			"Instance ID",
+			"Vulnerabilities",
+			"Status",
+		},
+		rows,
+		withTableAlignment(tablewriter.ALIGN_LEFT),
+	))
 
 	return tableBuilder.String()
 }
@@ -350,15 +850,6 @@ func hostVulnHostsToTable(hosts []api.HostVulnDetail) string {
 func hostVulnHostsTable(hosts []api.HostVulnDetail) [][]string {
 	out := [][]string{}
 	for _, host := range hosts {
-
-		// filter by machine status: Online / Offline
-		if vulCmdState.Online && host.Details.MachineStatus != "Online" {
-			continue
-		}
-		if vulCmdState.Offline && host.Details.MachineStatus != "Offline" {
-			continue
-		}
-
 		hostVulnSummary, _ := hostVulnSummaryFromHostDetail(&host.Summary)
 
 		out = append(out, []string{
@@ -374,7 +865,213 @@ func hostVulnHostsTable(hosts []api.HostVulnDetail) [][]string {
 		})
 	}
 
-	return out
+	return out
+}
+
+// hostVulnListHostsOutput is the structured output of list-hosts
+type hostVulnListHostsOutput struct {
+	Summary hostVulnHostsSummary `json:"summary"`
+	Hosts   []api.HostVulnDetail `json:"hosts"`
+}
+
+func buildHostVulnListHostsOutput(hosts []api.HostVulnDetail) hostVulnListHostsOutput {
+	return hostVulnListHostsOutput{buildHostVulnHostsSummary(hosts), hosts}
+}
+
+// hostVulnHostsSummary is a summary of the hosts affected by a CVE
+type hostVulnHostsSummary struct {
+	Total      int            `json:"total"`
+	ByOs       map[string]int `json:"by_os"`
+	ByProvider map[string]int `json:"by_provider"`
+}
+
+// String returns a one-line human-readable representation of the summary
+func (s hostVulnHostsSummary) String() string {
+	return fmt.Sprintf("%d host(s) affected (OS: %s) (Provider: %s)\n\n",
+		s.Total, countsMapToString(s.ByOs), countsMapToString(s.ByProvider),
+	)
+}
+
+func buildHostVulnHostsSummary(hosts []api.HostVulnDetail) hostVulnHostsSummary {
+	summary := hostVulnHostsSummary{
+		Total:      len(hosts),
+		ByOs:       map[string]int{},
+		ByProvider: map[string]int{},
+	}
+
+	for _, host := range hosts {
+		hostOs := host.Details.Tags.Os
+		if hostOs == "" {
+			hostOs = "unknown"
+		}
+		provider := host.Details.Tags.VmProvider
+		if provider == "" {
+			provider = "unknown"
+		}
+		summary.ByOs[hostOs]++
+		summary.ByProvider[provider]++
+	}
+
+	return summary
+}
+
+// countsMapToString converts a map of counts into a string ordered
+// by the highest count first, (e.g. "linux 3, windows 1")
+func countsMapToString(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] == counts[keys[j]] {
+			return keys[i] < keys[j]
+		}
+		return counts[keys[i]] > counts[keys[j]]
+	})
+
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = fmt.Sprintf("%s %d", k, counts[k])
+	}
+	return strings.Join(out, ", ")
+}
+
+// hostVulnTagFields are the machine tags that can be used with the flag
+// '--filter' of the command 'vulnerability host list-hosts'
+var hostVulnTagFields = map[string]func(api.HostVulnDetail) string{
+	"account":       func(h api.HostVulnDetail) string { return h.Details.Tags.Account },
+	"ami":           func(h api.HostVulnDetail) string { return h.Details.Tags.AmiID },
+	"arch":          func(h api.HostVulnDetail) string { return h.Details.Tags.Arch },
+	"external_ip":   func(h api.HostVulnDetail) string { return h.Details.Tags.ExternalIP },
+	"hostname":      func(h api.HostVulnDetail) string { return h.Details.Hostname },
+	"instance_id":   func(h api.HostVulnDetail) string { return h.Details.Tags.InstanceID },
+	"instance_type": func(h api.HostVulnDetail) string { return h.Details.Tags.VmInstanceType },
+	"internal_ip":   func(h api.HostVulnDetail) string { return h.Details.Tags.InternalIP },
+	"machine_id":    func(h api.HostVulnDetail) string { return h.Details.MachineID },
+	"os":            func(h api.HostVulnDetail) string { return h.Details.Tags.Os },
+	"provider":      func(h api.HostVulnDetail) string { return h.Details.Tags.VmProvider },
+	"subnet":        func(h api.HostVulnDetail) string { return h.Details.Tags.SubnetID },
+	"vpc":           func(h api.HostVulnDetail) string { return h.Details.Tags.VpcID },
+	"zone":          func(h api.HostVulnDetail) string { return h.Details.Tags.Zone },
+}
+
+func hostVulnTagFilterKeys() []string {
+	keys := make([]string, 0, len(hostVulnTagFields))
+	for key := range hostVulnTagFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type hostTagFilter struct {
+	Key   string
+	Value string
+}
+
+// parseHostTagFilters parses the key=value filters provided via '--filter'
+func parseHostTagFilters(filters []string) ([]hostTagFilter, error) {
+	parsed := make([]hostTagFilter, 0, len(filters))
+	for _, filter := range filters {
+		kv := strings.SplitN(filter, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, errors.Errorf("invalid filter '%s', use the format key=value", filter)
+		}
+
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		if _, ok := hostVulnTagFields[key]; !ok {
+			return nil, errors.Errorf("unknown filter key '%s', valid keys are: %s",
+				kv[0], strings.Join(hostVulnTagFilterKeys(), ", "))
+		}
+		parsed = append(parsed, hostTagFilter{Key: key, Value: strings.TrimSpace(kv[1])})
+	}
+	return parsed, nil
+}
+
+// filterHostVulnHostsByTags returns the hosts matching all the provided
+// filters (case-insensitive) and the number of hosts filtered out
+func filterHostVulnHostsByTags(hosts []api.HostVulnDetail, filters []hostTagFilter) ([]api.HostVulnDetail, int) {
+	if len(filters) == 0 {
+		return hosts, 0
+	}
+
+	out := []api.HostVulnDetail{}
+	for _, host := range hosts {
+		if hostMatchesTagFilters(host, filters) {
+			out = append(out, host)
+		}
+	}
+	return out, len(hosts) - len(out)
+}
+
+func hostMatchesTagFilters(host api.HostVulnDetail, filters []hostTagFilter) bool {
+	for _, filter := range filters {
+		if !strings.EqualFold(hostVulnTagFields[filter.Key](host), filter.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterHostVulnHostsByArch returns the hosts with any of the provided
+// architectures (case-insensitive) and the number of hosts filtered out
+func filterHostVulnHostsByArch(hosts []api.HostVulnDetail, archs []string) ([]api.HostVulnDetail, int) {
+	if len(archs) == 0 {
+		return hosts, 0
+	}
+
+	out := []api.HostVulnDetail{}
+	for _, host := range hosts {
+		for _, arch := range archs {
+			if strings.EqualFold(host.Details.Tags.Arch, arch) {
+				out = append(out, host)
+				break
+			}
+		}
+	}
+	return out, len(hosts) - len(out)
+}
+
+// filterHostVulnHostsByStatus returns the hosts with one of the provided machine
+// statuses (case-insensitive), and the number of hosts that were filtered out
+func filterHostVulnHostsByStatus(hosts []api.HostVulnDetail, statuses []string) ([]api.HostVulnDetail, int) {
+	if len(statuses) == 0 {
+		return hosts, 0
+	}
+
+	out := []api.HostVulnDetail{}
+	for _, host := range hosts {
+		for _, status := range statuses {
+			if strings.EqualFold(strings.TrimSpace(status), host.Details.MachineStatus) {
+				out = append(out, host)
+				break
+			}
+		}
+	}
+	return out, len(hosts) - len(out)
+}
+
+// validateHostVulnStatusFlags verifies that the flag --status is not combined
+// with the flags --online and --offline, since they filter the same status
+func validateHostVulnStatusFlags() error {
+	if len(vulCmdState.MachineStatuses) != 0 && (vulCmdState.Online || vulCmdState.Offline) {
+		return errors.New("cannot combine --status with --online/--offline")
+	}
+	return nil
+}
+
+// hostVulnOnlineStatuses returns the machine statuses selected by the flags
+// --online and --offline, the statuses reported by the API are Online and Offline
+func hostVulnOnlineStatuses() []string {
+	statuses := []string{}
+	if vulCmdState.Online {
+		statuses = append(statuses, "Online")
+	}
+	if vulCmdState.Offline {
+		statuses = append(statuses, "Offline")
+	}
+	return statuses
 }
 
 func hostVulnSummaryFromHostDetail(hostVulnSummary *api.HostVulnCveSummary) (string, bool) {
@@ -399,11 +1096,14 @@ func hostVulnSummaryFromHostDetail(hostVulnSummary *api.HostVulnCveSummary) (str
 }
 
 func hostVulnCVEsPackagesSummary(cves []api.HostVulnCVE, withHosts bool) string {
-	var (
-		tableBuilder = &strings.Builder{}
-		t            = tablewriter.NewWriter(tableBuilder)
+	return cli.RenderTable(
+		hostVulnPackagesTableHeaders(withHosts),
+		hostVulnPackagesTable(cves, withHosts),
+		withTableAlignment(tablewriter.ALIGN_LEFT),
 	)
+}
 
+func hostVulnPackagesTableHeaders(withHosts bool) []string {
 	headers := []string{
 		"CVE Count",
 		"Severity",
@@ -415,13 +1115,7 @@ func hostVulnCVEsPackagesSummary(cves []api.HostVulnCVE, withHosts bool) string
 	if withHosts {
 		headers = append(headers, "Hosts")
 	}
-	t.SetHeader(headers)
-	t.SetBorder(false)
-	t.SetAlignment(tablewriter.ALIGN_LEFT)
-	t.AppendBulk(hostVulnPackagesTable(cves, withHosts))
-	t.Render()
-
-	return tableBuilder.String()
+	return headers
 }
 
 func hostVulnPackagesTable(cves []api.HostVulnCVE, withHosts bool) [][]string {
@@ -481,16 +1175,152 @@ func hostVulnPackagesTable(cves []api.HostVulnCVE, withHosts bool) [][]string {
 
 	// order by severity
 	sort.Slice(out, func(i, j int) bool {
-		return severityOrder(out[i][1]) < severityOrder(out[j][1])
+		return api.ParseSeverity(out[i][1]).Order() < api.ParseSeverity(out[j][1]).Order()
 	})
 
 	return out
 }
 
+// hostVulnCVEsTableHeaders are the headers of the table and CSV outputs of CVEs
+var hostVulnCVEsTableHeaders = []string{
+	"CVE",
+	"Severity",
+	"Score",
+	"Package",
+	"Current Version",
+	"Fix Version",
+	"OS Version",
+	"Hosts",
+	"Pkg Status",
+	"Vuln Status",
+}
+
+// hostVulnHostAssessmentCVEsTableHeaders are the headers of the table of CVEs
+// of a host assessment, the OS version and number of hosts are not displayed
+var hostVulnHostAssessmentCVEsTableHeaders = []string{
+	"CVE",
+	"Severity",
+	"Score",
+	"Package",
+	"Current Version",
+	"Fix Version",
+	"Pkg Status",
+	"Vuln Status",
+}
+
+// hostVulnCVEsTableColumn is a column of the tables of host CVEs
+type hostVulnCVEsTableColumn struct {
+	// Name is the name used to select the column with the flag --columns
+	Name   string
+	Header string
+}
+
+// hostVulnCVEsTableColumns are all the columns of the tables of host CVEs,
+// not every table displays all of them
+var hostVulnCVEsTableColumns = []hostVulnCVEsTableColumn{
+	{"cve", "CVE"},
+	{"severity", "Severity"},
+	{"score", "Score"},
+	{"package", "Package"},
+	{"version", "Current Version"},
+	{"fix", "Fix Version"},
+	{"os", "OS Version"},
+	{"hosts", "Hosts"},
+	{"pkg_status", "Pkg Status"},
+	{"status", "Vuln Status"},
+}
+
+// hostVulnCVEsTableColumnNames returns the names of the columns of a table
+// of host CVEs with the provided headers
+func hostVulnCVEsTableColumnNames(headers []string) []string {
+	names := []string{}
+	for _, column := range hostVulnCVEsTableColumns {
+		if array.ContainsStr(headers, column.Header) {
+			names = append(names, column.Name)
+		}
+	}
+	return names
+}
+
+// hostVulnCVEsTableColumnIndexes returns the indexes of the provided headers
+// matching the column names in the same order, if no names are provided, it
+// returns nil which means that all the columns are displayed
+func hostVulnCVEsTableColumnIndexes(headers, names []string) ([]int, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var (
+		indexes  = []int{}
+		selected = map[string]bool{}
+	)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if selected[name] {
+			return nil, errors.Errorf("the column '%s' was selected more than once", name)
+		}
+
+		found := false
+		for _, column := range hostVulnCVEsTableColumns {
+			if column.Name != name {
+				continue
+			}
+			for i, header := range headers {
+				if header == column.Header {
+					indexes = append(indexes, i)
+					selected[name] = true
+					found = true
+				}
+			}
+			break
+		}
+		if !found {
+			return nil, errors.Errorf("unknown column '%s', use one of %s",
+				name, strings.Join(hostVulnCVEsTableColumnNames(headers), ", "),
+			)
+		}
+	}
+	return indexes, nil
+}
+
+// validateColumnsFlag verifies that the columns provided with the flag
+// --columns exist in a table of host CVEs with the provided headers
+func validateColumnsFlag(headers []string) error {
+	if vulCmdState.Packages && len(vulCmdState.Columns) != 0 {
+		return errors.New("cannot combine --packages with --columns")
+	}
+
+	_, err := hostVulnCVEsTableColumnIndexes(headers, vulCmdState.Columns)
+	return err
+}
+
+// selectHostVulnCVEsTableColumns returns the provided headers and rows with
+// only the columns selected with the flag --columns, the flag is validated
+// before rendering any table so unknown columns display all the columns
+func selectHostVulnCVEsTableColumns(headers []string, rows [][]string) ([]string, [][]string) {
+	indexes, err := hostVulnCVEsTableColumnIndexes(headers, vulCmdState.Columns)
+	if err != nil || indexes == nil {
+		return headers, rows
+	}
+
+	selectedHeaders := make([]string, len(indexes))
+	for i, index := range indexes {
+		selectedHeaders[i] = headers[index]
+	}
+
+	selectedRows := make([][]string, len(rows))
+	for r, row := range rows {
+		selectedRows[r] = make([]string, len(indexes))
+		for i, index := range indexes {
+			selectedRows[r][i] = row[index]
+		}
+	}
+	return selectedHeaders, selectedRows
+}
+
 func hostVulnCVEsToTable(cves []api.HostVulnCVE) string {
 	var (
 		tableBuilder = &strings.Builder{}
-		t            = tablewriter.NewWriter(tableBuilder)
 		rows         = hostVulnCVEsTable(cves)
 	)
 
@@ -500,21 +1330,13 @@ func hostVulnCVEsToTable(cves []api.HostVulnCVE) string {
 		return buildHostVulnCVEsToTableError()
 	}
 
-	t.SetHeader([]string{
-		"CVE",
-		"Severity",
-		"Score",
-		"Package",
-		"Current Version",
-		"Fix Version",
-		"OS Version",
-		"Hosts",
-		"Pkg Status",
-		"Vuln Status",
-	})
-	t.SetBorder(false)
-	t.AppendBulk(rows)
-	t.Render()
+	tableBuilder.WriteString(cli.RenderTable(
+		selectHostVulnCVEsTableColumns(hostVulnCVEsTableHeaders, rows),
+	))
+
+	if cli.Quiet() {
+		return tableBuilder.String()
+	}
 
 	if !vulCmdState.Active {
 		tableBuilder.WriteString(
@@ -555,7 +1377,7 @@ func hostVulnCVEsTableForSeverity(cves []api.HostVulnCVE, severity string) [][]s
 			if pkg.Severity == severity {
 				out = append(out, []string{
 					cve.ID,
-					pkg.Severity,
+					cli.colorizeSeverity(pkg.Severity),
 					pkg.CvssScore,
 					pkg.Name,
 					pkg.Version,
@@ -569,6 +1391,18 @@ func hostVulnCVEsTableForSeverity(cves []api.HostVulnCVE, severity string) [][]s
 		}
 	}
 
+	if !vulCmdState.NoDedupe {
+		out = dedupeHostVulnCVEsRows(out)
+	}
+
+	if vulCmdState.SortBy == "score" {
+		// order by cvss score
+		sort.Slice(out, func(i, j int) bool {
+			return cvssScoreGreater(out[i][2], out[j][2])
+		})
+		return out
+	}
+
 	// order by total number of host
 	sort.Slice(out, func(i, j int) bool {
 		return stringToInt(out[i][7]) > stringToInt(out[j][7])
@@ -577,18 +1411,119 @@ func hostVulnCVEsTableForSeverity(cves []api.HostVulnCVE, severity string) [][]s
 	return out
 }
 
-func hostVulnHostDetailsToTable(assessment api.HostVulnHostAssessment) string {
+// hostVulnSeverityStats is the number of vulnerabilities per severity of
+// a host CVE listing, it is computed from the rows of the table so that the
+// filters like --active and --fixable are taken into account
+type hostVulnSeverityStats struct {
+	Critical   int `json:"critical"`
+	High       int `json:"high"`
+	Medium     int `json:"medium"`
+	Low        int `json:"low"`
+	Negligible int `json:"negligible"`
+	Total      int `json:"total"`
+}
+
+// String returns a one-line human-readable representation of the stats
+func (s hostVulnSeverityStats) String() string {
+	return fmt.Sprintf("Critical: %d, High: %d, Medium: %d, Low: %d, Negligible: %d (Total: %d)",
+		s.Critical, s.High, s.Medium, s.Low, s.Negligible, s.Total,
+	)
+}
+
+func buildHostVulnSeverityStats(cves []api.HostVulnCVE) hostVulnSeverityStats {
+	stats := hostVulnSeverityStats{
+		Critical:   len(hostVulnCVEsTableForSeverity(cves, "Critical")),
+		High:       len(hostVulnCVEsTableForSeverity(cves, "High")),
+		Medium:     len(hostVulnCVEsTableForSeverity(cves, "Medium")),
+		Low:        len(hostVulnCVEsTableForSeverity(cves, "Low")),
+		Negligible: len(hostVulnCVEsTableForSeverity(cves, "Negligible")),
+	}
+	stats.Total = stats.Critical + stats.High + stats.Medium + stats.Low + stats.Negligible
+	return stats
+}
+
+// hostVulnFixableCount is the number of CVEs of a host that have a fix
+// available in at least one of their packages, and the total number of CVEs
+type hostVulnFixableCount struct {
+	Fixable int `json:"fixable"`
+	Total   int `json:"total"`
+}
+
+// String returns a one-line human-readable representation of the count
+func (c hostVulnFixableCount) String() string {
+	return fmt.Sprintf("Fixable: %d (Total: %d)", c.Fixable, c.Total)
+}
+
+// buildHostVulnFixableCount counts the fixable CVEs of a host, when the flag
+// --active is provided only the packages actively running are taken into account
+func buildHostVulnFixableCount(cves []api.HostVulnCVE) hostVulnFixableCount {
+	count := hostVulnFixableCount{}
+	for _, cve := range cves {
+		var found, fixable bool
+		for _, pkg := range cve.Packages {
+			if vulCmdState.Active && pkg.PackageStatus == "" {
+				continue
+			}
+
+			found = true
+			if pkg.FixedVersion != "" {
+				fixable = true
+			}
+		}
+
+		if found {
+			count.Total++
+		}
+		if fixable {
+			count.Fixable++
+		}
+	}
+	return count
+}
+
+// outputHostVulnFixableCount prints the fixable count in the output format of the cli
+func outputHostVulnFixableCount(count hostVulnFixableCount) error {
+	switch {
+	case cli.CSVOutput():
+		return cli.OutputCSV([]string{"Fixable", "Total"},
+			[][]string{{fmt.Sprintf("%d", count.Fixable), fmt.Sprintf("%d", count.Total)}},
+		)
+	case cli.StructuredOutput():
+		return cli.OutputStructured(count)
+	default:
+		cli.OutputHuman("%s\n", count)
+		return nil
+	}
+}
+
+// dedupeHostVulnCVEsRows collapses the rows of the same vulnerability, that is,
+// rows with the same CVE, package, version, fix version, namespace and statuses,
+// this happens on hosts with multiple architectures, since the collapsed rows
+// are reported by the same hosts, the number of hosts is the largest of them
+func dedupeHostVulnCVEsRows(rows [][]string) [][]string {
 	var (
-		tableBuilder        = &strings.Builder{}
-		hostDetailsTable    = &strings.Builder{}
-		hostVulnCountsTable = &strings.Builder{}
-		t                   = tablewriter.NewWriter(hostDetailsTable)
+		out   = [][]string{}
+		index = map[string]int{}
 	)
+	for _, row := range rows {
+		key := strings.Join([]string{row[0], row[3], row[4], row[5], row[6], row[8], row[9]}, "|")
+		if i, ok := index[key]; ok {
+			if stringToInt(row[7]) > stringToInt(out[i][7]) {
+				out[i][7] = row[7]
+			}
+			continue
+		}
+
+		index[key] = len(out)
+		out = append(out, row)
+	}
+	return out
+}
+
+func hostVulnHostDetailsToTable(assessment api.HostVulnHostAssessment) string {
+	tableBuilder := &strings.Builder{}
 
-	t.SetColumnSeparator("")
-	t.SetBorder(false)
-	t.SetAlignment(tablewriter.ALIGN_LEFT)
-	t.AppendBulk(
+	hostDetailsTable := cli.RenderTable(nil,
 		[][]string{
 			[]string{"Machine ID", assessment.Host.MachineID},
 			[]string{"Hostname", assessment.Host.Hostname},
@@ -601,41 +1536,43 @@ func hostVulnHostDetailsToTable(assessment api.HostVulnHostAssessment) string {
 			[]string{"Instance ID", assessment.Host.Tags.InstanceID},
 			[]string{"AMI", assessment.Host.Tags.AmiID},
 		},
+		withTableColumnSeparator(""),
+		withTableAlignment(tablewriter.ALIGN_LEFT),
 	)
-	t.Render()
 
-	t = tablewriter.NewWriter(hostVulnCountsTable)
-	t.SetBorder(false)
-	t.SetColumnSeparator(" ")
-	t.SetHeader([]string{
-		"Severity", "Count", "Fixable",
-	})
-	t.AppendBulk(hostVulnAssessmentToCountsTable(assessment.VulnerabilityCounts()))
-	t.Render()
-
-	t = tablewriter.NewWriter(tableBuilder)
-	t.SetBorder(false)
-	t.SetAutoWrapText(false)
-	t.SetHeader([]string{
-		"Host Details",
-		"Vulnerabilities",
-	})
-	t.Append([]string{
-		hostDetailsTable.String(),
-		hostVulnCountsTable.String(),
-	})
-	t.Render()
+	hostVulnCountsTable := cli.RenderTable(
+		[]string{"Severity", "Count", "Fixable"},
+		hostVulnAssessmentToCountsTable(assessment.VulnerabilityCounts()),
+		withTableColumnSeparator(" "),
+	)
+
+	tableBuilder.WriteString(cli.RenderTable(
+		[]string{
+			"Host Details",
+			"Vulnerabilities",
+		},
+		[][]string{{hostDetailsTable, hostVulnCountsTable}},
+		withTableAutoWrap(false),
+	))
 
-	if vulCmdState.Details || vulCmdState.Fixable || vulCmdState.Packages || vulCmdState.Active {
+	if vulCmdState.Details || vulCmdState.Fixable || vulCmdState.Packages ||
+		vulCmdState.Active || vulCmdState.ByPackage || len(vulCmdState.Columns) != 0 {
 		if vulCmdState.Packages {
 			tableBuilder.WriteString(hostVulnCVEsPackagesSummary(assessment.CVEs, false))
+		} else if vulCmdState.ByPackage {
+			tableBuilder.WriteString(hostVulnHostAssessmentPackagesToTable(assessment))
 		} else {
 			tableBuilder.WriteString(hostVulnHostAssessmentCVEsToTable(assessment))
 		}
 		tableBuilder.WriteString("\n")
 	}
 
-	if !vulCmdState.Details && !vulCmdState.Active && !vulCmdState.Fixable && !vulCmdState.Packages {
+	if cli.Quiet() {
+		return tableBuilder.String()
+	}
+
+	if !vulCmdState.Details && !vulCmdState.Active && !vulCmdState.Fixable &&
+		!vulCmdState.Packages && !vulCmdState.ByPackage && len(vulCmdState.Columns) == 0 {
 		tableBuilder.WriteString(
 			"Try adding '--details' to increase details shown about the vulnerability assessment.\n",
 		)
@@ -653,11 +1590,7 @@ func hostVulnHostDetailsToTable(assessment api.HostVulnHostAssessment) string {
 }
 
 func hostVulnHostAssessmentCVEsToTable(assessment api.HostVulnHostAssessment) string {
-	var (
-		tableBuilder = &strings.Builder{}
-		t            = tablewriter.NewWriter(tableBuilder)
-		rows         = hostVulnCVEsTableForHostView(assessment.CVEs)
-	)
+	rows := hostVulnCVEsTableForHostView(assessment.CVEs)
 
 	// if the user wants to show only vulnerabilities of active packages
 	// and we don't have any, show a friendly message
@@ -673,25 +1606,16 @@ func hostVulnHostAssessmentCVEsToTable(assessment api.HostVulnHostAssessment) st
 		}
 	}
 
-	t.SetHeader([]string{
-		"CVE",
-		"Severity",
-		"Score",
-		"Package",
-		"Current Version",
-		"Fix Version",
-		"Pgk Status",
-		"Vuln Status",
-	})
-	t.SetBorder(false)
-	t.AppendBulk(rows)
-	t.Render()
-
-	return tableBuilder.String()
+	return cli.RenderTable(
+		selectHostVulnCVEsTableColumns(hostVulnHostAssessmentCVEsTableHeaders, rows),
+	)
 }
 
 func hostVulnCVEsTableForHostView(cves []api.HostVulnCVE) [][]string {
-	out := [][]string{}
+	var (
+		out  = [][]string{}
+		seen = map[string]bool{}
+	)
 	for _, cve := range cves {
 		for _, pkg := range cve.Packages {
 			// if the user wants to show only vulnerabilities of acive packages
@@ -703,6 +1627,15 @@ func hostVulnCVEsTableForHostView(cves []api.HostVulnCVE) [][]string {
 				continue
 			}
 
+			// a host with multiple architectures reports the same vulnerability
+			// once per architecture, see dedupeHostVulnCVEsRows()
+			key := strings.Join([]string{cve.ID, pkg.Name, pkg.Version, pkg.FixedVersion,
+				pkg.Namespace, pkg.PackageStatus, pkg.VulnerabilityStatus}, "|")
+			if seen[key] && !vulCmdState.NoDedupe {
+				continue
+			}
+			seen[key] = true
+
 			out = append(out, []string{
 				cve.ID,
 				pkg.Severity,
@@ -716,11 +1649,115 @@ func hostVulnCVEsTableForHostView(cves []api.HostVulnCVE) [][]string {
 		}
 	}
 
-	// order by severity
+	// order by severity, and by cvss score if the user requested it
 	sort.Slice(out, func(i, j int) bool {
-		return severityOrder(out[i][1]) < severityOrder(out[j][1])
+		if vulCmdState.SortBy == "score" &&
+			api.ParseSeverity(out[i][1]).Order() == api.ParseSeverity(out[j][1]).Order() {
+			return cvssScoreGreater(out[i][2], out[j][2])
+		}
+		return api.ParseSeverity(out[i][1]).Order() < api.ParseSeverity(out[j][1]).Order()
+	})
+
+	return out
+}
+
+func hostVulnHostAssessmentPackagesToTable(assessment api.HostVulnHostAssessment) string {
+	rows := hostVulnPackagesTableForHostView(assessment.CVEs)
+
+	if len(rows) == 0 {
+		if vulCmdState.Active && vulCmdState.Fixable {
+			return "There are no fixable vulnerabilities with packages actively running in this host.\n"
+		}
+		if vulCmdState.Active {
+			return "There are no vulnerabilities with packages actively running in this host.\n"
+		}
+		if vulCmdState.Fixable {
+			return "There are no fixable vulnerabilities in this host.\n"
+		}
+	}
+
+	return cli.RenderTable(
+		[]string{
+			"Package",
+			"Current Version",
+			"Fix Version",
+			"Severity",
+			"CVEs",
+		},
+		rows,
+	)
+}
+
+// hostVulnPackagesTableForHostView collapses the CVEs of a host into one row
+// per package and version, every row contains the list of CVEs affecting the
+// package and the worst severity of them
+func hostVulnPackagesTableForHostView(cves []api.HostVulnCVE) [][]string {
+	type hostVulnPackageRow struct {
+		name          string
+		version       string
+		fixedVersions []string
+		severity      api.Severity
+		cves          []string
+	}
+
+	var (
+		rows  = []*hostVulnPackageRow{}
+		index = map[string]*hostVulnPackageRow{}
+	)
+	for _, cve := range cves {
+		for _, pkg := range cve.Packages {
+			// if the user wants to show only vulnerabilities of acive packages
+			if vulCmdState.Active && pkg.PackageStatus == "" {
+				continue
+			}
+
+			if vulCmdState.Fixable && pkg.FixedVersion == "" {
+				continue
+			}
+
+			key := pkg.Name + "|" + pkg.Version
+			row, ok := index[key]
+			if !ok {
+				row = &hostVulnPackageRow{
+					name:     pkg.Name,
+					version:  pkg.Version,
+					severity: api.SeverityUnknown,
+				}
+				index[key] = row
+				rows = append(rows, row)
+			}
+
+			if !array.ContainsStr(row.cves, cve.ID) {
+				row.cves = append(row.cves, cve.ID)
+			}
+			if pkg.FixedVersion != "" && !array.ContainsStr(row.fixedVersions, pkg.FixedVersion) {
+				row.fixedVersions = append(row.fixedVersions, pkg.FixedVersion)
+			}
+			if severity := api.ParseSeverity(pkg.Severity); severity.Order() < row.severity.Order() {
+				row.severity = severity
+			}
+		}
+	}
+
+	// order by worst severity, then by number of CVEs
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].severity.Order() == rows[j].severity.Order() {
+			return len(rows[i].cves) > len(rows[j].cves)
+		}
+		return rows[i].severity.Order() < rows[j].severity.Order()
 	})
 
+	out := [][]string{}
+	for _, row := range rows {
+		sort.Strings(row.cves)
+		out = append(out, []string{
+			row.name,
+			row.version,
+			strings.Join(row.fixedVersions, ", "),
+			row.severity.String(),
+			strings.Join(row.cves, ", "),
+		})
+	}
 	return out
 }
 
@@ -761,9 +1798,144 @@ func buildHostVulnCVEsToTableError() string {
 	if vulCmdState.Active {
 		msg = fmt.Sprintf("%s of packages actively running", msg)
 	}
+
+	if len(vulCmdState.VulnStatuses) != 0 {
+		msg = fmt.Sprintf("%s with the specified", msg)
+		if len(vulCmdState.VulnStatuses) == 1 {
+			msg = fmt.Sprintf("%s status", msg)
+		} else {
+			msg = fmt.Sprintf("%s statuses", msg)
+		}
+	}
+
+	if vulCmdState.MinScore != 0 {
+		msg = fmt.Sprintf("%s with a CVSS score of %g or higher", msg, vulCmdState.MinScore)
+	}
+
+	if len(vulCmdState.CVEs) != 0 {
+		msg = fmt.Sprintf("%s with the CVE id(s) %s", msg, strings.Join(vulCmdState.CVEs, ", "))
+	}
+
+	if len(vulCmdState.Namespaces) != 0 {
+		msg = fmt.Sprintf("%s from the specified", msg)
+		if len(vulCmdState.Namespaces) == 1 {
+			msg = fmt.Sprintf("%s namespace", msg)
+		} else {
+			msg = fmt.Sprintf("%s namespaces", msg)
+		}
+	}
 	return fmt.Sprintf("%s in your environment.\n", msg)
 }
 
+// filterHostVulnCVEsByNamespaces keeps only the packages of the CVEs that
+// match any of the namespaces provided by the user (case insensitive), CVEs
+// without packages are removed, it returns the number of packages filtered out
+func filterHostVulnCVEsByNamespaces(cves []api.HostVulnCVE) ([]api.HostVulnCVE, int) {
+	if len(vulCmdState.Namespaces) == 0 {
+		return cves, 0
+	}
+
+	return filterHostVulnCVEsPackages(cves, func(pkg api.HostVulnPackage) bool {
+		return containsStrFold(vulCmdState.Namespaces, pkg.Namespace)
+	})
+}
+
+// filterHostVulnCVEsByIDs keeps only the CVEs that match any of the provided
+// CVE ids (case insensitive), all CVEs are kept when no id is provided
+func filterHostVulnCVEsByIDs(cves []api.HostVulnCVE, ids []string) []api.HostVulnCVE {
+	if len(ids) == 0 {
+		return cves
+	}
+
+	out := []api.HostVulnCVE{}
+	for _, cve := range cves {
+		if containsStrFold(ids, cve.ID) {
+			out = append(out, cve)
+		}
+	}
+	return out
+}
+
+// filterHostVulnCVEsByStatuses keeps only the packages of the CVEs that
+// match any of the statuses provided by the user (case insensitive), CVEs
+// without packages are removed, it returns the number of packages filtered out
+func filterHostVulnCVEsByStatuses(cves []api.HostVulnCVE) ([]api.HostVulnCVE, int) {
+	if len(vulCmdState.VulnStatuses) == 0 {
+		return cves, 0
+	}
+
+	return filterHostVulnCVEsPackages(cves, func(pkg api.HostVulnPackage) bool {
+		return containsStrFold(vulCmdState.VulnStatuses, hostVulnPackageStatus(pkg))
+	})
+}
+
+// filterHostVulnCVEsByScore keeps only the packages of the CVEs with a CVSS
+// score at or above the threshold provided by the user, packages without a
+// valid score are removed unless the user provided --include-unscored, CVEs
+// without packages are removed, it returns the number of packages filtered out
+func filterHostVulnCVEsByScore(cves []api.HostVulnCVE) ([]api.HostVulnCVE, int) {
+	if vulCmdState.MinScore == 0 {
+		return cves, 0
+	}
+
+	return filterHostVulnCVEsPackages(cves, func(pkg api.HostVulnPackage) bool {
+		score, ok := cvssScoreToFloat(pkg.CvssScore)
+		if !ok {
+			return vulCmdState.IncludeUnscored
+		}
+		return score >= vulCmdState.MinScore
+	})
+}
+
+// filterHostVulnCVEsPackages keeps only the packages of the CVEs that the
+// provided function returns true, CVEs without packages are removed, it
+// returns the number of packages filtered out
+func filterHostVulnCVEsPackages(
+	cves []api.HostVulnCVE, keep func(api.HostVulnPackage) bool,
+) ([]api.HostVulnCVE, int) {
+	var (
+		filtered    = []api.HostVulnCVE{}
+		filteredOut = 0
+	)
+	for _, cve := range cves {
+		packages := []api.HostVulnPackage{}
+		for _, pkg := range cve.Packages {
+			if keep(pkg) {
+				packages = append(packages, pkg)
+			} else {
+				filteredOut++
+			}
+		}
+
+		if len(packages) != 0 {
+			cve.Packages = packages
+			filtered = append(filtered, cve)
+		}
+	}
+
+	return filtered, filteredOut
+}
+
+// hostVulnPackageStatus returns the status of a vulnerable package, the list
+// of CVEs returns it as 'status' and host assessments as 'vulnerability_status'
+func hostVulnPackageStatus(pkg api.HostVulnPackage) string {
+	if pkg.Status != "" {
+		return pkg.Status
+	}
+	return pkg.VulnerabilityStatus
+}
+
+// containsStrFold checks if the provided list contains the string s,
+// ignoring case and surrounding spaces of the elements in the list
+func containsStrFold(list []string, s string) bool {
+	for _, elem := range list {
+		if strings.EqualFold(strings.TrimSpace(elem), s) {
+			return true
+		}
+	}
+	return false
+}
+
 func addToHostSummary(text []string, num int32, severity string) []string {
 	if len(text) == 0 {
 		if num != 0 {
@@ -773,13 +1945,28 @@ func addToHostSummary(text []string, num int32, severity string) []string {
 	return text
 }
 
+// scanPkgManifest assesses the provided package manifest with the Lacework
+// API, or with the local vulnerability database when using --offline
+func scanPkgManifest(pkgManifest string) (api.HostVulnScanPkgManifestResponse, error) {
+	if pkgManifestOffline {
+		response, err := offlineScanPkgManifest(pkgManifestDB, pkgManifest)
+		if err != nil {
+			return response, errors.Wrap(err, "unable to run an offline host vulnerability scan")
+		}
+		return response, nil
+	}
+
+	response, err := cli.LwApi.Vulnerabilities.Host.Scan(pkgManifest)
+	if err != nil {
+		return response, errors.Wrap(err, "unable to request an on-demand host vulnerability scan")
+	}
+	return response, nil
+}
+
 func hostScanPackagesVulnToTable(scan *api.HostVulnScanPkgManifestResponse) string {
 	var (
-		tableBuilder   = &strings.Builder{}
-		summaryBuilder = &strings.Builder{}
-		t              *tablewriter.Table
-		rows           [][]string
-		headers        []string
+		rows    [][]string
+		headers []string
 	)
 
 	if vulCmdState.Packages {
@@ -815,30 +2002,17 @@ func hostScanPackagesVulnToTable(scan *api.HostVulnScanPkgManifestResponse) stri
 			scannedVia, randomEmoji())
 	}
 
-	t = tablewriter.NewWriter(summaryBuilder)
-	t.SetBorder(false)
-	t.SetColumnSeparator(" ")
-	t.SetHeader([]string{
-		"Severity", "Count", "Fixable",
-	})
-	t.AppendBulk(hostVulnAssessmentToCountsTable(scan.VulnerabilityCounts()))
-	t.Render()
-
-	t = tablewriter.NewWriter(tableBuilder)
-	t.SetBorder(false)
-	t.SetAutoWrapText(false)
-	t.SetHeader([]string{"Vulnerabilities"})
-	t.Append([]string{summaryBuilder.String()})
-	t.Render()
-
-	t = tablewriter.NewWriter(tableBuilder)
-	t.SetHeader(headers)
-	t.SetBorder(false)
-	t.SetAlignment(tablewriter.ALIGN_LEFT)
-	t.AppendBulk(rows)
-	t.Render()
+	summary := cli.RenderTable(
+		[]string{"Severity", "Count", "Fixable"},
+		hostVulnAssessmentToCountsTable(scan.VulnerabilityCounts()),
+		withTableColumnSeparator(" "),
+	)
 
-	return tableBuilder.String()
+	return cli.RenderTable([]string{"Vulnerabilities"}, [][]string{{summary}},
+		withTableAutoWrap(false),
+	) + cli.RenderTable(headers, rows,
+		withTableAlignment(tablewriter.ALIGN_LEFT),
+	)
 }
 
 func hostScanPackagesVulnDetailsTable(vulns []api.HostScanPackageVulnDetails) [][]string {
@@ -869,7 +2043,7 @@ func hostScanPackagesVulnDetailsTable(vulns []api.HostScanPackageVulnDetails) []
 
 	// order by severity
 	sort.Slice(out, func(i, j int) bool {
-		return severityOrder(out[i][1]) < severityOrder(out[j][1])
+		return api.ParseSeverity(out[i][1]).Order() < api.ParseSeverity(out[j][1]).Order()
 	})
 
 	return out
@@ -925,7 +2099,7 @@ func hostScanPackagesVulnPackagesView(vulns []api.HostScanPackageVulnDetails) []
 
 	// order by severity
 	sort.Slice(out, func(i, j int) bool {
-		return severityOrder(out[i][1]) < severityOrder(out[j][1])
+		return api.ParseSeverity(out[i][1]).Order() < api.ParseSeverity(out[j][1]).Order()
 	})
 
 	return out
@@ -0,0 +1,148 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+// defaultHostAssessmentCacheTTL is the time that the host vulnerability
+// assessments are cached on disk when the flag --cache is provided
+const defaultHostAssessmentCacheTTL = 15 * time.Minute
+
+// hostAssessmentCache is the content of a cache file of a host assessment
+type hostAssessmentCache struct {
+	Timestamp  time.Time                  `json:"timestamp"`
+	MachineID  string                     `json:"machine_id"`
+	Assessment api.HostVulnHostAssessment `json:"assessment"`
+}
+
+// useHostAssessmentCache returns true if the host assessments should be read
+// from, and stored into, the disk cache, the flag --no-cache overrides --cache
+func useHostAssessmentCache() bool {
+	return vulCmdState.Cache && !vulCmdState.NoCache
+}
+
+// getHostAssessment returns the vulnerability assessment of the provided machine
+// id, when the cache is enabled and it has a fresh copy of the assessment, the
+// API is not called and the time when the assessment was cached is returned
+func getHostAssessment(machineID string) (api.HostVulnHostAssessment, time.Time, error) {
+	cachePath := hostAssessmentCachePath(machineID)
+	if useHostAssessmentCache() {
+		if cache, ok := readHostAssessmentCache(cachePath, vulCmdState.CacheTTL); ok {
+			return cache.Assessment, cache.Timestamp, nil
+		}
+	}
+
+	response, err := cli.LwApi.Vulnerabilities.Host.GetHostAssessment(machineID)
+	if err != nil {
+		return response.Assessment, time.Time{}, err
+	}
+
+	if useHostAssessmentCache() {
+		purgeExpiredHostAssessments(filepath.Dir(cachePath), vulCmdState.CacheTTL)
+		writeHostAssessmentCache(cachePath, hostAssessmentCache{
+			Timestamp:  time.Now(),
+			MachineID:  machineID,
+			Assessment: response.Assessment,
+		})
+	}
+	return response.Assessment, time.Time{}, nil
+}
+
+// hostAssessmentCachePath returns the path of the cache file of the provided
+// machine id, the cache is unique per account and subaccount
+func hostAssessmentCachePath(machineID string) string {
+	return cachePath("host-assessments", cli.cacheAccount(),
+		url.PathEscape(machineID)+".json")
+}
+
+// readHostAssessmentCache reads the cache file from the provided path, expired
+// cache files are removed and reported as a miss
+func readHostAssessmentCache(path string, ttl time.Duration) (hostAssessmentCache, bool) {
+	var cache hostAssessmentCache
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cache, false
+	}
+
+	if err := json.Unmarshal(data, &cache); err != nil {
+		cli.Log.Debugw("invalid host assessment cache", "path", path, "error", err)
+		return cache, false
+	}
+
+	if time.Since(cache.Timestamp) > ttl {
+		cli.Log.Debugw("host assessment cache expired", "path", path)
+		removeHostAssessmentCache(path)
+		return cache, false
+	}
+
+	cli.Log.Debugw("using host assessment cache", "path", path)
+	return cache, true
+}
+
+func writeHostAssessmentCache(path string, cache hostAssessmentCache) {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		cli.Log.Debugw("unable to create host assessment cache directory", "error", err)
+		return
+	}
+
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		cli.Log.Debugw("unable to write host assessment cache", "path", path, "error", err)
+	}
+}
+
+// purgeExpiredHostAssessments removes the expired cache files of the provided
+// directory so that the cache doesn't grow with every machine ever queried
+func purgeExpiredHostAssessments(dir string, ttl time.Duration) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		// the modification time is the time when the assessment was cached,
+		// we avoid reading every file of the directory
+		if time.Since(file.ModTime()) > ttl {
+			removeHostAssessmentCache(filepath.Join(dir, file.Name()))
+		}
+	}
+}
+
+func removeHostAssessmentCache(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		cli.Log.Debugw("unable to remove host assessment cache", "path", path, "error", err)
+	}
+}
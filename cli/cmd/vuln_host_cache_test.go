@@ -0,0 +1,144 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/internal/lacework"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestGetHostAssessmentWithCache(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	defer func(cache, noCache bool, ttl time.Duration) {
+		vulCmdState.Cache = cache
+		vulCmdState.NoCache = noCache
+		vulCmdState.CacheTTL = ttl
+	}(vulCmdState.Cache, vulCmdState.NoCache, vulCmdState.CacheTTL)
+
+	cacheDir, err := ioutil.TempDir("", "lacework-host-cache")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(cacheDir)
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	os.Setenv("XDG_CACHE_HOME", cacheDir)
+
+	calls := 0
+	fakeServer := lacework.MockServer()
+	fakeServer.MockAPI("external/vulnerabilities/host/machineId/123",
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			fmt.Fprintf(w, `{"ok": true, "message": "SUCCESS", "data": {
+          "host": {"machine_id": "123", "hostname": "host-%d"},
+          "vulnerabilities": [{"cve_id": "CVE-2020-1234"}]
+        }}`, calls)
+		},
+	)
+	defer fakeServer.Close()
+
+	cli.Account = "test"
+	cli.Log = lwlogger.New("").Sugar()
+	cli.LwApi, err = api.NewClient("test",
+		api.WithURL(fakeServer.URL()),
+		api.WithToken("TOKEN"),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// the cache is opt-in
+	vulCmdState.Cache = false
+	vulCmdState.NoCache = false
+	vulCmdState.CacheTTL = defaultHostAssessmentCacheTTL
+	_, cachedAt, err := getHostAssessment("123")
+	assert.Nil(t, err)
+	assert.True(t, cachedAt.IsZero())
+	assert.NoFileExists(t, hostAssessmentCachePath("123"))
+
+	vulCmdState.Cache = true
+	assessment, cachedAt, err := getHostAssessment("123")
+	assert.Nil(t, err)
+	assert.True(t, cachedAt.IsZero(), "the first query should hit the API")
+	assert.Equal(t, "host-2", assessment.Host.Hostname)
+	assert.FileExists(t, filepath.Join(cacheDir, "lacework", "host-assessments", "test", "123.json"))
+
+	assessment, cachedAt, err = getHostAssessment("123")
+	assert.Nil(t, err)
+	assert.False(t, cachedAt.IsZero(), "the second query should use the cache")
+	assert.Equal(t, "host-2", assessment.Host.Hostname)
+	if assert.Len(t, assessment.CVEs, 1) {
+		assert.Equal(t, "CVE-2020-1234", assessment.CVEs[0].ID)
+	}
+	assert.Equal(t, 2, calls)
+
+	// --no-cache overrides --cache
+	vulCmdState.NoCache = true
+	assessment, _, err = getHostAssessment("123")
+	assert.Nil(t, err)
+	assert.Equal(t, "host-3", assessment.Host.Hostname)
+	assert.Equal(t, 3, calls)
+
+	// expired entries are removed and fetched again
+	vulCmdState.NoCache = false
+	vulCmdState.CacheTTL = time.Nanosecond
+	assessment, cachedAt, err = getHostAssessment("123")
+	assert.Nil(t, err)
+	assert.True(t, cachedAt.IsZero(), "an expired cache should be refreshed")
+	assert.Equal(t, "host-4", assessment.Host.Hostname)
+	assert.Equal(t, 4, calls)
+}
+
+func TestPurgeExpiredHostAssessments(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+
+	dir, err := ioutil.TempDir("", "lacework-host-cache")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		fresh   = filepath.Join(dir, "fresh.json")
+		expired = filepath.Join(dir, "expired.json")
+		other   = filepath.Join(dir, "other.txt")
+	)
+	for _, path := range []string{fresh, expired, other} {
+		assert.Nil(t, ioutil.WriteFile(path, []byte("{}"), 0600))
+	}
+	old := time.Now().Add(-time.Hour)
+	assert.Nil(t, os.Chtimes(expired, old, old))
+	assert.Nil(t, os.Chtimes(other, old, old))
+
+	purgeExpiredHostAssessments(dir, time.Minute)
+	assert.FileExists(t, fresh)
+	assert.NoFileExists(t, expired)
+	assert.FileExists(t, other, "only cache files should be removed")
+}
@@ -0,0 +1,188 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+var (
+	vulHostDiffCmd = &cobra.Command{
+		Use:   "diff <machine_id> <other_machine_id>",
+		Args:  cobra.ExactArgs(2),
+		Short: "compare the vulnerability assessments of two hosts",
+		Long: `Compare the current vulnerability assessments of two hosts and report the
+CVEs found only in the first host, only in the second host, and in both.
+
+This is useful to compare a host with a reference host, for instance, a host
+launched from a new image with a host launched from the previous one.
+
+The provided ids are the same machine ids accepted by the 'show-assessment'
+command. CVEs are matched by their id, package name and package version.
+
+    $ lacework vulnerability host diff my_machine_id my_other_machine_id`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if args[0] == args[1] {
+				return withExitCode(
+					errors.New("the machine ids must be different, the assessment of a host is always its current one"),
+					exitCodeUsage,
+				)
+			}
+
+			cli.StepProgress("Getting host assessments", 1, 2)
+			firstResponse, err := cli.LwApi.Vulnerabilities.Host.GetHostAssessment(args[0])
+			if err != nil {
+				cli.StopProgress()
+				return errors.Wrap(err, "unable to get host assessment with id "+args[0])
+			}
+
+			cli.StepProgress("Getting host assessments", 2, 2)
+			secondResponse, err := cli.LwApi.Vulnerabilities.Host.GetHostAssessment(args[1])
+			cli.StopProgress()
+			if err != nil {
+				return errors.Wrap(err, "unable to get host assessment with id "+args[1])
+			}
+
+			diff := diffHostVulnAssessments(firstResponse.Assessment, secondResponse.Assessment)
+			if cli.StructuredOutput() {
+				return cli.OutputStructured(diff)
+			}
+
+			cli.OutputHuman(hostVulnDiffToReport(args[0], args[1], diff))
+			return nil
+		},
+	}
+)
+
+func init() {
+	vulHostCmd.AddCommand(vulHostDiffCmd)
+}
+
+// hostVulnDiff is the result of comparing the assessments of two hosts
+type hostVulnDiff struct {
+	OnlyFirst  []hostVulnDiffEntry `json:"only_first"`
+	OnlySecond []hostVulnDiffEntry `json:"only_second"`
+	Both       []hostVulnDiffEntry `json:"both"`
+}
+
+type hostVulnDiffEntry struct {
+	CVE          string `json:"cve_id"`
+	Severity     string `json:"severity"`
+	Package      string `json:"package"`
+	Version      string `json:"version"`
+	FixedVersion string `json:"fixed_version"`
+}
+
+func (e hostVulnDiffEntry) key() string {
+	return strings.Join([]string{e.CVE, e.Package, e.Version}, "|")
+}
+
+func hostVulnDiffEntries(assessment api.HostVulnHostAssessment) map[string]hostVulnDiffEntry {
+	entries := map[string]hostVulnDiffEntry{}
+	for _, cve := range assessment.CVEs {
+		for _, pkg := range cve.Packages {
+			entry := hostVulnDiffEntry{
+				CVE:          cve.ID,
+				Severity:     pkg.Severity,
+				Package:      pkg.Name,
+				Version:      pkg.Version,
+				FixedVersion: pkg.FixedVersion,
+			}
+			entries[entry.key()] = entry
+		}
+	}
+	return entries
+}
+
+func diffHostVulnAssessments(first, second api.HostVulnHostAssessment) hostVulnDiff {
+	var (
+		diff = hostVulnDiff{
+			OnlyFirst:  []hostVulnDiffEntry{},
+			OnlySecond: []hostVulnDiffEntry{},
+			Both:       []hostVulnDiffEntry{},
+		}
+		firstEntries  = hostVulnDiffEntries(first)
+		secondEntries = hostVulnDiffEntries(second)
+	)
+
+	for key, entry := range firstEntries {
+		if _, ok := secondEntries[key]; ok {
+			diff.Both = append(diff.Both, entry)
+		} else {
+			diff.OnlyFirst = append(diff.OnlyFirst, entry)
+		}
+	}
+
+	for key, entry := range secondEntries {
+		if _, ok := firstEntries[key]; !ok {
+			diff.OnlySecond = append(diff.OnlySecond, entry)
+		}
+	}
+
+	sortHostVulnDiffEntries(diff.OnlyFirst)
+	sortHostVulnDiffEntries(diff.OnlySecond)
+	sortHostVulnDiffEntries(diff.Both)
+	return diff
+}
+
+// order by severity and then by CVE id
+func sortHostVulnDiffEntries(entries []hostVulnDiffEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if api.ParseSeverity(entries[i].Severity).Order() == api.ParseSeverity(entries[j].Severity).Order() {
+			return entries[i].key() < entries[j].key()
+		}
+		return api.ParseSeverity(entries[i].Severity).Order() < api.ParseSeverity(entries[j].Severity).Order()
+	})
+}
+
+func hostVulnDiffToReport(first, second string, diff hostVulnDiff) string {
+	report := &strings.Builder{}
+	report.WriteString(hostVulnDiffSection(fmt.Sprintf("Only in %s", first), diff.OnlyFirst))
+	report.WriteString("\n")
+	report.WriteString(hostVulnDiffSection(fmt.Sprintf("Only in %s", second), diff.OnlySecond))
+	report.WriteString("\n")
+	report.WriteString(hostVulnDiffSection("In both hosts", diff.Both))
+	return report.String()
+}
+
+func hostVulnDiffSection(title string, entries []hostVulnDiffEntry) string {
+	section := fmt.Sprintf("%s (%d)\n", title, len(entries))
+	if len(entries) == 0 {
+		return section
+	}
+
+	rows := [][]string{}
+	for _, e := range entries {
+		rows = append(rows, []string{e.CVE, e.Severity, e.Package, e.Version, e.FixedVersion})
+	}
+
+	return section + cli.RenderTable(
+		[]string{"CVE", "Severity", "Package", "Current Version", "Fix Version"},
+		rows,
+		withTableAlignment(tablewriter.ALIGN_LEFT),
+	)
+}
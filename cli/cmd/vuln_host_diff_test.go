@@ -0,0 +1,60 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+func TestDiffHostVulnAssessments(t *testing.T) {
+	var (
+		pkgA  = api.HostVulnPackage{Name: "openssl", Version: "1.0", Severity: "High"}
+		pkgA2 = api.HostVulnPackage{Name: "openssl", Version: "1.1", Severity: "High"}
+		pkgB  = api.HostVulnPackage{Name: "bash", Version: "4.4", Severity: "Low"}
+		first = api.HostVulnHostAssessment{CVEs: []api.HostVulnCVE{
+			{ID: "CVE-1", Packages: []api.HostVulnPackage{pkgA}},
+			{ID: "CVE-2", Packages: []api.HostVulnPackage{pkgB}},
+		}}
+		second = api.HostVulnHostAssessment{CVEs: []api.HostVulnCVE{
+			{ID: "CVE-1", Packages: []api.HostVulnPackage{pkgA2}},
+			{ID: "CVE-2", Packages: []api.HostVulnPackage{pkgB}},
+		}}
+	)
+
+	diff := diffHostVulnAssessments(first, second)
+	if assert.Len(t, diff.OnlyFirst, 1) {
+		assert.Equal(t, "1.0", diff.OnlyFirst[0].Version)
+	}
+	if assert.Len(t, diff.OnlySecond, 1) {
+		assert.Equal(t, "1.1", diff.OnlySecond[0].Version)
+	}
+	if assert.Len(t, diff.Both, 1) {
+		assert.Equal(t, "CVE-2", diff.Both[0].CVE)
+	}
+
+	report := hostVulnDiffToReport("host-a", "host-b", diff)
+	assert.Contains(t, report, "Only in host-a (1)")
+	assert.Contains(t, report, "Only in host-b (1)")
+	assert.Contains(t, report, "In both hosts (1)")
+	assert.Contains(t, report, "CURRENT VERSION")
+}
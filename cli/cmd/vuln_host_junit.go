@@ -0,0 +1,155 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+// validJUnitThresholds is the list of severities that can be used as the
+// threshold to fail the test cases of a JUnit report
+var validJUnitThresholds = []string{"critical", "high", "medium", "low", "info"}
+
+// junitTestSuites is a minimal representation of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	ID        string          `xml:"id,attr,omitempty"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// buildHostAssessmentJUnit converts the provided host assessment into a JUnit
+// report with a test suite for the host and a test case per package and CVE,
+// test cases with a severity equal or higher than the threshold are failures
+func buildHostAssessmentJUnit(assessment api.HostVulnHostAssessment, threshold api.Severity) junitTestSuites {
+	name := assessment.Host.Hostname
+	if name == "" {
+		name = assessment.Host.MachineID
+	}
+
+	suite := junitTestSuite{
+		Name:      name,
+		ID:        assessment.Host.MachineID,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Cases:     []junitTestCase{},
+	}
+
+	for _, cve := range assessment.CVEs {
+		for _, pkg := range cve.Packages {
+			testCase := junitTestCase{
+				Name:      fmt.Sprintf("%s: %s", pkg.Name, cve.ID),
+				ClassName: name,
+			}
+
+			severity := api.ParseSeverity(pkg.Severity)
+			if severity.Order() <= threshold.Order() {
+				testCase.Failure = &junitFailure{
+					Message: junitFailureMessage(cve.ID, pkg),
+					Type:    severity.String(),
+					Text:    junitFailureText(cve.ID, pkg),
+				}
+				suite.Failures++
+			}
+
+			suite.Tests++
+			suite.Cases = append(suite.Cases, testCase)
+		}
+	}
+
+	return junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}
+}
+
+func junitFailureMessage(cveID string, pkg api.HostVulnPackage) string {
+	fix := "no fix available"
+	if pkg.FixedVersion != "" {
+		fix = fmt.Sprintf("fixed in version %s", pkg.FixedVersion)
+	}
+	return fmt.Sprintf("%s severity vulnerability %s in %s %s, %s",
+		api.ParseSeverity(pkg.Severity), cveID, pkg.Name, pkg.Version, fix,
+	)
+}
+
+func junitFailureText(cveID string, pkg api.HostVulnPackage) string {
+	details := []string{
+		fmt.Sprintf("CVE: %s", cveID),
+		fmt.Sprintf("Package: %s", pkg.Name),
+		fmt.Sprintf("Current Version: %s", pkg.Version),
+		fmt.Sprintf("Fix Version: %s", pkg.FixedVersion),
+		fmt.Sprintf("Severity: %s", pkg.Severity),
+		fmt.Sprintf("CVSS Score: %s", pkg.CvssScore),
+	}
+	if pkg.CveLink != "" {
+		details = append(details, fmt.Sprintf("Link: %s", pkg.CveLink))
+	}
+	return strings.Join(details, "\n")
+}
+
+// writeHostAssessmentJUnit writes the JUnit report of the provided host
+// assessment to the specified file
+func writeHostAssessmentJUnit(path string, assessment api.HostVulnHostAssessment) error {
+	report := buildHostAssessmentJUnit(assessment, api.ParseSeverity(vulCmdState.JUnitThreshold))
+
+	out, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to generate JUnit report")
+	}
+
+	out = append([]byte(xml.Header), out...)
+	if err := ioutil.WriteFile(path, append(out, '\n'), 0644); err != nil {
+		return errors.Wrap(err, "unable to write JUnit report")
+	}
+
+	cli.Log.Infow("JUnit report written",
+		"path", path, "tests", report.Tests, "failures", report.Failures,
+	)
+	return nil
+}
@@ -0,0 +1,94 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+var junitAssessmentMock = api.HostVulnHostAssessment{
+	CVEs: []api.HostVulnCVE{
+		{ID: "CVE-1", Packages: []api.HostVulnPackage{
+			{Name: "openssl", Version: "1.1", Severity: "Critical", FixedVersion: "1.1g"},
+			{Name: "libssl", Version: "1.1", Severity: "Medium"},
+		}},
+		{ID: "CVE-2", Packages: []api.HostVulnPackage{
+			{Name: "bash", Version: "4.4", Severity: "Low"},
+		}},
+	},
+}
+
+func TestBuildHostAssessmentJUnit(t *testing.T) {
+	report := buildHostAssessmentJUnit(junitAssessmentMock, api.SeverityHigh)
+	assert.Equal(t, 3, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	if assert.Len(t, report.Suites, 1) && assert.Len(t, report.Suites[0].Cases, 3) {
+		cases := report.Suites[0].Cases
+		assert.Equal(t, "openssl: CVE-1", cases[0].Name)
+		if assert.NotNil(t, cases[0].Failure) {
+			assert.Equal(t,
+				"Critical severity vulnerability CVE-1 in openssl 1.1, fixed in version 1.1g",
+				cases[0].Failure.Message)
+			assert.Equal(t, "Critical", cases[0].Failure.Type)
+		}
+		assert.Nil(t, cases[1].Failure)
+		assert.Nil(t, cases[2].Failure)
+	}
+
+	report = buildHostAssessmentJUnit(junitAssessmentMock, api.SeverityMedium)
+	assert.Equal(t, 2, report.Failures)
+	if assert.Len(t, report.Suites, 1) && assert.NotNil(t, report.Suites[0].Cases[1].Failure) {
+		assert.Equal(t,
+			"Medium severity vulnerability CVE-1 in libssl 1.1, no fix available",
+			report.Suites[0].Cases[1].Failure.Message)
+	}
+}
+
+func TestWriteHostAssessmentJUnit(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	defer func(threshold string) { vulCmdState.JUnitThreshold = threshold }(vulCmdState.JUnitThreshold)
+	cli.Log = lwlogger.New("").Sugar()
+
+	dir, err := ioutil.TempDir("", "lacework-cli")
+	if assert.Nil(t, err) {
+		defer os.RemoveAll(dir)
+	}
+
+	vulCmdState.JUnitThreshold = "low"
+	file := path.Join(dir, "report.xml")
+	if assert.Nil(t, writeHostAssessmentJUnit(file, junitAssessmentMock)) {
+		out, err := ioutil.ReadFile(file)
+		assert.Nil(t, err)
+		assert.Contains(t, string(out), xml.Header)
+		assert.Contains(t, string(out), `<testsuites tests="3" failures="3">`)
+
+		report := junitTestSuites{}
+		assert.Nil(t, xml.Unmarshal(out, &report))
+		assert.Equal(t, 3, report.Failures)
+	}
+}
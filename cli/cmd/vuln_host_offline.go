@@ -0,0 +1,280 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+// offlineVulnDB is a local vulnerability database used to assess a package
+// manifest without reaching the Lacework API, useful for air-gapped hosts
+//
+// Example of a database file:
+//
+//   {
+//     "vulnerabilities": [
+//       {
+//         "id": "CVE-2020-1967",
+//         "namespace": "ubuntu:18.04",
+//         "package": "openssl",
+//         "severity": "High",
+//         "score": 7.5,
+//         "fixed_version": "1.1.1-1ubuntu2.1~18.04.6"
+//       }
+//     ]
+//   }
+//
+// A package is vulnerable when its installed version is lower than the fixed
+// version, entries without a fixed version match every installed version
+type offlineVulnDB struct {
+	Vulnerabilities []offlineVulnEntry `json:"vulnerabilities"`
+
+	// index of vulnerabilities by namespace and package name
+	index map[string][]offlineVulnEntry
+}
+
+type offlineVulnEntry struct {
+	ID           string  `json:"id"`
+	Namespace    string  `json:"namespace"`
+	Package      string  `json:"package"`
+	Severity     string  `json:"severity"`
+	Score        float64 `json:"score,omitempty"`
+	FixedVersion string  `json:"fixed_version,omitempty"`
+	Description  string  `json:"description,omitempty"`
+	Link         string  `json:"link,omitempty"`
+}
+
+func validateOfflineScanFlags() error {
+	if pkgManifestOffline && pkgManifestDB == "" {
+		return errors.New("the flag --db is required when using --offline")
+	}
+	if !pkgManifestOffline && pkgManifestDB != "" {
+		return errors.New("the flag --db can only be used with --offline")
+	}
+	return nil
+}
+
+// offlineScanPkgManifest assesses a package manifest against the vulnerability
+// database at the provided path
+func offlineScanPkgManifest(dbPath, pkgManifest string) (api.HostVulnScanPkgManifestResponse, error) {
+	manifest := new(PackageManifest)
+	if err := json.Unmarshal([]byte(pkgManifest), manifest); err != nil {
+		return api.HostVulnScanPkgManifestResponse{},
+			errors.Wrap(err, "unable to parse package manifest")
+	}
+
+	db, err := loadOfflineVulnDB(dbPath)
+	if err != nil {
+		return api.HostVulnScanPkgManifestResponse{}, err
+	}
+
+	cli.Log.Debugw("offline scan", "database", dbPath,
+		"vulnerabilities", len(db.Vulnerabilities),
+		"packages", len(manifest.OsPkgInfoList),
+	)
+	return db.Scan(manifest), nil
+}
+
+func loadOfflineVulnDB(path string) (*offlineVulnDB, error) {
+	dbBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read vulnerability database")
+	}
+
+	db := new(offlineVulnDB)
+	if err := json.Unmarshal(dbBytes, db); err != nil {
+		return nil, errors.Wrap(err, "unable to parse vulnerability database")
+	}
+
+	db.index = map[string][]offlineVulnEntry{}
+	for i, vuln := range db.Vulnerabilities {
+		if vuln.ID == "" || vuln.Namespace == "" || vuln.Package == "" {
+			return nil, errors.Errorf(
+				"invalid vulnerability database entry #%d, the fields 'id', 'namespace' and 'package' are required",
+				i+1,
+			)
+		}
+		key := offlineVulnDBKey(vuln.Namespace, vuln.Package)
+		db.index[key] = append(db.index[key], vuln)
+	}
+
+	return db, nil
+}
+
+func offlineVulnDBKey(namespace, pkg string) string {
+	return strings.ToLower(namespace) + "/" + strings.ToLower(pkg)
+}
+
+// Scan matches the packages of the provided manifest against the database and
+// returns a response with the same shape as the one from the Lacework API, so
+// that it can be rendered exactly like an online scan
+func (db *offlineVulnDB) Scan(manifest *PackageManifest) api.HostVulnScanPkgManifestResponse {
+	response := api.HostVulnScanPkgManifestResponse{Ok: true}
+
+	for _, pkg := range manifest.OsPkgInfoList {
+		namespace := strings.ToLower(pkg.Os) + ":" + pkg.OsVer
+		for _, vuln := range db.index[offlineVulnDBKey(namespace, pkg.Pkg)] {
+			if vuln.FixedVersion != "" &&
+				compareOfflineVersions(pkg.PkgVer, vuln.FixedVersion) >= 0 {
+				continue
+			}
+			response.Vulns = append(response.Vulns, offlineVulnDetails(namespace, pkg, vuln))
+		}
+	}
+
+	return response
+}
+
+func offlineVulnDetails(namespace string, pkg OsPkgInfo, vuln offlineVulnEntry) api.HostScanPackageVulnDetails {
+	details := api.HostScanPackageVulnDetails{
+		VulnID:   vuln.ID,
+		Severity: strings.Title(strings.ToLower(vuln.Severity)),
+	}
+	details.Summary.EvalStatus = "MATCH_VULN"
+	details.CVEProps.Description = vuln.Description
+	details.CVEProps.Link = vuln.Link
+	details.CVEProps.Metadata.NVD.CVSSv3.Score = vuln.Score
+	details.FeatureKey.Name = pkg.Pkg
+	details.FeatureKey.Namespace = namespace
+	details.OsPkgInfo.Namespace = namespace
+	details.OsPkgInfo.Os = pkg.Os
+	details.OsPkgInfo.OsVer = pkg.OsVer
+	details.OsPkgInfo.Pkg = pkg.Pkg
+	details.OsPkgInfo.PkgVer = pkg.PkgVer
+	details.FixInfo.VersionInstalled = pkg.PkgVer
+	details.FixInfo.EvalStatus = "NO_FIX"
+	if vuln.FixedVersion != "" {
+		details.FixInfo.EvalStatus = "GOOD"
+		details.FixInfo.FixAvailable = 1
+		details.FixInfo.FixedVersion = vuln.FixedVersion
+	}
+	return details
+}
+
+// compareOfflineVersions compares two package versions following the dpkg
+// ordering rules ([epoch:]upstream[-revision]), which also gives the expected
+// results for rpm and apk versions, it returns -1, 0 or 1
+func compareOfflineVersions(a, b string) int {
+	epochA, a := splitVersionEpoch(a)
+	epochB, b := splitVersionEpoch(b)
+	if c := compareVersionNumbers(epochA, epochB); c != 0 {
+		return c
+	}
+
+	upstreamA, revisionA := splitVersionRevision(a)
+	upstreamB, revisionB := splitVersionRevision(b)
+	if c := compareVersionFragments(upstreamA, upstreamB); c != 0 {
+		return c
+	}
+	return compareVersionFragments(revisionA, revisionB)
+}
+
+func splitVersionEpoch(version string) (string, string) {
+	if i := strings.Index(version, ":"); i > 0 && isAllDigits(version[:i]) {
+		return version[:i], version[i+1:]
+	}
+	return "0", version
+}
+
+func splitVersionRevision(version string) (string, string) {
+	if i := strings.LastIndex(version, "-"); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+// compareVersionFragments alternates between comparing non-digit prefixes,
+// character by character, and digit sequences, numerically
+func compareVersionFragments(a, b string) int {
+	for a != "" || b != "" {
+		for (a != "" && !isDigit(a[0])) || (b != "" && !isDigit(b[0])) {
+			orderA, orderB := versionCharOrder(a), versionCharOrder(b)
+			if orderA != orderB {
+				if orderA < orderB {
+					return -1
+				}
+				return 1
+			}
+			a, b = a[1:], b[1:]
+		}
+
+		var numberA, numberB string
+		numberA, a = splitLeadingDigits(a)
+		numberB, b = splitLeadingDigits(b)
+		if c := compareVersionNumbers(numberA, numberB); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// versionCharOrder returns the weight of the first character of a version
+// fragment, a tilde sorts before anything, even the end of the fragment, and
+// letters sort before any other symbol
+func versionCharOrder(s string) int {
+	switch {
+	case s == "" || isDigit(s[0]):
+		return 0
+	case s[0] == '~':
+		return -1
+	case (s[0] >= 'a' && s[0] <= 'z') || (s[0] >= 'A' && s[0] <= 'Z'):
+		return int(s[0])
+	default:
+		return int(s[0]) + 256
+	}
+}
+
+func compareVersionNumbers(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func splitLeadingDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isAllDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
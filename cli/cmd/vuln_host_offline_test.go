@@ -0,0 +1,152 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var offlineVulnDBTest = `{
+  "vulnerabilities": [
+    {
+      "id": "CVE-2020-1967",
+      "namespace": "ubuntu:18.04",
+      "package": "openssl",
+      "severity": "high",
+      "score": 7.5,
+      "fixed_version": "1.1.1-1ubuntu2.1~18.04.6"
+    },
+    {
+      "id": "CVE-2019-1551",
+      "namespace": "ubuntu:18.04",
+      "package": "openssl",
+      "severity": "Low",
+      "fixed_version": "1.1.1-1ubuntu2.1~18.04.5"
+    },
+    {
+      "id": "CVE-2021-3999",
+      "namespace": "ubuntu:18.04",
+      "package": "libc6",
+      "severity": "Medium"
+    },
+    {
+      "id": "CVE-2020-1234",
+      "namespace": "ubuntu:20.04",
+      "package": "openssl",
+      "severity": "Critical",
+      "fixed_version": "9.9.9"
+    }
+  ]
+}`
+
+func TestCompareOfflineVersions(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.1", -1},
+		{"1.10", "1.9", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0a", "1.0", 1},
+		{"1.0-1", "1.0-2", -1},
+		{"1:1.0", "2.0", 1},
+		{"0:7.29.0-59.el7", "0:7.29.0-59.el7_9.1", -1},
+		{"1.1.1-1ubuntu2.1~18.04.5", "1.1.1-1ubuntu2.1~18.04.6", -1},
+		{"1.01", "1.1", 0},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, compareOfflineVersions(c.a, c.b), "%s vs %s", c.a, c.b)
+		assert.Equal(t, -c.expected, compareOfflineVersions(c.b, c.a), "%s vs %s", c.b, c.a)
+	}
+}
+
+func TestOfflineVulnDBScan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lacework-offline-db")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	dbPath := filepath.Join(dir, "vulns.json")
+	require.Nil(t, ioutil.WriteFile(dbPath, []byte(offlineVulnDBTest), 0600))
+
+	db, err := loadOfflineVulnDB(dbPath)
+	require.Nil(t, err)
+
+	response := db.Scan(&PackageManifest{OsPkgInfoList: []OsPkgInfo{
+		{Os: "Ubuntu", OsVer: "18.04", Pkg: "openssl", PkgVer: "1.1.1-1ubuntu2.1~18.04.5"},
+		{Os: "Ubuntu", OsVer: "18.04", Pkg: "libc6", PkgVer: "2.27-3ubuntu1"},
+		{Os: "Ubuntu", OsVer: "18.04", Pkg: "bash", PkgVer: "4.4.18-2ubuntu1"},
+	}})
+	if assert.Len(t, response.Vulns, 2) {
+		assert.Equal(t, "CVE-2020-1967", response.Vulns[0].VulnID)
+		assert.Equal(t, "High", response.Vulns[0].Severity)
+		assert.Equal(t, "7.5", response.Vulns[0].ScoreString())
+		assert.Equal(t, "GOOD", response.Vulns[0].FixInfo.EvalStatus)
+		assert.Equal(t, "ubuntu:18.04", response.Vulns[0].OsPkgInfo.Namespace)
+
+		assert.Equal(t, "CVE-2021-3999", response.Vulns[1].VulnID)
+		assert.Equal(t, "MATCH_VULN", response.Vulns[1].Summary.EvalStatus)
+		assert.NotEqual(t, "GOOD", response.Vulns[1].FixInfo.EvalStatus)
+	}
+
+	table := hostScanPackagesVulnToTable(&response)
+	assert.Contains(t, table, "CVE-2020-1967")
+	assert.Contains(t, table, "1.1.1-1ubuntu2.1~18.04.6")
+	assert.Contains(t, table, "CVE-2021-3999")
+}
+
+func TestLoadOfflineVulnDBErrors(t *testing.T) {
+	_, err := loadOfflineVulnDB("/path/that/does/not/exist.json")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unable to read vulnerability database")
+	}
+
+	dir, err := ioutil.TempDir("", "lacework-offline-db")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	dbPath := filepath.Join(dir, "vulns.json")
+	require.Nil(t, ioutil.WriteFile(dbPath,
+		[]byte(`{"vulnerabilities":[{"id":"CVE-1","package":"bash"}]}`), 0600))
+	_, err = loadOfflineVulnDB(dbPath)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid vulnerability database entry #1")
+	}
+}
+
+func TestValidateOfflineScanFlags(t *testing.T) {
+	defer func(offline bool, db string) {
+		pkgManifestOffline, pkgManifestDB = offline, db
+	}(pkgManifestOffline, pkgManifestDB)
+
+	pkgManifestOffline, pkgManifestDB = true, ""
+	assert.EqualError(t, validateOfflineScanFlags(), "the flag --db is required when using --offline")
+
+	pkgManifestOffline, pkgManifestDB = false, "vulns.json"
+	assert.EqualError(t, validateOfflineScanFlags(), "the flag --db can only be used with --offline")
+
+	pkgManifestOffline, pkgManifestDB = true, "vulns.json"
+	assert.Nil(t, validateOfflineScanFlags())
+}
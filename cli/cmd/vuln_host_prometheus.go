@@ -0,0 +1,125 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+// promHostMetric is a metric of a host assessment in the Prometheus text
+// format, the value of every severity is written as a separate sample
+type promHostMetric struct {
+	Name   string
+	Help   string
+	Values func(api.HostVulnCounts) []int32
+}
+
+// promSeverities are the values of the 'severity' label of the metrics,
+// in the same order as the values of the promHostMetrics
+var promSeverities = []string{"critical", "high", "medium", "low", "negligible"}
+
+var promHostMetrics = []promHostMetric{
+	{
+		Name: "lacework_host_cve_count",
+		Help: "Number of vulnerabilities of the host by severity.",
+		Values: func(c api.HostVulnCounts) []int32 {
+			return []int32{c.Critical, c.High, c.Medium, c.Low, c.Negligible}
+		},
+	},
+	{
+		Name: "lacework_host_cve_fixable_count",
+		Help: "Number of fixable vulnerabilities of the host by severity.",
+		Values: func(c api.HostVulnCounts) []int32 {
+			return []int32{c.CritFixable, c.HighFixable, c.MedFixable, c.LowFixable, c.NegFixable}
+		},
+	},
+}
+
+// buildHostAssessmentPrometheus converts the provided host assessment into
+// metrics in the Prometheus text format, the labels of the metrics are
+// derived from the tags of the host
+func buildHostAssessmentPrometheus(assessment api.HostVulnHostAssessment) string {
+	var (
+		out    = &strings.Builder{}
+		counts = assessment.VulnerabilityCounts()
+		labels = promHostLabels(assessment)
+	)
+
+	for _, metric := range promHostMetrics {
+		fmt.Fprintf(out, "# HELP %s %s\n", metric.Name, metric.Help)
+		fmt.Fprintf(out, "# TYPE %s gauge\n", metric.Name)
+		for i, value := range metric.Values(counts) {
+			fmt.Fprintf(out, "%s{%s,severity=\"%s\"} %d\n",
+				metric.Name, labels, promSeverities[i], value)
+		}
+	}
+
+	return out.String()
+}
+
+func promHostLabels(assessment api.HostVulnHostAssessment) string {
+	host := assessment.Host
+	labels := [][2]string{
+		{"machine_id", host.MachineID},
+		{"hostname", host.Hostname},
+		{"os", host.Tags.Os},
+		{"arch", host.Tags.Arch},
+		{"provider", host.Tags.VmProvider},
+		{"instance_id", host.Tags.InstanceID},
+		{"zone", host.Tags.Zone},
+	}
+
+	out := make([]string, len(labels))
+	for i, label := range labels {
+		out[i] = fmt.Sprintf("%s=\"%s\"", label[0], promEscapeLabelValue(label[1]))
+	}
+	return strings.Join(out, ",")
+}
+
+// promEscapeLabelValue escapes the backslashes, double-quotes and line feeds
+// of a label value as required by the Prometheus text format
+func promEscapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// writeHostAssessmentPrometheus writes the metrics of the provided host
+// assessment to the specified file, the file is written into a temporary
+// file first and then renamed, so that the textfile collector of the
+// node-exporter never reads a partially written file
+func writeHostAssessmentPrometheus(path string, assessment api.HostVulnHostAssessment) error {
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, []byte(buildHostAssessmentPrometheus(assessment)), 0644); err != nil {
+		return errors.Wrap(err, "unable to write Prometheus metrics")
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrap(err, "unable to write Prometheus metrics")
+	}
+
+	cli.Log.Infow("Prometheus metrics written", "path", path)
+	return nil
+}
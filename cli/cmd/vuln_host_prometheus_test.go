@@ -0,0 +1,66 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestBuildHostAssessmentPrometheus(t *testing.T) {
+	assessment := junitAssessmentMock
+	assessment.Host.MachineID = "123"
+	assessment.Host.Hostname = `web-"1"`
+	assessment.Host.Tags.Os = "Ubuntu"
+
+	metrics := buildHostAssessmentPrometheus(assessment)
+	labels := `machine_id="123",hostname="web-\"1\"",os="Ubuntu",arch="",provider="",instance_id="",zone=""`
+	assert.Contains(t, metrics, "# TYPE lacework_host_cve_count gauge\n")
+	assert.Contains(t, metrics, "lacework_host_cve_count{"+labels+`,severity="critical"} 1`+"\n")
+	assert.Contains(t, metrics, "lacework_host_cve_count{"+labels+`,severity="medium"} 1`+"\n")
+	assert.Contains(t, metrics, "lacework_host_cve_count{"+labels+`,severity="high"} 0`+"\n")
+	assert.Contains(t, metrics, "lacework_host_cve_fixable_count{"+labels+`,severity="critical"} 1`+"\n")
+	assert.Contains(t, metrics, "lacework_host_cve_fixable_count{"+labels+`,severity="low"} 0`+"\n")
+}
+
+func TestWriteHostAssessmentPrometheus(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+
+	dir, err := ioutil.TempDir("", "lacework-cli")
+	if assert.Nil(t, err) {
+		defer os.RemoveAll(dir)
+	}
+
+	promPath := path.Join(dir, "lacework.prom")
+	if assert.Nil(t, writeHostAssessmentPrometheus(promPath, api.HostVulnHostAssessment{})) {
+		content, err := ioutil.ReadFile(promPath)
+		if assert.Nil(t, err) {
+			assert.Contains(t, string(content), "# HELP lacework_host_cve_count")
+		}
+		assert.NoFileExists(t, promPath+".tmp")
+	}
+}
@@ -0,0 +1,378 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+// validSbomFormats is the list of supported Software Bill of Materials formats
+var validSbomFormats = []string{"cyclonedx", "spdx"}
+
+// cycloneDxBom is a minimal representation of a CycloneDX (v1.4) JSON document
+type cycloneDxBom struct {
+	BomFormat       string                   `json:"bomFormat"`
+	SpecVersion     string                   `json:"specVersion"`
+	SerialNumber    string                   `json:"serialNumber"`
+	Version         int                      `json:"version"`
+	Metadata        cycloneDxMetadata        `json:"metadata"`
+	Components      []cycloneDxComponent     `json:"components"`
+	Vulnerabilities []cycloneDxVulnerability `json:"vulnerabilities,omitempty"`
+}
+
+type cycloneDxMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []cycloneDxTool    `json:"tools"`
+	Component cycloneDxComponent `json:"component"`
+}
+
+type cycloneDxTool struct {
+	Vendor  string `json:"vendor"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type cycloneDxComponent struct {
+	Type    string `json:"type"`
+	BomRef  string `json:"bom-ref"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Purl    string `json:"purl,omitempty"`
+}
+
+type cycloneDxVulnerability struct {
+	ID      string            `json:"id"`
+	Source  cycloneDxSource   `json:"source"`
+	Ratings []cycloneDxRating `json:"ratings,omitempty"`
+	Affects []cycloneDxAffect `json:"affects"`
+}
+
+type cycloneDxSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type cycloneDxRating struct {
+	Score    float64 `json:"score,omitempty"`
+	Severity string  `json:"severity"`
+}
+
+type cycloneDxAffect struct {
+	Ref string `json:"ref"`
+}
+
+// spdxDocument is a minimal representation of a SPDX (v2.3) JSON document
+type spdxDocument struct {
+	SpdxVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SpdxElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+// sbomPackage is an intermediate representation of a package found in a host
+// assessment, it holds the list of CVEs that affect such package
+type sbomPackage struct {
+	Name      string
+	Version   string
+	Namespace string
+	CVEs      []api.HostVulnCVE
+}
+
+func (p sbomPackage) Purl() string {
+	var (
+		distro  = strings.Replace(p.Namespace, ":", "-", 1)
+		family  = strings.ToLower(strings.Split(p.Namespace, ":")[0])
+		pkgType = "generic"
+	)
+
+	switch family {
+	case "ubuntu", "debian":
+		pkgType = "deb"
+	case "centos", "rhel", "amzn", "amazonlinux", "ol", "oracle", "fedora", "sles":
+		pkgType = "rpm"
+	case "alpine":
+		pkgType = "apk"
+	}
+
+	if distro == "" {
+		return fmt.Sprintf("pkg:%s/%s@%s", pkgType, p.Name, p.Version)
+	}
+	return fmt.Sprintf("pkg:%s/%s/%s@%s?distro=%s", pkgType, family, p.Name, p.Version, distro)
+}
+
+// buildHostAssessmentSBOM converts the provided host assessment into a Software
+// Bill of Materials document of the specified format (cyclonedx or spdx)
+func buildHostAssessmentSBOM(format string, assessment api.HostVulnHostAssessment) (interface{}, error) {
+	packages, err := sbomPackagesFromHostAssessment(assessment)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "cyclonedx":
+		return hostAssessmentToCycloneDx(assessment, packages), nil
+	case "spdx":
+		return hostAssessmentToSpdx(assessment, packages), nil
+	default:
+		return nil, errors.Errorf("the SBOM format %s is not valid, use one of %s",
+			format, strings.Join(validSbomFormats, ", "),
+		)
+	}
+}
+
+// sbomPackagesFromHostAssessment collapses the CVEs from a host assessment into a list
+// of unique packages, it errors out if the assessment lacks the required package data
+func sbomPackagesFromHostAssessment(assessment api.HostVulnHostAssessment) ([]*sbomPackage, error) {
+	var (
+		packages = []*sbomPackage{}
+		index    = map[string]*sbomPackage{}
+	)
+
+	for _, cve := range assessment.CVEs {
+		for _, pkg := range cve.Packages {
+			if pkg.Name == "" || pkg.Version == "" {
+				return nil, errors.Errorf(
+					"unable to generate SBOM: package name and version are required (cve: %s)", cve.ID,
+				)
+			}
+
+			key := fmt.Sprintf("%s:%s:%s", pkg.Namespace, pkg.Name, pkg.Version)
+			if _, ok := index[key]; !ok {
+				index[key] = &sbomPackage{
+					Name:      pkg.Name,
+					Version:   pkg.Version,
+					Namespace: pkg.Namespace,
+				}
+				packages = append(packages, index[key])
+			}
+
+			index[key].CVEs = append(index[key].CVEs, api.HostVulnCVE{
+				ID:       cve.ID,
+				Packages: []api.HostVulnPackage{pkg},
+			})
+		}
+	}
+
+	if len(packages) == 0 {
+		return nil, errors.New("unable to generate SBOM: the host assessment has no package data")
+	}
+
+	return packages, nil
+}
+
+func hostAssessmentToCycloneDx(assessment api.HostVulnHostAssessment, packages []*sbomPackage) cycloneDxBom {
+	bom := cycloneDxBom{
+		BomFormat:    "CycloneDX",
+		SpecVersion:  "1.4",
+		SerialNumber: fmt.Sprintf("urn:uuid:%s", newUUID()),
+		Version:      1,
+		Metadata: cycloneDxMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools: []cycloneDxTool{
+				{Vendor: "Lacework", Name: "lacework-cli", Version: Version},
+			},
+			Component: cycloneDxComponent{
+				Type:   "operating-system",
+				BomRef: assessment.Host.MachineID,
+				Name:   assessment.Host.Hostname,
+			},
+		},
+		Components:      []cycloneDxComponent{},
+		Vulnerabilities: []cycloneDxVulnerability{},
+	}
+
+	vulnIndex := map[string]int{}
+	for _, pkg := range packages {
+		purl := pkg.Purl()
+		bom.Components = append(bom.Components, cycloneDxComponent{
+			Type:    "library",
+			BomRef:  purl,
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Purl:    purl,
+		})
+
+		for _, cve := range pkg.CVEs {
+			if i, ok := vulnIndex[cve.ID]; ok {
+				bom.Vulnerabilities[i].Affects = append(bom.Vulnerabilities[i].Affects,
+					cycloneDxAffect{Ref: purl})
+				continue
+			}
+
+			var (
+				details = cve.Packages[0]
+				rating  = cycloneDxRating{Severity: cycloneDxSeverity(details.Severity)}
+			)
+			if score, ok := cvssScoreToFloat(details.CvssScore); ok {
+				rating.Score = score
+			}
+
+			vulnIndex[cve.ID] = len(bom.Vulnerabilities)
+			bom.Vulnerabilities = append(bom.Vulnerabilities, cycloneDxVulnerability{
+				ID:      cve.ID,
+				Source:  cycloneDxSource{Name: "NVD", URL: details.CveLink},
+				Ratings: []cycloneDxRating{rating},
+				Affects: []cycloneDxAffect{{Ref: purl}},
+			})
+		}
+	}
+
+	return bom
+}
+
+// cycloneDxSeverity maps the severities of Lacework to the ones allowed by
+// CycloneDX: critical, high, medium, low, info, none and unknown
+func cycloneDxSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "critical":
+		return "critical"
+	case "high":
+		return "high"
+	case "medium":
+		return "medium"
+	case "low":
+		return "low"
+	case "negligible", "info", "informational":
+		return "info"
+	default:
+		return "unknown"
+	}
+}
+
+// writeSBOM writes the provided SBOM as indented JSON, without the colors
+// of the JSON output of the CLI, and regardless of --json-compact and --jsonl,
+// since SBOMs are usually stored in a file and read by other tools
+func writeSBOM(w io.Writer, sbom interface{}) error {
+	data, err := json.MarshalIndent(sbom, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to encode SBOM")
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func hostAssessmentToSpdx(assessment api.HostVulnHostAssessment, packages []*sbomPackage) spdxDocument {
+	doc := spdxDocument{
+		SpdxVersion: "SPDX-2.3",
+		DataLicense: "CC0-1.0",
+		SPDXID:      "SPDXRef-DOCUMENT",
+		Name:        assessment.Host.Hostname,
+		DocumentNamespace: fmt.Sprintf("https://lacework.net/spdxdocs/%s-%s",
+			assessment.Host.MachineID, newUUID()),
+		CreationInfo: spdxCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{fmt.Sprintf("Tool: lacework-cli-%s", Version)},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	for i, pkg := range packages {
+		spdxID := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		refs := []spdxExternalRef{
+			{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  pkg.Purl(),
+			},
+		}
+		for _, cve := range pkg.CVEs {
+			locator := cve.Packages[0].CveLink
+			if locator == "" {
+				locator = fmt.Sprintf("https://nvd.nist.gov/vuln/detail/%s", cve.ID)
+			}
+			refs = append(refs, spdxExternalRef{
+				ReferenceCategory: "SECURITY",
+				ReferenceType:     "advisory",
+				ReferenceLocator:  locator,
+			})
+		}
+
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SPDXID:           spdxID,
+			Name:             pkg.Name,
+			VersionInfo:      pkg.Version,
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+			ExternalRefs:     refs,
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SpdxElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSpdxElement: spdxID,
+		})
+	}
+
+	return doc
+}
+
+// newUUID generates a random (version 4) UUID
+func newUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "00000000-0000-4000-8000-000000000000"
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
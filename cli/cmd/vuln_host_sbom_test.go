@@ -0,0 +1,94 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+func TestBuildHostAssessmentSBOM(t *testing.T) {
+	assessment := api.HostVulnHostAssessment{
+		CVEs: []api.HostVulnCVE{
+			{ID: "CVE-1", Packages: []api.HostVulnPackage{
+				{Name: "openssl", Version: "1.1", Namespace: "ubuntu:18.04", Severity: "High", CvssScore: "7.5"},
+			}},
+			{ID: "CVE-2", Packages: []api.HostVulnPackage{
+				{Name: "openssl", Version: "1.1", Namespace: "ubuntu:18.04", Severity: "Low"},
+			}},
+		},
+	}
+
+	sbom, err := buildHostAssessmentSBOM("cyclonedx", assessment)
+	if assert.Nil(t, err) {
+		bom, ok := sbom.(cycloneDxBom)
+		if assert.True(t, ok) {
+			assert.Len(t, bom.Components, 1)
+			assert.Equal(t, "pkg:deb/ubuntu/openssl@1.1?distro=ubuntu-18.04", bom.Components[0].Purl)
+			assert.Len(t, bom.Vulnerabilities, 2)
+			assert.Equal(t, 7.5, bom.Vulnerabilities[0].Ratings[0].Score)
+		}
+	}
+
+	sbom, err = buildHostAssessmentSBOM("spdx", assessment)
+	if assert.Nil(t, err) {
+		doc, ok := sbom.(spdxDocument)
+		if assert.True(t, ok) {
+			assert.Len(t, doc.Packages, 1)
+			// one purl reference plus two advisories
+			assert.Len(t, doc.Packages[0].ExternalRefs, 3)
+		}
+	}
+
+	_, err = buildHostAssessmentSBOM("cyclonedx", api.HostVulnHostAssessment{})
+	if assert.NotNil(t, err) {
+		assert.Equal(t,
+			"unable to generate SBOM: the host assessment has no package data",
+			err.Error())
+	}
+}
+
+func TestCycloneDxSeverity(t *testing.T) {
+	cases := map[string]string{
+		"Critical":   "critical",
+		"High":       "high",
+		"Medium":     "medium",
+		"low":        "low",
+		"Negligible": "info",
+		"Info":       "info",
+		"":           "unknown",
+		"Severe":     "unknown",
+	}
+	for severity, expected := range cases {
+		assert.Equal(t, expected, cycloneDxSeverity(severity), severity)
+	}
+}
+
+func TestWriteSBOM(t *testing.T) {
+	var out bytes.Buffer
+	err := writeSBOM(&out, cycloneDxBom{BomFormat: "CycloneDX", SpecVersion: "1.4"})
+	if assert.Nil(t, err) {
+		assert.Contains(t, out.String(), "{\n  \"bomFormat\": \"CycloneDX\",\n")
+		assert.NotContains(t, out.String(), "\x1b[", "the SBOM should not be colored")
+	}
+}
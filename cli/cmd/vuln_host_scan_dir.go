@@ -0,0 +1,374 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+
+	"github.com/lacework/go-sdk/api"
+)
+
+// the statuses of the package manifests of a bulk directory scan
+const (
+	scanDirStatusScanned = "scanned"
+	scanDirStatusCached  = "cached"
+	scanDirStatusFailed  = "failed"
+)
+
+// scanDirRun is a bulk scan of a directory of package manifests, the result of
+// every manifest is stored in the run directory so that an interrupted scan
+// can be resumed without re-scanning the manifests that already have results
+//
+// Layout of the run directory:
+//
+//   <run-dir>/results/<manifest>.json   the result of every scanned manifest
+//   <run-dir>/failures.json             the manifests that failed to scan
+type scanDirRun struct {
+	Dir    string
+	RunDir string
+	Resume bool
+
+	// the function used to assess every manifest, see scanPkgManifest()
+	scan func(string) (api.HostVulnScanPkgManifestResponse, error)
+
+	// the pool that bounds the manifests scanned at once, usually the one of
+	// the api client (--concurrency), when nil, they are scanned one at a time
+	pool *api.WorkerPool
+}
+
+type scanDirResult struct {
+	Manifest string                               `json:"manifest"`
+	Status   string                               `json:"status"`
+	Error    string                               `json:"error,omitempty"`
+	Result   *api.HostVulnScanPkgManifestResponse `json:"result,omitempty"`
+}
+
+func validateScanDirFlags(args []string) error {
+	if pkgManifestDir == "" {
+		if pkgManifestResume || pkgManifestRunDir != "" {
+			return errors.New("the flags --resume and --run-dir can only be used with --dir")
+		}
+		return nil
+	}
+
+	if len(args) != 0 || pkgManifestFile != "" || pkgManifestLocal {
+		return errors.New("cannot combine --dir with a package manifest, --file or --local")
+	}
+	return nil
+}
+
+func defaultScanRunDir(dir string) string {
+	return filepath.Join(dir, ".lacework-scan")
+}
+
+func scanPkgManifestDir(dir, runDir string, resume bool) error {
+	if runDir == "" {
+		runDir = defaultScanRunDir(dir)
+	}
+
+	run := scanDirRun{Dir: dir, RunDir: runDir, Resume: resume, scan: scanPkgManifest}
+	if cli.LwApi != nil {
+		run.pool = cli.LwApi.WorkerPool()
+	}
+	results, err := run.Run()
+	if err != nil {
+		return err
+	}
+
+	failed := countScanDirResults(results, scanDirStatusFailed)
+	if cli.StructuredOutput() {
+		if err := cli.OutputStructured(results); err != nil {
+			return err
+		}
+	} else {
+		cli.OutputHuman(buildScanDirReport(results, runDir))
+		if failed != 0 && !cli.Quiet() {
+			cli.OutputHuman("\nUse the flag --resume to retry only the failed manifests.\n")
+		}
+	}
+
+	if failed != 0 {
+		return errors.Errorf("unable to scan %d package manifests", failed)
+	}
+	return nil
+}
+
+// Run scans every package manifest of the directory, when resuming, the
+// manifests with results in the run directory are loaded instead of being
+// scanned again, failed manifests have no results so they are always retried,
+// the manifests are scanned through the worker pool of the run, the order of
+// the results is the one of the manifests, regardless of when they finished
+func (run scanDirRun) Run() ([]scanDirResult, error) {
+	manifests, err := listPkgManifestFiles(run.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) == 0 {
+		return nil, errors.Errorf("no package manifests found in directory %s", run.Dir)
+	}
+
+	if run.Resume {
+		previous, err := run.loadFailures()
+		if err != nil {
+			return nil, err
+		}
+		cli.Log.Debugw("resuming scan", "run_dir", run.RunDir, "failed", len(previous))
+	}
+
+	var (
+		results = make([]scanDirResult, len(manifests))
+		pending = make([]int, 0, len(manifests))
+	)
+	for i, manifest := range manifests {
+		if run.Resume {
+			if cached, ok := run.loadResult(manifest); ok {
+				results[i] = scanDirResult{
+					Manifest: manifest, Status: scanDirStatusCached, Result: cached,
+				}
+				continue
+			}
+		}
+		pending = append(pending, i)
+	}
+
+	pool := run.pool
+	if pool == nil {
+		pool = api.NewWorkerPool(1)
+	}
+
+	var (
+		mu          sync.Mutex
+		done        = 0
+		failures    = map[string]string{}
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+	defer cancel()
+
+	if len(pending) != 0 {
+		cli.StepProgress(" Scanning", 0, len(pending))
+	}
+	errs := pool.Run(ctx, len(pending), func(_ context.Context, p int) error {
+		var (
+			manifest      = manifests[pending[p]]
+			response, err = run.scanManifest(manifest)
+			result        = scanDirResult{Manifest: manifest, Status: scanDirStatusScanned}
+		)
+		if err != nil {
+			cli.Log.Debugw("unable to scan manifest", "manifest", manifest, "error", err)
+			result.Status = scanDirStatusFailed
+			result.Error = err.Error()
+		} else if err := run.storeResult(manifest, response); err != nil {
+			// stop scanning, the results could not be resumed
+			cancel()
+			return err
+		} else {
+			result.Result = &response
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if result.Status == scanDirStatusFailed {
+			failures[manifest] = result.Error
+		}
+		results[pending[p]] = result
+		done++
+		cli.StepProgress(" Scanning", done, len(pending))
+		return nil
+	})
+	cli.StopProgress()
+
+	for _, err := range errs {
+		if err != nil && err != context.Canceled {
+			return nil, err
+		}
+	}
+
+	if err := run.storeFailures(failures); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (run scanDirRun) scanManifest(manifest string) (api.HostVulnScanPkgManifestResponse, error) {
+	pkgManifest, err := ioutil.ReadFile(filepath.Join(run.Dir, manifest))
+	if err != nil {
+		return api.HostVulnScanPkgManifestResponse{}, errors.Wrap(err, "unable to read file")
+	}
+	return run.scan(string(pkgManifest))
+}
+
+func (run scanDirRun) resultPath(manifest string) string {
+	return filepath.Join(run.RunDir, "results", manifest)
+}
+
+func (run scanDirRun) failuresPath() string {
+	return filepath.Join(run.RunDir, "failures.json")
+}
+
+// loadResult returns the stored result of a manifest, a missing or unreadable
+// result, like one from an interrupted write, means it needs to be scanned
+func (run scanDirRun) loadResult(manifest string) (*api.HostVulnScanPkgManifestResponse, bool) {
+	data, err := ioutil.ReadFile(run.resultPath(manifest))
+	if err != nil {
+		return nil, false
+	}
+
+	response := new(api.HostVulnScanPkgManifestResponse)
+	if err := json.Unmarshal(data, response); err != nil {
+		cli.Log.Debugw("ignoring invalid result", "manifest", manifest, "error", err)
+		return nil, false
+	}
+	return response, true
+}
+
+func (run scanDirRun) storeResult(manifest string, response api.HostVulnScanPkgManifestResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode scan result")
+	}
+	return writeScanRunFile(run.resultPath(manifest), data)
+}
+
+func (run scanDirRun) loadFailures() (map[string]string, error) {
+	failures := map[string]string{}
+
+	data, err := ioutil.ReadFile(run.failuresPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return failures, nil
+		}
+		return failures, errors.Wrap(err, "unable to read failures file")
+	}
+
+	if err := json.Unmarshal(data, &failures); err != nil {
+		return failures, errors.Wrapf(err, "unable to decode failures file %s", run.failuresPath())
+	}
+	return failures, nil
+}
+
+func (run scanDirRun) storeFailures(failures map[string]string) error {
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to encode failures file")
+	}
+	return writeScanRunFile(run.failuresPath(), append(data, '\n'))
+}
+
+// writeScanRunFile writes into a temporary file and renames it, so that
+// an interrupted scan never leaves a partially written file behind
+func writeScanRunFile(filePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return errors.Wrap(err, "unable to create run directory")
+	}
+
+	tmpPath := filePath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return errors.Wrap(err, "unable to write scan results")
+	}
+	return errors.Wrap(os.Rename(tmpPath, filePath), "unable to write scan results")
+}
+
+// listPkgManifestFiles returns the names of the *.json files of a directory,
+// sub-directories, like the default run directory, are not scanned
+func listPkgManifestFiles(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read directory")
+	}
+
+	manifests := []string{}
+	for _, file := range files {
+		if file.Mode().IsRegular() && strings.HasSuffix(file.Name(), ".json") {
+			manifests = append(manifests, file.Name())
+		}
+	}
+	sort.Strings(manifests)
+	return manifests, nil
+}
+
+func countScanDirResults(results []scanDirResult, status string) int {
+	count := 0
+	for _, result := range results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func buildScanDirReport(results []scanDirResult, runDir string) string {
+	var (
+		mainReport = &strings.Builder{}
+		t          = tablewriter.NewWriter(mainReport)
+	)
+
+	t.SetHeader([]string{
+		"Manifest", "Status", "Critical", "High", "Medium", "Low", "Negligible", "Fixable",
+	})
+	t.SetBorder(false)
+	t.SetAutoWrapText(false)
+	for _, result := range results {
+		if result.Result == nil {
+			t.Append([]string{result.Manifest, result.Status, "", "", "", "", "", ""})
+			continue
+		}
+
+		counts := result.Result.VulnerabilityCounts()
+		fixable := counts.CritFixable + counts.HighFixable + counts.MedFixable +
+			counts.LowFixable + counts.NegFixable
+		t.Append([]string{
+			result.Manifest,
+			result.Status,
+			fmt.Sprint(counts.Critical),
+			fmt.Sprint(counts.High),
+			fmt.Sprint(counts.Medium),
+			fmt.Sprint(counts.Low),
+			fmt.Sprint(counts.Negligible),
+			fmt.Sprint(fixable),
+		})
+	}
+	t.Render()
+
+	mainReport.WriteString(fmt.Sprintf(
+		"\n%d manifests scanned, %d loaded from previous results, %d failed.\n",
+		countScanDirResults(results, scanDirStatusScanned),
+		countScanDirResults(results, scanDirStatusCached),
+		countScanDirResults(results, scanDirStatusFailed),
+	))
+
+	for _, result := range results {
+		if result.Status == scanDirStatusFailed {
+			mainReport.WriteString(fmt.Sprintf("  %s: %s\n", result.Manifest, result.Error))
+		}
+	}
+
+	mainReport.WriteString(fmt.Sprintf("\nResults stored in %s\n", runDir))
+	return mainReport.String()
+}
@@ -0,0 +1,210 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestScanDirRunResume(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+	cli.NonInteractive()
+
+	dir, err := ioutil.TempDir("", "lacework-scan-dir")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"host-a.json", "host-b.json", "host-c.json", "notes.txt"} {
+		require.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0600))
+	}
+
+	var (
+		scanned = []string{}
+		failing = map[string]bool{"host-b.json": true}
+		run     = scanDirRun{
+			Dir:    dir,
+			RunDir: defaultScanRunDir(dir),
+			scan: func(manifest string) (api.HostVulnScanPkgManifestResponse, error) {
+				scanned = append(scanned, manifest)
+				if failing[manifest] {
+					return api.HostVulnScanPkgManifestResponse{}, errors.New("rate limit exceeded")
+				}
+				return api.HostVulnScanPkgManifestResponse{Ok: true, Message: manifest}, nil
+			},
+		}
+	)
+
+	results, err := run.Run()
+	require.Nil(t, err)
+	assert.Equal(t, []string{"host-a.json", "host-b.json", "host-c.json"}, scanned)
+	assert.Equal(t, 2, countScanDirResults(results, scanDirStatusScanned))
+	assert.Equal(t, 1, countScanDirResults(results, scanDirStatusFailed))
+
+	failures, err := run.loadFailures()
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"host-b.json": "rate limit exceeded"}, failures)
+
+	report := buildScanDirReport(results, run.RunDir)
+	assert.Contains(t, report, "2 manifests scanned, 0 loaded from previous results, 1 failed.")
+	assert.Contains(t, report, "host-b.json: rate limit exceeded")
+
+	// resuming only retries the failed manifests
+	scanned = []string{}
+	failing = map[string]bool{}
+	run.Resume = true
+	results, err = run.Run()
+	require.Nil(t, err)
+	assert.Equal(t, []string{"host-b.json"}, scanned)
+	assert.Equal(t, 1, countScanDirResults(results, scanDirStatusScanned))
+	assert.Equal(t, 2, countScanDirResults(results, scanDirStatusCached))
+	if assert.Len(t, results, 3) && assert.NotNil(t, results[0].Result) {
+		assert.Equal(t, "host-a.json", results[0].Result.Message)
+	}
+
+	failures, err = run.loadFailures()
+	require.Nil(t, err)
+	assert.Empty(t, failures)
+
+	// without resuming, every manifest is scanned again
+	scanned = []string{}
+	run.Resume = false
+	_, err = run.Run()
+	require.Nil(t, err)
+	assert.Len(t, scanned, 3)
+}
+
+func TestScanDirRunInvalidResult(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+	cli.NonInteractive()
+
+	dir, err := ioutil.TempDir("", "lacework-scan-dir")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "host.json"), []byte("{}"), 0600))
+
+	scans := 0
+	run := scanDirRun{Dir: dir, RunDir: filepath.Join(dir, "run"), Resume: true,
+		scan: func(string) (api.HostVulnScanPkgManifestResponse, error) {
+			scans++
+			return api.HostVulnScanPkgManifestResponse{Ok: true}, nil
+		},
+	}
+
+	// a partially written result is scanned again
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "run", "results"), 0700))
+	require.Nil(t, ioutil.WriteFile(run.resultPath("host.json"), []byte(`{"data":[`), 0600))
+
+	_, err = run.Run()
+	require.Nil(t, err)
+	assert.Equal(t, 1, scans)
+}
+
+func TestScanDirRunConcurrency(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+	cli.NonInteractive()
+
+	dir, err := ioutil.TempDir("", "lacework-scan-dir")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	manifests := []string{"host-a.json", "host-b.json", "host-c.json", "host-d.json", "host-e.json"}
+	for _, name := range manifests {
+		require.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0600))
+	}
+
+	var (
+		mu      sync.Mutex
+		running int
+		maxSeen int
+		run     = scanDirRun{Dir: dir, RunDir: defaultScanRunDir(dir), pool: api.NewWorkerPool(2),
+			scan: func(manifest string) (api.HostVulnScanPkgManifestResponse, error) {
+				mu.Lock()
+				running++
+				if running > maxSeen {
+					maxSeen = running
+				}
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return api.HostVulnScanPkgManifestResponse{Ok: true, Message: manifest}, nil
+			},
+		}
+	)
+
+	results, err := run.Run()
+	require.Nil(t, err)
+	assert.Equal(t, 2, maxSeen, "the manifests should be scanned two at a time")
+	if assert.Len(t, results, len(manifests)) {
+		for i, result := range results {
+			assert.Equal(t, manifests[i], result.Manifest, "the results should keep the order of the manifests")
+			assert.Equal(t, scanDirStatusScanned, result.Status)
+		}
+	}
+}
+
+func TestScanDirRunEmptyDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lacework-scan-dir")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = scanDirRun{Dir: dir}.Run()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "no package manifests found in directory")
+	}
+}
+
+func TestValidateScanDirFlags(t *testing.T) {
+	defer func(dir, runDir, file string, resume, local bool) {
+		pkgManifestDir, pkgManifestRunDir, pkgManifestFile = dir, runDir, file
+		pkgManifestResume, pkgManifestLocal = resume, local
+	}(pkgManifestDir, pkgManifestRunDir, pkgManifestFile, pkgManifestResume, pkgManifestLocal)
+
+	pkgManifestDir, pkgManifestResume = "", true
+	assert.EqualError(t, validateScanDirFlags(nil),
+		"the flags --resume and --run-dir can only be used with --dir")
+
+	pkgManifestDir, pkgManifestLocal = "manifests", true
+	assert.EqualError(t, validateScanDirFlags(nil),
+		"cannot combine --dir with a package manifest, --file or --local")
+
+	pkgManifestLocal = false
+	assert.EqualError(t, validateScanDirFlags([]string{"{}"}),
+		"cannot combine --dir with a package manifest, --file or --local")
+	assert.Nil(t, validateScanDirFlags(nil))
+}
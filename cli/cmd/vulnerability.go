@@ -29,6 +29,7 @@ import (
 	flag "github.com/spf13/pflag"
 
 	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/internal/array"
 )
 
 var (
@@ -67,9 +68,80 @@ var (
 		// show only hosts that are offline
 		Offline bool
 
+		// show only hosts with the specified machine statuses
+		MachineStatuses []string
+
+		// show only hosts with machine tags matching the key=value filters
+		HostFilters []string
+
+		// show only hosts with the specified architectures
+		Archs []string
+
 		// filter assessments for specific repositories
 		Repositories []string
-	}{PollInterval: time.Second * 5}
+
+		// sort host vulnerabilities by severity (default) or by cvss score
+		SortBy string
+
+		// generate a Software Bill of Materials (SBOM) in the specified format
+		Sbom string
+
+		// disable the de-duplication of identical CVE/package rows
+		NoDedupe bool
+
+		// show only vulnerabilities of packages from the specified namespaces
+		Namespaces []string
+
+		// group the vulnerabilities of a host assessment by package
+		ByPackage bool
+
+		// show only vulnerabilities with the specified statuses
+		VulnStatuses []string
+
+		// show only the vulnerabilities with the specified CVE ids
+		CVEs []string
+
+		// select and order the columns of the host CVE tables
+		Columns []string
+
+		// show only vulnerabilities with a CVSS score at or above this threshold
+		MinScore float64
+
+		// keep the vulnerabilities without a CVSS score when filtering by score
+		IncludeUnscored bool
+
+		// show a summary of the number of vulnerabilities per severity
+		Stats bool
+
+		// print only the number of fixable CVEs of a host assessment
+		FixableCount bool
+
+		// write a JUnit report of a host assessment to the specified file
+		JUnit string
+
+		// severity threshold to fail the test cases of a JUnit report
+		JUnitThreshold string
+
+		// write the vulnerability counts of a host assessment as Prometheus metrics
+		Prometheus string
+
+		// cache the host assessments on disk to avoid fetching them on every run
+		Cache bool
+
+		// time that the host assessments are cached
+		CacheTTL time.Duration
+
+		// do not use the cache of host assessments, overrides Cache
+		NoCache bool
+	}{
+		PollInterval:   time.Second * 5,
+		SortBy:         "severity",
+		JUnitThreshold: "high",
+		CacheTTL:       defaultHostAssessmentCacheTTL,
+	}
+
+	// hostVulnSortByFields is the list of valid fields to sort host vulnerabilities
+	hostVulnSortByFields = []string{"severity", "score"}
 
 	// vulnerability represents the vulnerability command that holds both, the host
 	// and container sub-commands
@@ -125,7 +197,7 @@ from hosts with the Lacework datacollector agent installed.
 		RunE: func(_ *cobra.Command, args []string) error {
 			cli.OutputHuman("(DEPRECATED) This command has been moved.\n")
 			cli.OutputHuman("(DEPRECATED) Use now the command 'lacework vulnerability container show-assessment %s'\n\n", args[0])
-			if cli.JSONOutput() {
+			if cli.StructuredOutput() {
 				cli.Log.Warnw("this command has been deprecated", "moved_to", "lacework vulnerability container show-assessment")
 			}
 			return showContainerAssessmentsWithSha256(args[0])
@@ -151,7 +223,7 @@ from hosts with the Lacework datacollector agent installed.
 				"(DEPRECATED) Use now the command 'lacework vulnerability container scan %s %s %s'\n\n",
 				args[0], args[1], args[2],
 			)
-			if cli.JSONOutput() {
+			if cli.StructuredOutput() {
 				cli.Log.Warnw("this command has been deprecated", "moved_to", "lacework vulnerability container scan")
 			}
 			return requestOnDemandContainerVulnerabilityScan(args)
@@ -167,7 +239,7 @@ from hosts with the Lacework datacollector agent installed.
 		RunE: func(_ *cobra.Command, args []string) error {
 			cli.OutputHuman("(DEPRECATED) This command has been moved.\n")
 			cli.OutputHuman("(DEPRECATED) Use now the command 'lacework vulnerability container scan-status %s'\n\n", args[0])
-			if cli.JSONOutput() {
+			if cli.StructuredOutput() {
 				cli.Log.Warnw("this command has been deprecated", "moved_to", "lacework vulnerability container scan-status")
 			}
 			return checkOnDemandContainerVulnerabilityStatus(args[0])
@@ -270,6 +342,50 @@ func setActiveFlag(cmds ...*flag.FlagSet) {
 	}
 }
 
+func setVulnStatusFlag(cmds ...*flag.FlagSet) {
+	for _, cmd := range cmds {
+		if cmd != nil {
+			cmd.StringSliceVar(&vulCmdState.VulnStatuses, "status", []string{},
+				"only show vulnerabilities with the specified status (e.g. Active,Fixed,Reopened)",
+			)
+		}
+	}
+}
+
+func setSortByFlag(cmds ...*flag.FlagSet) {
+	for _, cmd := range cmds {
+		if cmd != nil {
+			cmd.StringVar(&vulCmdState.SortBy, "sort-by", "severity",
+				fmt.Sprintf("sort vulnerabilities by field (%s)",
+					strings.Join(hostVulnSortByFields, ", ")),
+			)
+		}
+	}
+}
+
+// validateSortByFlag verifies that the provided --sort-by field is supported
+func validateSortByFlag() error {
+	if !array.ContainsStr(hostVulnSortByFields, vulCmdState.SortBy) {
+		return errors.Errorf("the sort field %s is not valid, use one of %s",
+			vulCmdState.SortBy, strings.Join(hostVulnSortByFields, ", "),
+		)
+	}
+	return nil
+}
+
+// validateMinScoreFlags verifies that the CVSS score threshold provided by
+// the flag --min-score is a valid score, between 0 and 10
+func validateMinScoreFlags() error {
+	if vulCmdState.MinScore < 0 || vulCmdState.MinScore > 10 {
+		return errors.Errorf("the minimum score must be between 0 and 10, provided: %g",
+			vulCmdState.MinScore)
+	}
+	if vulCmdState.IncludeUnscored && vulCmdState.MinScore == 0 {
+		return errors.New("the flag --include-unscored can only be used with --min-score")
+	}
+	return nil
+}
+
 func pollScanStatus(requestID string) error {
 	cli.StartProgress(" Scan running...")
 
@@ -304,8 +420,8 @@ func pollScanStatus(requestID string) error {
 			continue
 		}
 
-		if cli.JSONOutput() {
-			return cli.OutputJSON(assessment)
+		if cli.StructuredOutput() {
+			return cli.OutputStructured(assessment)
 		}
 
 		cli.StopProgress()
@@ -352,21 +468,28 @@ For more information about supported distributions, visit:
 	}
 }
 
-func severityOrder(severity string) int {
-	switch strings.ToLower(severity) {
-	case "critical":
-		return 1
-	case "high":
-		return 2
-	case "medium":
-		return 3
-	case "low":
-		return 4
-	case "info":
-		return 5
-	default:
-		return 6
+// cvssScoreToFloat parses the provided CVSS score, if the score is empty
+// or it can't be parsed, the second returned value will be false
+func cvssScoreToFloat(score string) (float64, bool) {
+	if score == "" {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(score, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// cvssScoreGreater returns true if the score a is greater than the score b,
+// empty or unparseable scores are always pushed to the bottom
+func cvssScoreGreater(a, b string) bool {
+	scoreA, okA := cvssScoreToFloat(a)
+	scoreB, okB := cvssScoreToFloat(b)
+	if okA != okB {
+		return okA
 	}
+	return scoreA > scoreB
 }
 
 func byteCountBinary(b int64) string {
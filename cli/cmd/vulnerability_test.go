@@ -0,0 +1,588 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package cmd
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/api"
+	"github.com/lacework/go-sdk/lwlogger"
+)
+
+func TestCvssScoreGreater(t *testing.T) {
+	scores := []string{"", "5.0", "foo", "9.8", "7.5", "10"}
+	sort.Slice(scores, func(i, j int) bool {
+		return cvssScoreGreater(scores[i], scores[j])
+	})
+
+	assert.Equal(t, []string{"10", "9.8", "7.5", "5.0"}, scores[:4],
+		"scores should be sorted in descending order")
+	assert.ElementsMatch(t, []string{"", "foo"}, scores[4:],
+		"unparseable scores should be pushed to the bottom")
+}
+
+func TestFilterHostVulnCVEsByNamespaces(t *testing.T) {
+	defer func(namespaces []string) { vulCmdState.Namespaces = namespaces }(vulCmdState.Namespaces)
+
+	cves := []api.HostVulnCVE{
+		{ID: "CVE-1", Packages: []api.HostVulnPackage{
+			{Name: "openssl", Namespace: "ubuntu:18.04"},
+			{Name: "openssl", Namespace: "centos:7"},
+		}},
+		{ID: "CVE-2", Packages: []api.HostVulnPackage{
+			{Name: "bash", Namespace: "centos:7"},
+		}},
+		{ID: "CVE-3", Packages: []api.HostVulnPackage{
+			{Name: "curl", Namespace: "debian:10"},
+		}},
+	}
+
+	vulCmdState.Namespaces = []string{}
+	filtered, filteredOut := filterHostVulnCVEsByNamespaces(cves)
+	assert.Equal(t, cves, filtered)
+	assert.Equal(t, 0, filteredOut)
+
+	vulCmdState.Namespaces = []string{"Ubuntu:18.04"}
+	filtered, filteredOut = filterHostVulnCVEsByNamespaces(cves)
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, "CVE-1", filtered[0].ID)
+		assert.Equal(t, []api.HostVulnPackage{{Name: "openssl", Namespace: "ubuntu:18.04"}},
+			filtered[0].Packages)
+	}
+	assert.Equal(t, 3, filteredOut)
+
+	vulCmdState.Namespaces = []string{"ubuntu:18.04", "debian:10"}
+	filtered, filteredOut = filterHostVulnCVEsByNamespaces(cves)
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, "CVE-1", filtered[0].ID)
+		assert.Equal(t, "CVE-3", filtered[1].ID)
+	}
+	assert.Equal(t, 2, filteredOut)
+	assert.Len(t, cves[0].Packages, 2, "the original CVEs should not be modified")
+}
+
+func TestHostVulnPackagesTableForHostView(t *testing.T) {
+	defer func(fixable bool) { vulCmdState.Fixable = fixable }(vulCmdState.Fixable)
+
+	cves := []api.HostVulnCVE{
+		{ID: "CVE-2", Packages: []api.HostVulnPackage{
+			{Name: "openssl", Version: "1.1.1", Severity: "Medium", FixedVersion: "1.1.1g"},
+			{Name: "bash", Version: "4.4", Severity: "Low"},
+		}},
+		{ID: "CVE-1", Packages: []api.HostVulnPackage{
+			{Name: "openssl", Version: "1.1.1", Severity: "High", FixedVersion: "1.1.1h"},
+		}},
+		{ID: "CVE-3", Packages: []api.HostVulnPackage{
+			{Name: "curl", Version: "7.58", Severity: "High", FixedVersion: "7.68"},
+		}},
+	}
+
+	vulCmdState.Fixable = false
+	assert.Equal(t, [][]string{
+		{"openssl", "1.1.1", "1.1.1g, 1.1.1h", "High", "CVE-1, CVE-2"},
+		{"curl", "7.58", "7.68", "High", "CVE-3"},
+		{"bash", "4.4", "", "Low", "CVE-2"},
+	}, hostVulnPackagesTableForHostView(cves))
+
+	vulCmdState.Fixable = true
+	assert.Equal(t, [][]string{
+		{"openssl", "1.1.1", "1.1.1g, 1.1.1h", "High", "CVE-1, CVE-2"},
+		{"curl", "7.58", "7.68", "High", "CVE-3"},
+	}, hostVulnPackagesTableForHostView(cves))
+}
+
+func TestFilterHostVulnCVEsByIDs(t *testing.T) {
+	cves := []api.HostVulnCVE{{ID: "CVE-2021-1"}, {ID: "CVE-2021-2"}, {ID: "CVE-2021-3"}}
+
+	assert.Equal(t, cves, filterHostVulnCVEsByIDs(cves, nil))
+	assert.Equal(t,
+		[]api.HostVulnCVE{{ID: "CVE-2021-1"}, {ID: "CVE-2021-3"}},
+		filterHostVulnCVEsByIDs(cves, []string{"cve-2021-3", "CVE-2021-1"}),
+	)
+	assert.Empty(t, filterHostVulnCVEsByIDs(cves, []string{"CVE-2020-9"}))
+}
+
+func TestFilterHostVulnCVEsByStatuses(t *testing.T) {
+	defer func(statuses []string) { vulCmdState.VulnStatuses = statuses }(vulCmdState.VulnStatuses)
+
+	cves := []api.HostVulnCVE{
+		{ID: "CVE-1", Packages: []api.HostVulnPackage{
+			{Name: "openssl", Status: "Active"},
+			{Name: "curl", Status: "Fixed"},
+		}},
+		{ID: "CVE-2", Packages: []api.HostVulnPackage{
+			{Name: "bash", VulnerabilityStatus: "Reopened"},
+		}},
+		{ID: "CVE-3", Packages: []api.HostVulnPackage{
+			{Name: "sudo", Status: "Fixed"},
+		}},
+	}
+
+	vulCmdState.VulnStatuses = []string{}
+	filtered, filteredOut := filterHostVulnCVEsByStatuses(cves)
+	assert.Equal(t, cves, filtered)
+	assert.Equal(t, 0, filteredOut)
+
+	vulCmdState.VulnStatuses = []string{"active", "Reopened"}
+	filtered, filteredOut = filterHostVulnCVEsByStatuses(cves)
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, []api.HostVulnPackage{{Name: "openssl", Status: "Active"}},
+			filtered[0].Packages)
+		assert.Equal(t, "CVE-2", filtered[1].ID)
+	}
+	assert.Equal(t, 2, filteredOut)
+
+	vulCmdState.VulnStatuses = []string{"Fixed"}
+	filtered, _ = filterHostVulnCVEsByStatuses(cves)
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, "CVE-1", filtered[0].ID)
+		assert.Equal(t, "CVE-3", filtered[1].ID)
+	}
+}
+
+func TestHostVulnCVEsToTableQuiet(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	defer func(active, fixable bool) {
+		vulCmdState.Active = active
+		vulCmdState.Fixable = fixable
+	}(vulCmdState.Active, vulCmdState.Fixable)
+
+	cves := []api.HostVulnCVE{
+		{ID: "CVE-1", Packages: []api.HostVulnPackage{
+			{Name: "openssl", Severity: "High", Status: "Active"},
+		}},
+	}
+
+	vulCmdState.Active = false
+	vulCmdState.Fixable = false
+	assert.Contains(t, hostVulnCVEsToTable(cves), "Try adding '--active'")
+
+	cli.quiet = true
+	report := hostVulnCVEsToTable(cves)
+	assert.Contains(t, report, "CVE-1")
+	assert.NotContains(t, report, "Try adding")
+}
+
+func TestSelectHostVulnCVEsTableColumns(t *testing.T) {
+	defer func(columns []string, packages bool) {
+		vulCmdState.Columns = columns
+		vulCmdState.Packages = packages
+	}(vulCmdState.Columns, vulCmdState.Packages)
+
+	rows := [][]string{
+		{"CVE-1", "High", "7.5", "openssl", "1.0", "1.1", "ubuntu:18.04", "3", "ACTIVE", "Active"},
+	}
+
+	vulCmdState.Columns = []string{}
+	headers, selected := selectHostVulnCVEsTableColumns(hostVulnCVEsTableHeaders, rows)
+	assert.Equal(t, hostVulnCVEsTableHeaders, headers)
+	assert.Equal(t, rows, selected)
+
+	vulCmdState.Columns = []string{"cve", "FIX", " status"}
+	assert.Nil(t, validateColumnsFlag(hostVulnCVEsTableHeaders))
+	headers, selected = selectHostVulnCVEsTableColumns(hostVulnCVEsTableHeaders, rows)
+	assert.Equal(t, []string{"CVE", "Fix Version", "Vuln Status"}, headers)
+	assert.Equal(t, [][]string{{"CVE-1", "1.1", "Active"}}, selected)
+
+	// the table of a host assessment has no hosts column
+	vulCmdState.Columns = []string{"cve", "hosts"}
+	assert.Nil(t, validateColumnsFlag(hostVulnCVEsTableHeaders))
+	err := validateColumnsFlag(hostVulnHostAssessmentCVEsTableHeaders)
+	if assert.NotNil(t, err) {
+		assert.Equal(t,
+			"unknown column 'hosts', use one of cve, severity, score, package, version, fix, pkg_status, status",
+			err.Error())
+	}
+
+	vulCmdState.Columns = []string{"cve", "cve"}
+	err = validateColumnsFlag(hostVulnCVEsTableHeaders)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "the column 'cve' was selected more than once", err.Error())
+	}
+
+	vulCmdState.Columns = []string{"cve"}
+	vulCmdState.Packages = true
+	err = validateColumnsFlag(hostVulnCVEsTableHeaders)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "cannot combine --packages with --columns", err.Error())
+	}
+}
+
+func TestFilterHostVulnCVEsByScore(t *testing.T) {
+	defer func(minScore float64, includeUnscored bool) {
+		vulCmdState.MinScore = minScore
+		vulCmdState.IncludeUnscored = includeUnscored
+	}(vulCmdState.MinScore, vulCmdState.IncludeUnscored)
+
+	cves := []api.HostVulnCVE{
+		{ID: "CVE-1", Packages: []api.HostVulnPackage{
+			{Name: "openssl", CvssScore: "9.8"},
+			{Name: "curl", CvssScore: "5.3"},
+		}},
+		{ID: "CVE-2", Packages: []api.HostVulnPackage{
+			{Name: "bash", CvssScore: "7.0"},
+		}},
+		{ID: "CVE-3", Packages: []api.HostVulnPackage{
+			{Name: "sudo", CvssScore: ""},
+			{Name: "vim", CvssScore: "n/a"},
+		}},
+	}
+
+	vulCmdState.MinScore = 0
+	filtered, filteredOut := filterHostVulnCVEsByScore(cves)
+	assert.Equal(t, cves, filtered)
+	assert.Equal(t, 0, filteredOut)
+
+	vulCmdState.MinScore = 7.0
+	filtered, filteredOut = filterHostVulnCVEsByScore(cves)
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, []api.HostVulnPackage{{Name: "openssl", CvssScore: "9.8"}},
+			filtered[0].Packages)
+		assert.Equal(t, "CVE-2", filtered[1].ID, "the threshold should be inclusive")
+	}
+	assert.Equal(t, 3, filteredOut)
+
+	vulCmdState.IncludeUnscored = true
+	filtered, _ = filterHostVulnCVEsByScore(cves)
+	if assert.Len(t, filtered, 3) {
+		assert.Equal(t, "CVE-3", filtered[2].ID)
+		assert.Len(t, filtered[2].Packages, 2)
+	}
+}
+
+func TestValidateMinScoreFlags(t *testing.T) {
+	defer func(minScore float64, includeUnscored bool) {
+		vulCmdState.MinScore = minScore
+		vulCmdState.IncludeUnscored = includeUnscored
+	}(vulCmdState.MinScore, vulCmdState.IncludeUnscored)
+
+	vulCmdState.IncludeUnscored = false
+	for _, score := range []float64{0, 7.5, 10} {
+		vulCmdState.MinScore = score
+		assert.Nil(t, validateMinScoreFlags())
+	}
+
+	vulCmdState.MinScore = 11
+	if err := validateMinScoreFlags(); assert.NotNil(t, err) {
+		assert.Equal(t, "the minimum score must be between 0 and 10, provided: 11", err.Error())
+	}
+
+	vulCmdState.MinScore = 0
+	vulCmdState.IncludeUnscored = true
+	if err := validateMinScoreFlags(); assert.NotNil(t, err) {
+		assert.Equal(t,
+			"the flag --include-unscored can only be used with --min-score", err.Error())
+	}
+}
+
+func TestParseHostTagFilters(t *testing.T) {
+	filters, err := parseHostTagFilters([]string{"OS=Ubuntu", "provider = AWS", "zone="})
+	if assert.Nil(t, err) {
+		assert.Equal(t, []hostTagFilter{
+			{Key: "os", Value: "Ubuntu"},
+			{Key: "provider", Value: "AWS"},
+			{Key: "zone", Value: ""},
+		}, filters)
+	}
+
+	_, err = parseHostTagFilters([]string{"ubuntu"})
+	assert.EqualError(t, err, "invalid filter 'ubuntu', use the format key=value")
+
+	_, err = parseHostTagFilters([]string{"color=blue"})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unknown filter key 'color', valid keys are: account, ami, arch,")
+	}
+}
+
+func TestFilterHostVulnHostsByTags(t *testing.T) {
+	hostWithTags := func(id, os, provider string) api.HostVulnDetail {
+		var host api.HostVulnDetail
+		host.Details.MachineID = id
+		host.Details.Tags.Os = os
+		host.Details.Tags.VmProvider = provider
+		return host
+	}
+	hosts := []api.HostVulnDetail{
+		hostWithTags("1", "Ubuntu", "AWS"),
+		hostWithTags("2", "Ubuntu", "GCE"),
+		hostWithTags("3", "Amzn", "AWS"),
+	}
+
+	filtered, filteredOut := filterHostVulnHostsByTags(hosts, nil)
+	assert.Len(t, filtered, 3)
+	assert.Equal(t, 0, filteredOut)
+
+	filters, err := parseHostTagFilters([]string{"os=ubuntu", "provider=aws"})
+	assert.Nil(t, err)
+	filtered, filteredOut = filterHostVulnHostsByTags(hosts, filters)
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, "1", filtered[0].Details.MachineID)
+	}
+	assert.Equal(t, 2, filteredOut)
+}
+
+func TestFilterHostVulnHostsByArch(t *testing.T) {
+	hostWithArch := func(id, arch string) api.HostVulnDetail {
+		var host api.HostVulnDetail
+		host.Details.MachineID = id
+		host.Details.Tags.Arch = arch
+		return host
+	}
+	hosts := []api.HostVulnDetail{
+		hostWithArch("1", "amd64"),
+		hostWithArch("2", "ARM64"),
+		hostWithArch("3", "x86_64"),
+	}
+
+	filtered, filteredOut := filterHostVulnHostsByArch(hosts, nil)
+	assert.Len(t, filtered, 3)
+	assert.Equal(t, 0, filteredOut)
+
+	filtered, filteredOut = filterHostVulnHostsByArch(hosts, []string{"AMD64", "arm64"})
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, "1", filtered[0].Details.MachineID)
+		assert.Equal(t, "2", filtered[1].Details.MachineID)
+	}
+	assert.Equal(t, 1, filteredOut)
+}
+
+func TestFilterHostVulnHostsOnline(t *testing.T) {
+	defer func(online, offline bool) {
+		vulCmdState.Online, vulCmdState.Offline = online, offline
+	}(vulCmdState.Online, vulCmdState.Offline)
+
+	host := func(id, status string) api.HostVulnDetail {
+		var host api.HostVulnDetail
+		host.Details.MachineID = id
+		host.Details.MachineStatus = status
+		return host
+	}
+	hosts := []api.HostVulnDetail{host("1", "Online"), host("2", "Offline"), host("3", "Online")}
+
+	vulCmdState.Online, vulCmdState.Offline = false, false
+	assert.Empty(t, hostVulnOnlineStatuses())
+
+	vulCmdState.Online = true
+	filtered, filteredOut := filterHostVulnHostsByStatus(hosts, hostVulnOnlineStatuses())
+	assert.Equal(t, 1, filteredOut)
+	assert.Equal(t, 2, buildHostVulnListHostsOutput(filtered).Summary.Total,
+		"the summary should only count the hosts that are online")
+
+	vulCmdState.Online, vulCmdState.Offline = false, true
+	filtered, filteredOut = filterHostVulnHostsByStatus(hosts, hostVulnOnlineStatuses())
+	assert.Equal(t, 2, filteredOut)
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, "2", filtered[0].Details.MachineID)
+	}
+}
+
+func TestValidateHostVulnStatusFlags(t *testing.T) {
+	defer func(statuses []string, online, offline bool) {
+		vulCmdState.MachineStatuses, vulCmdState.Online, vulCmdState.Offline = statuses, online, offline
+	}(vulCmdState.MachineStatuses, vulCmdState.Online, vulCmdState.Offline)
+
+	vulCmdState.MachineStatuses, vulCmdState.Online, vulCmdState.Offline = []string{"Online"}, false, false
+	assert.Nil(t, validateHostVulnStatusFlags())
+
+	vulCmdState.Offline = true
+	err := validateHostVulnStatusFlags()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "cannot combine --status with --online/--offline", err.Error())
+	}
+
+	vulCmdState.MachineStatuses = []string{}
+	assert.Nil(t, validateHostVulnStatusFlags())
+}
+
+func TestFilterHostVulnHostsByStatus(t *testing.T) {
+	host := func(id, arch, status string) api.HostVulnDetail {
+		var host api.HostVulnDetail
+		host.Details.MachineID = id
+		host.Details.MachineStatus = status
+		host.Details.Tags.Arch = arch
+		host.Details.Tags.Os = "Linux"
+		return host
+	}
+	hosts := []api.HostVulnDetail{
+		host("1", "amd64", "Online"),
+		host("2", "amd64", "Offline"),
+		host("3", "arm64", "Online"),
+		host("4", "amd64", "Online"),
+	}
+
+	filtered, filteredOut := filterHostVulnHostsByStatus(hosts, nil)
+	assert.Len(t, filtered, 4)
+	assert.Equal(t, 0, filteredOut)
+
+	// every filter counts only the hosts that it hides
+	filtered, archFilteredOut := filterHostVulnHostsByArch(hosts, []string{"amd64"})
+	filtered, statusFilteredOut := filterHostVulnHostsByStatus(filtered, []string{"ONLINE"})
+	assert.Equal(t, 1, archFilteredOut)
+	assert.Equal(t, 1, statusFilteredOut)
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, "1", filtered[0].Details.MachineID)
+		assert.Equal(t, "4", filtered[1].Details.MachineID)
+	}
+
+	// the structured output only has the filtered hosts
+	output, err := json.Marshal(buildHostVulnListHostsOutput(filtered))
+	if assert.Nil(t, err) {
+		var decoded struct {
+			Summary struct {
+				Total int `json:"total"`
+			} `json:"summary"`
+			Hosts []json.RawMessage `json:"hosts"`
+		}
+		assert.Nil(t, json.Unmarshal(output, &decoded))
+		assert.Equal(t, 2, decoded.Summary.Total)
+		assert.Len(t, decoded.Hosts, 2)
+	}
+}
+
+func TestDedupeHostVulnCVEsRows(t *testing.T) {
+	row := func(namespace, fixedVersion, hosts, status string) []string {
+		return []string{"CVE-1", "High", "7.5", "openssl", "1.0", fixedVersion,
+			namespace, hosts, "ACTIVE", status}
+	}
+
+	cases := []struct {
+		name     string
+		rows     [][]string
+		expected [][]string
+	}{
+		{"no rows", [][]string{}, [][]string{}},
+		{"same vulnerability of a host with multiple architectures",
+			[][]string{row("ubuntu:18.04", "1.1", "3", "New"), row("ubuntu:18.04", "1.1", "5", "New")},
+			[][]string{row("ubuntu:18.04", "1.1", "5", "New")},
+		},
+		{"different namespaces",
+			[][]string{row("ubuntu:18.04", "1.1", "3", "New"), row("debian:10", "1.1", "2", "New")},
+			[][]string{row("ubuntu:18.04", "1.1", "3", "New"), row("debian:10", "1.1", "2", "New")},
+		},
+		{"different fix versions",
+			[][]string{row("ubuntu:18.04", "1.1", "3", "New"), row("ubuntu:18.04", "", "3", "New")},
+			[][]string{row("ubuntu:18.04", "1.1", "3", "New"), row("ubuntu:18.04", "", "3", "New")},
+		},
+		{"different statuses",
+			[][]string{row("ubuntu:18.04", "1.1", "3", "New"), row("ubuntu:18.04", "1.1", "3", "Active")},
+			[][]string{row("ubuntu:18.04", "1.1", "3", "New"), row("ubuntu:18.04", "1.1", "3", "Active")},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, dedupeHostVulnCVEsRows(c.rows))
+		})
+	}
+}
+
+func TestHostVulnCVEsTableForHostViewDedupe(t *testing.T) {
+	defer func(active, fixable, noDedupe bool) {
+		vulCmdState.Active, vulCmdState.Fixable, vulCmdState.NoDedupe = active, fixable, noDedupe
+	}(vulCmdState.Active, vulCmdState.Fixable, vulCmdState.NoDedupe)
+	vulCmdState.Active, vulCmdState.Fixable = false, false
+
+	pkg := func(namespace string) api.HostVulnPackage {
+		return api.HostVulnPackage{Name: "openssl", Version: "1.0", Severity: "High",
+			FixedVersion: "1.1", Namespace: namespace}
+	}
+	cves := []api.HostVulnCVE{
+		{ID: "CVE-1", Packages: []api.HostVulnPackage{pkg("ubuntu:18.04"), pkg("ubuntu:18.04"), pkg("debian:10")}},
+	}
+
+	cases := []struct {
+		name     string
+		noDedupe bool
+		expected int
+	}{
+		{"dedupe", false, 2},
+		{"no dedupe", true, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			vulCmdState.NoDedupe = c.noDedupe
+			assert.Len(t, hostVulnCVEsTableForHostView(cves), c.expected)
+		})
+	}
+}
+
+func TestBuildHostVulnSeverityStats(t *testing.T) {
+	defer func(state cliState) { cli = state }(cli)
+	cli.Log = lwlogger.New("").Sugar()
+	defer func(fixable, noDedupe bool) {
+		vulCmdState.Fixable, vulCmdState.NoDedupe = fixable, noDedupe
+	}(vulCmdState.Fixable, vulCmdState.NoDedupe)
+	vulCmdState.Fixable, vulCmdState.NoDedupe = false, false
+
+	cves := []api.HostVulnCVE{
+		{ID: "CVE-1", Packages: []api.HostVulnPackage{
+			{Name: "openssl", Version: "1.0", Severity: "Critical", FixedVersion: "1.1"},
+			{Name: "openssl", Version: "1.0", Severity: "Critical", FixedVersion: "1.1"},
+		}},
+		{ID: "CVE-2", Packages: []api.HostVulnPackage{
+			{Name: "bash", Version: "4.4", Severity: "High"},
+			{Name: "curl", Version: "7.0", Severity: "Low", FixedVersion: "7.1"},
+		}},
+	}
+
+	stats := buildHostVulnSeverityStats(cves)
+	assert.Equal(t, hostVulnSeverityStats{Critical: 1, High: 1, Low: 1, Total: 3}, stats)
+	assert.Equal(t,
+		"Critical: 1, High: 1, Medium: 0, Low: 1, Negligible: 0 (Total: 3)",
+		stats.String(),
+	)
+
+	vulCmdState.Fixable = true
+	assert.Equal(t,
+		hostVulnSeverityStats{Critical: 1, Low: 1, Total: 2},
+		buildHostVulnSeverityStats(cves),
+	)
+}
+
+func TestBuildHostVulnFixableCount(t *testing.T) {
+	defer func(active bool) { vulCmdState.Active = active }(vulCmdState.Active)
+	vulCmdState.Active = false
+
+	cves := []api.HostVulnCVE{
+		{ID: "CVE-1", Packages: []api.HostVulnPackage{
+			{Name: "openssl", FixedVersion: "1.1", PackageStatus: "ACTIVE"},
+			{Name: "libssl", FixedVersion: "1.1"},
+		}},
+		{ID: "CVE-2", Packages: []api.HostVulnPackage{
+			{Name: "bash", PackageStatus: "ACTIVE"},
+			{Name: "curl", FixedVersion: "7.1"},
+		}},
+		{ID: "CVE-3", Packages: []api.HostVulnPackage{
+			{Name: "zlib"},
+		}},
+	}
+
+	count := buildHostVulnFixableCount(cves)
+	assert.Equal(t, hostVulnFixableCount{Fixable: 2, Total: 3}, count)
+	assert.Equal(t, "Fixable: 2 (Total: 3)", count.String())
+
+	vulCmdState.Active = true
+	assert.Equal(t, hostVulnFixableCount{Fixable: 1, Total: 2}, buildHostVulnFixableCount(cves),
+		"only the packages actively running should be counted")
+
+	assert.Equal(t, hostVulnFixableCount{}, buildHostVulnFixableCount(nil))
+}
@@ -0,0 +1,55 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletionCommandBash(t *testing.T) {
+	out, err, exitcode := LaceworkCLI("completion", "bash")
+	assert.Contains(t, out.String(), "# bash completion for lacework")
+	assert.Empty(t, err.String(), "STDERR should be empty")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+}
+
+func TestCompletionCommandUnknownShell(t *testing.T) {
+	_, err, exitcode := LaceworkCLI("completion", "foo")
+	assert.Contains(t, err.String(), "ERROR invalid argument \"foo\" for \"lacework completion\"")
+	assert.Equal(t, 2, exitcode, "EXITCODE is not the expected one")
+}
+
+func TestCompletionEventSeverities(t *testing.T) {
+	out, err, exitcode := LaceworkCLI("__complete", "event", "list", "--severity", "")
+	assert.Equal(t,
+		"critical\nhigh\nmedium\nlow\ninfo\n:4\n",
+		out.String(),
+		"the event severities completion changed, please update")
+	assert.Contains(t, err.String(), "ShellCompDirectiveNoFileComp")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+}
+
+func TestCompletionHostVulnSortBy(t *testing.T) {
+	out, _, exitcode := LaceworkCLI("__complete", "vulnerability", "host", "list-cves", "--sort-by", "")
+	assert.Equal(t, "severity\nscore\n:4\n", out.String(),
+		"the sort-by completion changed, please update")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+}
@@ -59,14 +59,32 @@ Flags:
   -h, --help   help for compliance
 
 Global Flags:
-  -a, --account string      account subdomain of URL (i.e. <ACCOUNT>.lacework.net)
-  -k, --api_key string      access key id
-  -s, --api_secret string   secret access key
-      --debug               turn on debug logging
-      --json                switch commands output from human-readable to json format
-      --nocolor             turn off colors
-      --noninteractive      turn off interactive mode (disable spinners, prompts, etc.)
-  -p, --profile string      switch between profiles configured at ~/.lacework.toml
+  -a, --account string         account subdomain of URL (i.e. <ACCOUNT>.lacework.net), overrides the account of the profile
+  -k, --api_key string         access key id
+  -s, --api_secret string      secret access key
+      --ca-cert string         path to a PEM file of certificate authorities to trust, like the CA of a TLS-intercepting proxy
+      --concurrency int        number of API requests that bulk operations, like a directory scan, send at once (default 4)
+      --config string          path to the config file, also set with LW_CONFIG (default ~/.lacework.toml)
+      --debug                  turn on debug logging, same as --log-level debug
+      --debug-http             log the API requests and responses (URLs, headers and bodies) with their secrets redacted
+      --insecure-skip-verify   turn off the verification of TLS certificates (insecure, prefer --ca-cert)
+      --json                   (deprecated) alias of --output json
+      --json-compact           print the JSON output in a single line
+      --jsonl                  print lists in the JSON output one element per line (JSON Lines)
+      --log-format string      format of the logs written to stderr: console or json (default "console")
+      --log-level string       level of the logs written to stderr: info or debug
+      --no-color               turn off colors, also set with the environment variable NO_COLOR
+      --no-pager               do not pipe the output through a pager, overrides --paginate
+      --no-retry               turn off retries of API requests on transient errors
+      --nocolor                (deprecated) alias of --no-color
+      --noninteractive         turn off interactive mode (disable spinners, prompts, etc.)
+  -o, --output string          output format of the commands: table, json, csv or yaml (default "table")
+      --paginate               pipe long tables through $PAGER or 'less -R' when the output is a terminal
+  -p, --profile string         switch between profiles configured at ~/.lacework.toml
+  -q, --quiet                  suppress informational output like hints and banners, print only the data
+      --subaccount string      sub-account name inside your organization (org admins only)
+      --timeout string         timeout of the API requests in seconds or as a duration like 2m (default 60s)
+      --timezone string        time zone of the timestamps in the output, an IANA name like America/New_York (default UTC)
 
 Use "lacework compliance [command] --help" for more information about a command.
 `,
@@ -0,0 +1,84 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package integration
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureDefaultCommand(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "default", "dev")
+	assert.Empty(t, errB.String(), "STDERR should be empty")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+	assert.Equal(t, "Profile 'dev' is now the default profile.\n", out.String(),
+		"STDOUT changed, please check")
+
+	laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if assert.Nil(t, err) {
+		assert.Contains(t, string(laceworkTOML), `default_profile = "dev"`)
+	}
+
+	t.Run("subsequent commands use the default profile", func(t *testing.T) {
+		out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "show", "account")
+		assert.Empty(t, errB.String(), "STDERR should be empty")
+		assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+		assert.Equal(t, "dev.example\n", out.String(), "STDOUT changed, please check")
+	})
+
+	t.Run("the flag --profile takes precedence", func(t *testing.T) {
+		out, _, exitcode := LaceworkCLIWithHome(home,
+			"configure", "show", "profile", "--profile", "integration")
+		assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+		assert.Equal(t, "integration\n", out.String(), "STDOUT changed, please check")
+	})
+
+	t.Run("the default profile follows a rename", func(t *testing.T) {
+		out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "rename", "dev", "staging")
+		assert.Empty(t, errB.String(), "STDERR should be empty")
+		assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+		assert.Contains(t, out.String(), "Profile 'staging' is now the default profile.",
+			"STDOUT changed, please check")
+
+		out, _, _ = LaceworkCLIWithHome(home, "configure", "show", "profile")
+		assert.Equal(t, "staging\n", out.String(), "STDOUT changed, please check")
+	})
+}
+
+func TestConfigureDefaultCommandProfileNotFound(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "default", "foo")
+	assert.Empty(t, out.String(), "STDOUT should be empty")
+	assert.Contains(t, errB.String(), "the profile 'foo' could not be found",
+		"STDERR changed, please check")
+	assert.Equal(t, 5, exitcode, "EXITCODE is not the expected one")
+
+	laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if assert.Nil(t, err) {
+		assert.NotContains(t, string(laceworkTOML), "default_profile")
+	}
+}
@@ -0,0 +1,124 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package integration
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createImportTOMLConfig(dir string) string {
+	importFile := path.Join(dir, "other.toml")
+	c := []byte(`[default]
+account = 'test.account'
+api_key = 'INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00'
+api_secret = '_00000000000000000000000000000000'
+
+[dev]
+account = 'dev.other'
+api_key = 'DEVOTHER_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCC000'
+api_secret = '_22222222222222222222222222222222'
+
+[prod]
+account = 'prod.example'
+api_key = 'PROD_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000000'
+api_secret = '_33333333333333333333333333333333'
+`)
+	if err := ioutil.WriteFile(importFile, c, 0600); err != nil {
+		panic(err)
+	}
+	return importFile
+}
+
+func TestConfigureImportCommand(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+	importFile := createImportTOMLConfig(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home,
+		"configure", "import", importFile, "--noninteractive")
+	assert.Empty(t, errB.String(), "STDERR should be empty")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+	assert.Equal(t, `1 profiles imported.
+
+  Added: prod
+  Skipped: default, dev
+`, out.String(), "STDOUT changed, please check")
+
+	laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if assert.Nil(t, err) {
+		assert.Contains(t, string(laceworkTOML), `[prod]
+  account = "prod.example"`)
+		assert.Contains(t, string(laceworkTOML), `[dev]
+  account = "dev.example"`)
+	}
+
+	t.Run("overwrite existing profiles", func(t *testing.T) {
+		out, errB, exitcode := LaceworkCLIWithHome(home,
+			"configure", "import", importFile, "--on-conflict", "overwrite")
+		assert.Empty(t, errB.String(), "STDERR should be empty")
+		assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+		assert.Equal(t, `1 profiles imported.
+
+  Overwritten: dev
+  Skipped: default, prod
+`, out.String(), "STDOUT changed, please check")
+
+		laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+		if assert.Nil(t, err) {
+			assert.Contains(t, string(laceworkTOML), `[dev]
+  account = "dev.other"`)
+		}
+	})
+
+	t.Run("nothing to import", func(t *testing.T) {
+		out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "import", importFile)
+		assert.Empty(t, errB.String(), "STDERR should be empty")
+		assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+		assert.Equal(t, "No profiles were imported.\n\n  Skipped: default, dev, prod\n",
+			out.String(), "STDOUT changed, please check")
+	})
+}
+
+func TestConfigureImportCommandErrors(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	t.Run("file not found", func(t *testing.T) {
+		out, errB, exitcode := LaceworkCLIWithHome(home,
+			"configure", "import", path.Join(home, "foo.toml"))
+		assert.Empty(t, out.String(), "STDOUT should be empty")
+		assert.Contains(t, errB.String(), "unable to load config file",
+			"STDERR changed, please check")
+		assert.Equal(t, 1, exitcode, "EXITCODE is not the expected one")
+	})
+
+	t.Run("invalid --on-conflict", func(t *testing.T) {
+		out, errB, exitcode := LaceworkCLIWithHome(home,
+			"configure", "import", createImportTOMLConfig(home), "--on-conflict", "foo")
+		assert.Empty(t, out.String(), "STDOUT should be empty")
+		assert.Contains(t, errB.String(),
+			"invalid value 'foo' for --on-conflict, use 'keep' or 'overwrite'",
+			"STDERR changed, please check")
+		assert.Equal(t, 1, exitcode, "EXITCODE is not the expected one")
+	})
+}
@@ -19,6 +19,10 @@
 package integration
 
 import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,25 +46,25 @@ func TestConfigureListCommandWithConfig(t *testing.T) {
 		// column 1
 		"> default",
 		"dummy",
-		"DUMMY_1234567890abcdefg",
+		"*******************defg",
 		"*************cret",
 
 		// column 2
 		"dev",
 		"dev.example",
-		"DEVDEV_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000",
+		"***************************************************C000",
 		"*****************************1111",
 
 		// column 3
 		"integration",
 		"integration",
-		"INTEGRATION_3DF1234AABBCCDD5678XXYYZZ1234ABC8BEC6500DC70",
+		"****************************************************DC70",
 		"*****************************4abc",
 
 		// column 3
 		"test",
 		"test.account",
-		"INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+		"***************************************************CC00",
 		"*****************************0000",
 	}
 	t.Run("verify table fields", func(t *testing.T) {
@@ -82,6 +86,31 @@ func TestConfigureListCommandWithoutConfig(t *testing.T) {
 		"EXITCODE is not the expected one")
 }
 
+func TestConfigureListCommandWithInvalidProfile(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	configFile := path.Join(home, ".lacework.toml")
+	c, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		panic(err)
+	}
+	c = append(c, []byte("\n[broken]\naccount = 'broken'\n")...)
+	if err := ioutil.WriteFile(configFile, c, 0644); err != nil {
+		panic(err)
+	}
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "list")
+	assert.Empty(t,
+		errB.String(),
+		"STDERR should be empty")
+	assert.Equal(t, 0, exitcode,
+		"EXITCODE is not the expected one")
+	assert.Contains(t, out.String(), "broken")
+	assert.Contains(t, out.String(), "invalid config: profile 'broken': api_key missing",
+		"STDOUT the problems of the config are missing, please check")
+}
+
 func TestConfigureListCommandWithConfigAndProfile(t *testing.T) {
 	out, err, exitcode := LaceworkCLIWithDummyConfig("configure", "list")
 	assert.Empty(t,
@@ -119,3 +148,23 @@ func TestConfigureListCommandWithConfigAndProfile(t *testing.T) {
 			"STDOUT something intside the table is missing, please check")
 	})
 }
+
+func TestConfigureListCommandJSONLines(t *testing.T) {
+	out, err, exitcode := LaceworkCLIWithDummyConfig("configure", "list", "--jsonl")
+	assert.Empty(t,
+		err.String(),
+		"STDERR should be empty")
+	assert.Equal(t, 0, exitcode,
+		"EXITCODE is not the expected one")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if assert.Len(t, lines, 4, "every profile should be displayed in its own line") {
+		assert.True(t, strings.HasPrefix(lines[0], `{"profile":"default",`))
+	}
+
+	out, err, exitcode = LaceworkCLIWithDummyConfig("configure", "list", "--jsonl", "--output", "yaml")
+	assert.Empty(t, out.String())
+	assert.Contains(t, err.String(),
+		"ERROR the flags --json-compact and --jsonl can only be used with JSON output")
+	assert.Equal(t, 1, exitcode)
+}
@@ -0,0 +1,110 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package integration
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureRenameCommand(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "rename", "dev", "staging")
+	assert.Empty(t, errB.String(), "STDERR should be empty")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+	assert.Equal(t, "Profile 'dev' renamed to 'staging'.\n", out.String(),
+		"STDOUT changed, please check")
+
+	laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if err != nil {
+		panic(err)
+	}
+	assert.NotContains(t, string(laceworkTOML), "[dev]")
+
+	backups, err := filepath.Glob(path.Join(home, ".lacework.toml.bak-*"))
+	if assert.Nil(t, err) && assert.Len(t, backups, 1, "the config file should be backed up") {
+		backupTOML, err := ioutil.ReadFile(backups[0])
+		if assert.Nil(t, err) {
+			assert.Contains(t, string(backupTOML), "[dev]")
+		}
+	}
+	assert.Contains(t, string(laceworkTOML), `[staging]
+  account = "dev.example"
+  api_key = "DEVDEV_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000"
+  api_secret = "_11111111111111111111111111111111"
+`)
+}
+
+func TestConfigureRenameCommandActiveProfile(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "rename", "default", "main")
+	assert.Empty(t, errB.String(), "STDERR should be empty")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+	assert.Contains(t, out.String(), "NOTE: 'default' was the active profile",
+		"STDOUT changed, please check")
+}
+
+func TestConfigureRenameCommandActiveProfileQuiet(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "rename", "default", "main", "--quiet")
+	assert.Empty(t, errB.String(), "STDERR should be empty")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+	assert.Equal(t, "Profile 'default' renamed to 'main'.\n", out.String(),
+		"STDOUT changed, please check")
+}
+
+func TestConfigureRenameCommandErrors(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	t.Run("old profile not found", func(t *testing.T) {
+		out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "rename", "foo", "bar")
+		assert.Empty(t, out.String(), "STDOUT should be empty")
+		assert.Contains(t, errB.String(), "the profile 'foo' could not be found",
+			"STDERR changed, please check")
+		assert.Equal(t, 5, exitcode, "EXITCODE is not the expected one")
+	})
+
+	t.Run("new profile already exists", func(t *testing.T) {
+		out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "rename", "dev", "integration")
+		assert.Empty(t, out.String(), "STDOUT should be empty")
+		assert.Contains(t, errB.String(),
+			"the profile 'integration' already exists. Use --force to overwrite it",
+			"STDERR changed, please check")
+		assert.Equal(t, 1, exitcode, "EXITCODE is not the expected one")
+	})
+
+	t.Run("force overwrite", func(t *testing.T) {
+		_, errB, exitcode := LaceworkCLIWithHome(home,
+			"configure", "rename", "dev", "integration", "--force")
+		assert.Empty(t, errB.String(), "STDERR should be empty")
+		assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+	})
+}
@@ -19,6 +19,7 @@
 package integration
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,11 +30,11 @@ func TestConfigureShowCommandWrongKey(t *testing.T) {
 	assert.Empty(t,
 		out.String(),
 		"STDOUT should be empty")
-	assert.Contains(t, err.String(), "unknown configuration key.",
+	assert.Contains(t, err.String(), "unknown configuration key 'foo'.",
 		"STDERR is not correct, please update")
-	assert.Contains(t, err.String(), "(available: profile, account, api_secret, api_key)",
+	assert.Contains(t, err.String(), "(available: profile, account, subaccount, api_secret, api_key)",
 		"STDERR is not correct, please update")
-	assert.Equal(t, 1, exitcode,
+	assert.Equal(t, 2, exitcode,
 		"EXITCODE is not the expected one")
 }
 
@@ -106,3 +107,58 @@ func TestConfigureShowCommandWithConfigAndProfile(t *testing.T) {
 			"STDERR should be empty")
 	})
 }
+
+func TestConfigureShowCommandProfileDetails(t *testing.T) {
+	out, err, exitcode := LaceworkCLIWithDummyConfig("configure", "show", "--profile", "dev")
+	assert.Empty(t,
+		err.String(),
+		"STDERR should be empty")
+	assert.Equal(t, 0, exitcode,
+		"EXITCODE is not the expected one")
+	assert.Contains(t, out.String(), "dev.example",
+		"STDOUT profile account is missing")
+	assert.Contains(t, out.String(), "DEVDEV_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000",
+		"STDOUT profile api_key is missing")
+	assert.Contains(t, out.String(), "*****************************1111",
+		"STDOUT profile api_secret should be masked")
+
+	out, err, exitcode = LaceworkCLIWithDummyConfig("configure", "show", "--profile", "dev", "--show-secret")
+	assert.Empty(t,
+		err.String(),
+		"STDERR should be empty")
+	assert.Equal(t, 0, exitcode,
+		"EXITCODE is not the expected one")
+	assert.Contains(t, out.String(), "_11111111111111111111111111111111",
+		"STDOUT profile api_secret should be revealed")
+}
+
+func TestConfigureShowCommandProfileNamedAsKey(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	_, _, exitcode := LaceworkCLIWithHome(home, "configure", "rename", "dev", "account")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+
+	out, err, exitcode := LaceworkCLIWithHome(home, "configure", "show", "--profile", "account")
+	assert.Empty(t,
+		err.String(),
+		"STDERR should be empty")
+	assert.Equal(t, 0, exitcode,
+		"EXITCODE is not the expected one")
+	assert.Contains(t, out.String(), "dev.example",
+		"STDOUT profile account is missing")
+
+	out, _, exitcode = LaceworkCLIWithHome(home, "configure", "show", "account", "--profile", "account")
+	assert.Equal(t, 0, exitcode,
+		"EXITCODE is not the expected one")
+	assert.Equal(t, "dev.example\n", out.String(),
+		"the argument should always be a configuration key")
+}
+
+func TestConfigureShowCommandProfileNotFound(t *testing.T) {
+	_, err, exitcode := LaceworkCLIWithDummyConfig("configure", "show", "--profile", "foo")
+	assert.Contains(t, err.String(), "the profile 'foo' could not be found",
+		"STDERR is not correct, please update")
+	assert.Equal(t, 5, exitcode,
+		"EXITCODE is not the expected one")
+}
@@ -19,10 +19,12 @@
 package integration
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -40,8 +42,8 @@ func TestConfigureCommandNonInteractive(t *testing.T) {
 	out, errB, exitcode := LaceworkCLIWithHome(home, "configure",
 		"--noninteractive",
 		"-a", "my-account",
-		"-k", "my-key",
-		"-s", "my-secret",
+		"-k", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+		"-s", "_00000000000000000000000000000000",
 	)
 
 	assert.Empty(t, errB.String())
@@ -56,13 +58,413 @@ func TestConfigureCommandNonInteractive(t *testing.T) {
 		panic(err)
 	}
 
-	assert.Equal(t, `[default]
+	assert.Equal(t, `version = 1
+
+[default]
   account = "my-account"
-  api_key = "my-key"
-  api_secret = "my-secret"
+  api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
+  api_secret = "_00000000000000000000000000000000"
 `, string(laceworkTOML), "there is a problem with the generated config")
 }
 
+func TestConfigureCommandWithFlagsDoesNotPrompt(t *testing.T) {
+	home, err := ioutil.TempDir("", "lacework-cli")
+	if err != nil {
+		panic(err)
+	}
+
+	defer os.RemoveAll(home)
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure",
+		"--account", "my-account",
+		"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+		"--api_secret", "_00000000000000000000000000000000",
+	)
+
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+	assert.Equal(t, "You are all set!\n", out.String(),
+		"you are not all set, check configure cmd")
+
+	laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, `version = 1
+
+[default]
+  account = "my-account"
+  api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
+  api_secret = "_00000000000000000000000000000000"
+`, string(laceworkTOML), "there is a problem with the generated config")
+}
+
+func TestConfigureCommandWithFlagsJSONOutput(t *testing.T) {
+	home, err := ioutil.TempDir("", "lacework-cli")
+	if err != nil {
+		panic(err)
+	}
+
+	defer os.RemoveAll(home)
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure",
+		"--profile", "ci",
+		"--account", "my-account",
+		"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+		"--api_secret", "_00000000000000000000000000000000",
+		"--noninteractive", "--json",
+	)
+
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+	assert.Equal(t, `{
+  "account": "my-account",
+  "active": true,
+  "api_key": "***************************************************CC00",
+  "api_secret": "*****************************0000",
+  "profile": "ci"
+}
+`, out.String(), "the JSON output of the configured profile changed, please check")
+
+	laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if assert.Nil(t, err) {
+		assert.Contains(t, string(laceworkTOML), "[ci]")
+	}
+}
+
+func TestConfigureCommandWithFlagsDryRun(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	before, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if err != nil {
+		panic(err)
+	}
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure",
+		"--account", "my-account",
+		"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+		"--api_secret", "_00000000000000000000000000000000",
+		"--dry-run",
+	)
+
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+	assert.Contains(t, out.String(), "# dry run, the config file")
+	assert.Contains(t, out.String(), `[default]
+  account = "my-account"
+  api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
+  api_secret = "*****************************0000"
+`, "the dry run should display the profile with the secret masked")
+	assert.NotContains(t, out.String(), "You are all set!")
+
+	after, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if assert.Nil(t, err) {
+		assert.Equal(t, string(before), string(after), "the dry run should not modify the config")
+	}
+}
+
+func TestConfigureCommandWithSubaccount(t *testing.T) {
+	home, err := ioutil.TempDir("", "lacework-cli")
+	if err != nil {
+		panic(err)
+	}
+
+	defer os.RemoveAll(home)
+	_, errB, exitcode := LaceworkCLIWithHome(home, "configure",
+		"--account", "my-org",
+		"--subaccount", "my-tenant",
+		"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+		"--api_secret", "_00000000000000000000000000000000",
+	)
+
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+
+	laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, `version = 1
+
+[default]
+  account = "my-org"
+  subaccount = "my-tenant"
+  api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
+  api_secret = "_00000000000000000000000000000000"
+`, string(laceworkTOML), "there is a problem with the generated config")
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "show", "subaccount")
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+	assert.Equal(t, "my-tenant\n", out.String())
+}
+
+func TestConfigureCommandWithApiURL(t *testing.T) {
+	home, err := ioutil.TempDir("", "lacework-cli")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(home)
+
+	_, errB, exitcode := LaceworkCLIWithHome(home, "configure",
+		"--account", "my-account",
+		"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+		"--api_secret", "_00000000000000000000000000000000",
+		"--api-url", "https://api.example.com/",
+	)
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+
+	laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if err != nil {
+		panic(err)
+	}
+	assert.Contains(t, string(laceworkTOML), `  api_url = "https://api.example.com"
+`, "there is a problem with the generated config")
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure",
+		"--account", "my-account",
+		"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+		"--api_secret", "_00000000000000000000000000000000",
+		"--api-url", "api.example.com",
+	)
+	assert.Empty(t, out.String())
+	assert.Contains(t, errB.String(), "invalid API URL 'api.example.com'")
+	assert.Equal(t, 1, exitcode)
+}
+
+func TestConfigureCommandWithSecretFromStdin(t *testing.T) {
+	home, err := ioutil.TempDir("", "lacework-cli")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(home)
+
+	t.Run("valid secret", func(t *testing.T) {
+		var out, errB bytes.Buffer
+		cmd := NewLaceworkCLI(home, "configure",
+			"--account", "my-account",
+			"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+			"--secret-stdin",
+		)
+		cmd.Stdin = strings.NewReader("_00000000000000000000000000000000\n")
+		cmd.Stdout = &out
+		cmd.Stderr = &errB
+
+		assert.Nil(t, cmd.Run())
+		assert.Empty(t, errB.String())
+		assert.Equal(t, "You are all set!\n", out.String(),
+			"you are not all set, check configure cmd")
+
+		laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+		if err != nil {
+			panic(err)
+		}
+
+		assert.Equal(t, `version = 1
+
+[default]
+  account = "my-account"
+  api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
+  api_secret = "_00000000000000000000000000000000"
+`, string(laceworkTOML), "there is a problem with the generated config")
+	})
+
+	t.Run("short secret", func(t *testing.T) {
+		var errB bytes.Buffer
+		cmd := NewLaceworkCLI(home, "configure",
+			"--account", "my-account",
+			"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+			"--secret-stdin",
+		)
+		cmd.Stdin = strings.NewReader("my-secret\n")
+		cmd.Stderr = &errB
+
+		assert.NotNil(t, cmd.Run())
+		assert.Contains(t, errB.String(),
+			"The API secret access key must have more than 30 characters.")
+	})
+}
+
+func TestConfigureCommandWithMultipleJsonFiles(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	keysDir := path.Join(home, "keys")
+	if err := os.Mkdir(keysDir, 0755); err != nil {
+		panic(err)
+	}
+	for _, account := range []string{"dev", "prod"} {
+		err := ioutil.WriteFile(path.Join(keysDir, account+".json"), []byte(`{
+  "keyId": "`+strings.ToUpper(account)+`_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000000",
+  "secret": "_`+account+`0000000000000000000000000000"
+}`), 0644)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	// the account is not derived from the file name
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure",
+		"--json_file", path.Join(keysDir, "*.json"),
+		"--profile-prefix", "org-",
+	)
+	assert.Empty(t, out.String())
+	assert.Contains(t, errB.String(), "is required, use --account")
+	assert.Equal(t, 1, exitcode)
+
+	out, errB, exitcode = LaceworkCLIWithHome(home, "configure",
+		"--json_file", path.Join(keysDir, "*.json"),
+		"--profile-prefix", "org-",
+		"--account", "my-org",
+		"--subaccount", "prod",
+	)
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+	assert.Contains(t, out.String(), "org-dev")
+	assert.Contains(t, out.String(), "org-prod")
+	assert.Contains(t, out.String(), "created")
+	assert.Contains(t, out.String(), "You are all set! 2 profiles configured.")
+
+	laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if err != nil {
+		panic(err)
+	}
+	assert.Contains(t, string(laceworkTOML), `[org-prod]
+  account = "my-org"
+  subaccount = "prod"
+  api_key = "PROD_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000000"
+  api_secret = "_prod0000000000000000000000000000"
+`)
+	assert.Contains(t, string(laceworkTOML), "[dev]",
+		"existing profiles should not be modified")
+}
+
+func TestConfigureCommandWithMultipleJsonFilesExistingProfiles(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	keysDir := path.Join(home, "keys")
+	if err := os.Mkdir(keysDir, 0755); err != nil {
+		panic(err)
+	}
+	for _, account := range []string{"dev", "integration"} {
+		err := ioutil.WriteFile(path.Join(keysDir, account+".json"), []byte(`{
+  "keyId": "`+strings.ToUpper(account)+`_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000000",
+  "secret": "_`+account+`0000000000000000000000000000"
+}`), 0644)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	before, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if err != nil {
+		panic(err)
+	}
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure",
+		"--json_file", path.Join(keysDir, "*.json"), "--account", "my-org",
+	)
+	assert.Empty(t, out.String())
+	assert.Contains(t, errB.String(),
+		"profiles 'dev', 'integration' already exist, use --force to overwrite them")
+	assert.Equal(t, 1, exitcode)
+
+	out, errB, exitcode = LaceworkCLIWithHome(home, "configure",
+		"--json_file", path.Join(keysDir, "*.json"), "--account", "my-org", "--dry-run",
+	)
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+	assert.Contains(t, out.String(), "[integration]")
+
+	after, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(t, string(before), string(after), "the existing profiles should not be modified")
+
+	out, errB, exitcode = LaceworkCLIWithHome(home, "configure",
+		"--json_file", path.Join(keysDir, "*.json"), "--account", "my-org", "--force",
+	)
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+	assert.Contains(t, out.String(), "updated")
+	assert.Contains(t, out.String(), "You are all set! 2 profiles configured.")
+}
+
+func TestConfigureCommandBackups(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	configure := func(args ...string) {
+		_, errB, exitcode := LaceworkCLIWithHome(home, append([]string{"configure",
+			"--account", "my-account",
+			"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+			"--api_secret", "_00000000000000000000000000000000",
+			"--force",
+		}, args...)...)
+		assert.Empty(t, errB.String())
+		assert.Equal(t, 0, exitcode)
+	}
+
+	configure("--no-backup")
+	backups, _ := filepath.Glob(path.Join(home, ".lacework.toml.bak-*"))
+	assert.Empty(t, backups, "no backups should be written with --no-backup")
+
+	for i := 0; i < 3; i++ {
+		configure("--max-backups", "2")
+	}
+	backups, _ = filepath.Glob(path.Join(home, ".lacework.toml.bak-*"))
+	assert.Len(t, backups, 2, "only the latest backups should be kept")
+}
+
+func TestConfigureCommandExistingProfileRequiresForce(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure",
+		"--account", "my-account",
+		"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+		"--api_secret", "_00000000000000000000000000000000",
+	)
+	assert.Empty(t, out.String())
+	assert.Contains(t, errB.String(),
+		"profile 'default' already exists, use --force to overwrite it")
+	assert.Equal(t, 1, exitcode)
+}
+
+func TestConfigureCommandWithInvalidKeys(t *testing.T) {
+	home, err := ioutil.TempDir("", "lacework-cli")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure",
+		"--noninteractive",
+		"-a", "my-account",
+		"-k", "my-key",
+		"-s", "my-secret",
+	)
+	assert.Empty(t, out.String())
+	assert.Contains(t, errB.String(), "api_key must have at least 55 characters")
+	assert.Equal(t, 1, exitcode)
+
+	out, errB, exitcode = LaceworkCLIWithHome(home, "configure",
+		"--noninteractive",
+		"--lenient",
+		"-a", "my-account",
+		"-k", "my-key",
+		"-s", "my-secret",
+	)
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+	assert.Equal(t, "You are all set!\n", out.String(),
+		"you are not all set, check configure cmd")
+}
+
 func createJSONFileLikeWebUI(content string) string {
 	contentBytes := []byte(content)
 	tmpfile, err := ioutil.TempFile("", "json_file")
@@ -98,9 +500,45 @@ account = 'dev.example'
 api_key = 'DEVDEV_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000'
 api_secret = '_11111111111111111111111111111111'
 `)
-	err = ioutil.WriteFile(configFile, c, 0644)
+	err = ioutil.WriteFile(configFile, c, 0600)
 	if err != nil {
 		panic(err)
 	}
 	return dir
 }
+
+func TestConfigureVerifyCommandWithoutConfig(t *testing.T) {
+	out, errB, exitcode := LaceworkCLI("configure", "verify")
+	assert.Contains(t, out.String(), "[FAIL] config file: no config file found")
+	assert.Contains(t, out.String(), "Hint: run 'lacework configure' to create it")
+	assert.Contains(t, out.String(), "[SKIP] credentials: the profile is not valid")
+	assert.Contains(t, errB.String(), "ERROR 2 of 5 checks failed")
+	assert.Equal(t, 1, exitcode)
+}
+
+func TestConfigureCommandWithConfigFlag(t *testing.T) {
+	home, err := ioutil.TempDir("", "lacework-cli")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(home)
+
+	configFile := path.Join(home, "ci", "lacework.toml")
+	_, errB, exitcode := LaceworkCLIWithHome(home, "configure", "--noninteractive",
+		"--config", configFile,
+		"--account", "my-account",
+		"--api_key", "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+		"--api_secret", "_00000000000000000000000000000000",
+	)
+	assert.Empty(t, errB.String())
+	assert.Equal(t, 0, exitcode)
+	assert.FileExists(t, configFile, "the config file should be written at the provided path")
+	assert.NoFileExists(t, path.Join(home, ".lacework.toml"),
+		"the default config file should not be written")
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "show", "account",
+		"--config", configFile)
+	assert.Empty(t, errB.String())
+	assert.Equal(t, "my-account\n", out.String())
+	assert.Equal(t, 0, exitcode)
+}
@@ -44,7 +44,9 @@ func TestConfigureCommand(t *testing.T) {
 		"configure",
 	)
 
-	assert.Equal(t, `[default]
+	assert.Equal(t, `version = 1
+
+[default]
   account = "test-account"
   api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
   api_secret = "_00000000000000000000000000000000"
@@ -65,7 +67,9 @@ func TestConfigureCommandWithProfileFlag(t *testing.T) {
 		"configure", "--profile", "my-profile",
 	)
 
-	assert.Equal(t, `[my-profile]
+	assert.Equal(t, `version = 1
+
+[my-profile]
   account = "test-account"
   api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
   api_secret = "_00000000000000000000000000000000"
@@ -90,7 +94,9 @@ func TestConfigureCommandWithJSONFileFlag(t *testing.T) {
 		"configure", "--json_file", s, "--profile", "web-ui-test",
 	)
 
-	assert.Equal(t, `[web-ui-test]
+	assert.Equal(t, `version = 1
+
+[web-ui-test]
   account = "web-ui-test"
   api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
   api_secret = "_cccccccccccccccccccccccccccccccc"
@@ -118,7 +124,9 @@ func TestConfigureCommandWithEnvironmentVariables(t *testing.T) {
 		"configure",
 	)
 
-	assert.Equal(t, `[default]
+	assert.Equal(t, `version = 1
+
+[default]
   account = "env-vars"
   api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
   api_secret = "_cccccccccccccccccccccccccccccccc"
@@ -128,12 +136,7 @@ func TestConfigureCommandWithEnvironmentVariables(t *testing.T) {
 func TestConfigureCommandWithAPIkeysFromFlags(t *testing.T) {
 	_, laceworkTOML := runConfigureTest(t,
 		func(c *expect.Console) {
-			c.ExpectString("Account:")
-			c.SendLine("") // using the default, which should be loaded from the provided flags
-			c.ExpectString("Access Key ID:")
-			c.SendLine("") // using the default, which should be loaded from the provided flags
-			c.ExpectString("Secret Access Key:")
-			c.SendLine("") // using the default, which should be loaded from the provided flags
+			// all settings are provided via flags, there should be no prompts
 			c.ExpectString("You are all set!")
 		},
 		"configure",
@@ -142,7 +145,9 @@ func TestConfigureCommandWithAPIkeysFromFlags(t *testing.T) {
 		"--api_secret", "_cccccccccccccccccccccccccccccccc",
 	)
 
-	assert.Equal(t, `[default]
+	assert.Equal(t, `version = 1
+
+[default]
   account = "from-flags"
   api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
   api_secret = "_cccccccccccccccccccccccccccccccc"
@@ -166,7 +171,9 @@ func TestConfigureCommandWithExistingConfigAndMultiProfile(t *testing.T) {
 		"configure", "--profile", "new-profile",
 	)
 
-	assert.Equal(t, `[default]
+	assert.Equal(t, `version = 1
+
+[default]
   account = "test.account"
   api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
   api_secret = "_00000000000000000000000000000000"
@@ -188,6 +195,32 @@ func TestConfigureCommandWithExistingConfigAndMultiProfile(t *testing.T) {
 `, laceworkTOML, "there is a problem with the generated config")
 }
 
+func TestConfigureCommandWithExistingProfileConfirmed(t *testing.T) {
+	dir := createTOMLConfig()
+	defer os.RemoveAll(dir)
+
+	_, laceworkTOML := runConfigureTestFromDir(t, dir,
+		func(c *expect.Console) {
+			c.ExpectString("Profile 'dev' exists, update it?")
+			c.SendLine("y")
+			c.ExpectString("Account:")
+			c.SendLine("")
+			c.ExpectString("Access Key ID:")
+			c.SendLine("")
+			c.ExpectString("Secret Access Key:")
+			c.SendLine("_22222222222222222222222222222222")
+			c.ExpectString("You are all set!")
+		},
+		"configure", "--profile", "dev",
+	)
+
+	assert.Contains(t, laceworkTOML, `[dev]
+  account = "dev.example"
+  api_key = "DEVDEV_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000"
+  api_secret = "_22222222222222222222222222222222"
+`, "there is a problem with the generated config")
+}
+
 func TestConfigureCommandErrors(t *testing.T) {
 	_, laceworkTOML := runConfigureTest(t,
 		func(c *expect.Console) {
@@ -209,7 +242,9 @@ func TestConfigureCommandErrors(t *testing.T) {
 		"configure",
 	)
 
-	assert.Equal(t, `[default]
+	assert.Equal(t, `version = 1
+
+[default]
   account = "my-account"
   api_key = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
   api_secret = "_00000000000000000000000000000000"
@@ -287,3 +322,38 @@ func TestConfigureListHelp(t *testing.T) {
 	assert.Equal(t, 0, exitcode,
 		"EXITCODE is not the expected one")
 }
+
+func TestConfigureInsecurePermissions(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	configFile := path.Join(home, ".lacework.toml")
+	if err := os.Chmod(configFile, 0644); err != nil {
+		panic(err)
+	}
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "list")
+	assert.Contains(t, errB.String(),
+		"WARN config file "+configFile+" is accessible by other users (0644)",
+		"STDERR the warning message changed, please check")
+	assert.Contains(t, errB.String(),
+		"Run 'lacework configure verify --fix-perms' to fix it.",
+		"STDERR the warning message changed, please check")
+	assert.Contains(t, out.String(), "integration",
+		"the warning should not prevent loading the config")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+
+	out, errB, _ = LaceworkCLIWithHome(home, "configure", "verify", "--fix-perms")
+	assert.NotContains(t, errB.String(), "WARN", "STDERR should not have warnings")
+	assert.Contains(t, out.String(), "fixed, the file was accessible by other users 0644",
+		"STDOUT changed, please check")
+
+	info, err := os.Stat(configFile)
+	if assert.Nil(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	_, errB, exitcode = LaceworkCLIWithHome(home, "configure", "list")
+	assert.Empty(t, errB.String(), "STDERR should be empty")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+}
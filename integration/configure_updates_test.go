@@ -0,0 +1,81 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+package integration
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureUpdatesCommand(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "updates")
+	assert.Empty(t, errB.String(), "STDERR should be empty")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+	assert.Equal(t, "on\n", out.String(), "updates should be on by default")
+
+	out, errB, exitcode = LaceworkCLIWithHome(home, "configure", "updates", "off")
+	assert.Empty(t, errB.String(), "STDERR should be empty")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+	assert.Equal(t, "The check for updates is now off.\n", out.String(),
+		"STDOUT changed, please check")
+
+	laceworkTOML, err := ioutil.ReadFile(path.Join(home, ".lacework.toml"))
+	if assert.Nil(t, err) {
+		assert.Contains(t, string(laceworkTOML), "updates = false")
+		assert.Contains(t, string(laceworkTOML), "[dev]",
+			"the profiles should not be modified")
+	}
+
+	out, _, _ = LaceworkCLIWithHome(home, "configure", "updates")
+	assert.Equal(t, "off\n", out.String(), "STDOUT changed, please check")
+
+	t.Run("the version command does not check for updates", func(t *testing.T) {
+		out, errB, exitcode := LaceworkCLIWithHome(home, "version")
+		assert.Empty(t, errB.String(), "STDERR should be empty")
+		assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+		assert.NotContains(t, out.String(), "A newer version of the Lacework CLI is available",
+			"STDOUT changed, please check")
+	})
+
+	t.Run("the setting is preserved when updating profiles", func(t *testing.T) {
+		_, errB, exitcode := LaceworkCLIWithHome(home, "configure", "rename", "dev", "staging")
+		assert.Empty(t, errB.String(), "STDERR should be empty")
+		assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+
+		out, _, _ := LaceworkCLIWithHome(home, "configure", "updates")
+		assert.Equal(t, "off\n", out.String(), "STDOUT changed, please check")
+	})
+}
+
+func TestConfigureUpdatesCommandInvalidValue(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "configure", "updates", "maybe")
+	assert.Empty(t, out.String(), "STDOUT should be empty")
+	assert.Contains(t, errB.String(), "invalid value 'maybe', use 'on' or 'off'",
+		"STDERR changed, please check")
+	assert.Equal(t, 1, exitcode, "EXITCODE is not the expected one")
+}
@@ -19,6 +19,9 @@
 package integration
 
 import (
+	"io/ioutil"
+	"os"
+	"path"
 	"testing"
 	"time"
 
@@ -91,6 +94,32 @@ func TestEventCommandListSeverityError(t *testing.T) {
 		"EXITCODE is not the expected one")
 }
 
+func TestEventCommandListTypesOnly(t *testing.T) {
+	out, err, exitcode := LaceworkCLIWithTOMLConfig("event", "list", "--types-only", "--days", "3")
+	assert.Contains(t, out.String(), "EVENT TYPE",
+		"STDOUT table headers changed, please check")
+	assert.Contains(t, out.String(), "COUNT",
+		"STDOUT table headers changed, please check")
+	assert.NotContains(t, out.String(), "EVENT ID",
+		"STDOUT should only list event types, please check")
+	assert.Empty(t,
+		err.String(),
+		"STDERR should be empty")
+	assert.Equal(t, 0, exitcode,
+		"EXITCODE is not the expected one")
+}
+
+func TestEventCommandListTypesOnlyColumnsError(t *testing.T) {
+	out, err, exitcode := LaceworkCLIWithTOMLConfig("event", "list", "--types-only", "--columns", "id")
+	assert.Contains(t, err.String(), "ERROR cannot combine --types-only with --columns",
+		"STDERR the message to the user has changed, update please")
+	assert.Empty(t,
+		out.String(),
+		"STDOUT should be empty")
+	assert.Equal(t, 1, exitcode,
+		"EXITCODE is not the expected one")
+}
+
 func TestEventCommandListTimeRange(t *testing.T) {
 	var (
 		now  = time.Now().UTC()
@@ -126,3 +155,69 @@ func TestEventCommandOpenError(t *testing.T) {
 	assert.Equal(t, 1, exitcode,
 		"EXITCODE is not the expected one")
 }
+
+func TestEventCommandOpenPrintOnly(t *testing.T) {
+	out, err, exitcode := LaceworkCLIWithTOMLConfig("event", "open", "123", "--print-only")
+	assert.Contains(t, out.String(),
+		"/ui/investigation/recents/EventDossier-123",
+		"STDOUT the event URL changed, update")
+	assert.Empty(t,
+		err.String(),
+		"STDERR should be empty")
+	assert.Equal(t, 0, exitcode,
+		"EXITCODE is not the expected one")
+}
+
+func TestEventCommandLinkError(t *testing.T) {
+	out, err, exitcode := LaceworkCLIWithTOMLConfig("event", "link", "abc")
+	assert.Contains(t, err.String(),
+		"ERROR invalid event id abc. Event id should be a numeric value",
+		"STDERR the error message changed, update")
+	assert.Empty(t,
+		out.String(),
+		"STDOUT should be empty")
+	assert.Equal(t, 1, exitcode,
+		"EXITCODE is not the expected one")
+}
+
+func TestEventCommandListBookmarkReset(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	statePath := path.Join(home, ".config", "lacework", "state.json")
+	if err := os.MkdirAll(path.Dir(statePath), 0700); err != nil {
+		panic(err)
+	}
+	err := ioutil.WriteFile(statePath, []byte(`{"events_bookmarks": {
+  "test.account": "2020-08-20T08:00:00Z",
+  "dev.example": "2020-08-20T08:00:00Z"
+}}`), 0600)
+	if err != nil {
+		panic(err)
+	}
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "event", "list", "--reset-bookmark")
+	assert.Empty(t, errB.String(), "STDERR should be empty")
+	assert.Equal(t, 0, exitcode, "EXITCODE is not the expected one")
+	assert.Equal(t, "The events bookmark of the account 'test.account' was reset.\n",
+		out.String(), "STDOUT changed, please check")
+
+	state, err := ioutil.ReadFile(statePath)
+	if assert.Nil(t, err) {
+		assert.NotContains(t, string(state), "test.account")
+		assert.Contains(t, string(state), "dev.example",
+			"the bookmarks of other accounts should not be modified")
+	}
+}
+
+func TestEventCommandListBookmarkErrors(t *testing.T) {
+	home := createTOMLConfig()
+	defer os.RemoveAll(home)
+
+	out, errB, exitcode := LaceworkCLIWithHome(home, "event", "list", "--since-last", "--days", "1")
+	assert.Empty(t, out.String(), "STDOUT should be empty")
+	assert.Contains(t, errB.String(),
+		"ERROR cannot combine --since-last with --start, --end or --days",
+		"STDERR the message to the user has changed, update please")
+	assert.Equal(t, 1, exitcode, "EXITCODE is not the expected one")
+}
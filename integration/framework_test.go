@@ -142,7 +142,7 @@ account = '` + os.Getenv("CI_ACCOUNT") + `'
 api_key = '` + os.Getenv("CI_API_KEY") + `'
 api_secret = '` + os.Getenv("CI_API_SECRET") + `'
 `)
-	err = ioutil.WriteFile(configFile, c, 0644)
+	err = ioutil.WriteFile(configFile, c, 0600)
 	if err != nil {
 		panic(err)
 	}
@@ -188,7 +188,7 @@ account = 'dev.example'
 api_key = 'DEVDEV_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000'
 api_secret = '_11111111111111111111111111111111'
 `)
-	err = ioutil.WriteFile(configFile, c, 0644)
+	err = ioutil.WriteFile(configFile, c, 0600)
 	if err != nil {
 		panic(err)
 	}
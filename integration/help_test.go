@@ -50,6 +50,65 @@ download the generated API key file.
 
 Use the flag --json_file to preload the downloaded API key file.
 
+To configure multiple accounts at once, repeat the flag --json_file or pass
+a glob, one profile per file will be created or updated. The profile name is
+derived from the file name, use the flag --profile-prefix to prepend a prefix
+to the profile names. The account is the one of the flag --account, without
+it, you are prompted for the account of every file:
+
+    $ lacework configure --json_file 'keys/*.json' --profile-prefix org- --account my-org
+
+Updating existing profiles asks for confirmation, use the flag --force to
+update them without prompts.
+
+To configure the Lacework CLI without any prompt, useful for provisioning
+automation and container images, provide the account, API key and secret
+via flags (or the account plus the API key file):
+
+    $ lacework configure --account my-account --api_key X --api_secret Y
+
+The API access key and secret are validated to have at least 55 and 30
+characters respectively, use the flag --lenient for legacy API keys.
+
+Organization admins can configure the sub-account to interact with by
+using the flag --subaccount.
+
+For isolated regions or test environments where the API does not live at
+https://<ACCOUNT>.lacework.net, use the flag --api-url.
+
+Before overwriting the config file, a timestamped backup is written next to
+it (i.e. ~/.lacework.toml.bak-<timestamp>), only the latest backups are kept,
+use the flags --no-backup and --max-backups to control this behavior.
+
+To provision the Lacework CLI on EC2 instances or ECS tasks without copying
+API key files around, store the API key JSON file in AWS Secrets Manager and
+use the flag --from-aws-secret, the secret is fetched with the AWS SDK using
+the default credential chain (environment variables, config files or roles):
+
+    $ lacework configure --account my-account \
+        --from-aws-secret arn:aws:secretsmanager:us-west-2:123456789012:secret:lacework
+
+To avoid the secret being echoed or stored in your shell history, use the
+flag --secret-stdin to read it from the standard input:
+
+    $ cat secret.txt | lacework configure --account my-account --api_key X --secret-stdin
+
+To preview the changes without touching the config file, use the flag
+--dry-run, the credentials are verified and the config file that would be
+written is printed with the API secrets masked:
+
+    $ lacework configure --account my-account --api_key X --api_secret Y --dry-run
+
+When the profile already exists, you are asked to confirm before updating
+it. If there are no prompts (i.e. with --noninteractive or when all values
+are provided via flags), use the flag --force to overwrite it.
+
+To confirm what was written, for example from provisioning automation, use
+the flag --json to display the configured profile (with a masked secret)
+instead of the success message:
+
+    $ lacework configure --account my-account --api_key X --api_secret Y --json
+
 If this command is run with no flags, the Lacework CLI will store all
 settings under the default profile. The information in the default profile
 is used any time you run a Lacework CLI command that doesn't explicitly
@@ -64,22 +123,55 @@ Usage:
   lacework configure [command]
 
 Available Commands:
+  default     set the default profile of the config file ~/.lacework.toml
+  import      import the profiles from another config file
   list        list all configured profiles at ~/.lacework.toml
+  rename      rename a profile from the config file ~/.lacework.toml
   show        show current configuration data
+  test        test the credentials of a profile against the Lacework API
+  updates     turn on or off the check for updates of the Lacework CLI
+  verify      diagnose the setup of the Lacework CLI
 
 Flags:
-  -h, --help               help for configure
-  -j, --json_file string   loads the generated API key JSON file from the WebUI
+      --api-url string           custom API base URL (default https://<ACCOUNT>.lacework.net)
+      --dry-run                  print the config file that would be written without modifying it
+      --force                    overwrite an existing profile without asking for confirmation
+      --from-aws-secret string   loads the API key JSON file from an AWS Secrets Manager secret ARN
+  -h, --help                     help for configure
+  -j, --json_file stringArray    loads the generated API key JSON file from the WebUI (repeatable, accepts globs)
+      --lenient                  skip the length validation of legacy API keys and secrets
+      --max-backups int          number of config file backups to keep (default 5)
+      --no-backup                do not back up the config file before overwriting it
+      --profile-prefix string    prefix for the profiles created from multiple API key files
+      --secret-stdin             read the API secret from the standard input
 
 Global Flags:
-  -a, --account string      account subdomain of URL (i.e. <ACCOUNT>.lacework.net)
-  -k, --api_key string      access key id
-  -s, --api_secret string   secret access key
-      --debug               turn on debug logging
-      --json                switch commands output from human-readable to json format
-      --nocolor             turn off colors
-      --noninteractive      turn off interactive mode (disable spinners, prompts, etc.)
-  -p, --profile string      switch between profiles configured at ~/.lacework.toml
+  -a, --account string         account subdomain of URL (i.e. <ACCOUNT>.lacework.net), overrides the account of the profile
+  -k, --api_key string         access key id
+  -s, --api_secret string      secret access key
+      --ca-cert string         path to a PEM file of certificate authorities to trust, like the CA of a TLS-intercepting proxy
+      --concurrency int        number of API requests that bulk operations, like a directory scan, send at once (default 4)
+      --config string          path to the config file, also set with LW_CONFIG (default ~/.lacework.toml)
+      --debug                  turn on debug logging, same as --log-level debug
+      --debug-http             log the API requests and responses (URLs, headers and bodies) with their secrets redacted
+      --insecure-skip-verify   turn off the verification of TLS certificates (insecure, prefer --ca-cert)
+      --json                   (deprecated) alias of --output json
+      --json-compact           print the JSON output in a single line
+      --jsonl                  print lists in the JSON output one element per line (JSON Lines)
+      --log-format string      format of the logs written to stderr: console or json (default "console")
+      --log-level string       level of the logs written to stderr: info or debug
+      --no-color               turn off colors, also set with the environment variable NO_COLOR
+      --no-pager               do not pipe the output through a pager, overrides --paginate
+      --no-retry               turn off retries of API requests on transient errors
+      --nocolor                (deprecated) alias of --no-color
+      --noninteractive         turn off interactive mode (disable spinners, prompts, etc.)
+  -o, --output string          output format of the commands: table, json, csv or yaml (default "table")
+      --paginate               pipe long tables through $PAGER or 'less -R' when the output is a terminal
+  -p, --profile string         switch between profiles configured at ~/.lacework.toml
+  -q, --quiet                  suppress informational output like hints and banners, print only the data
+      --subaccount string      sub-account name inside your organization (org admins only)
+      --timeout string         timeout of the API requests in seconds or as a duration like 2m (default 60s)
+      --timezone string        time zone of the timestamps in the output, an IANA name like America/New_York (default UTC)
 
 Use "lacework configure [command] --help" for more information about a command.
 `,
@@ -119,7 +211,7 @@ func TestCommandDoesNotExist(t *testing.T) {
 		err.String(),
 		"ERROR unknown command \"foo\" for \"lacework\"",
 		"STDERR message doesn't match")
-	assert.Equal(t, 1, exitcode,
+	assert.Equal(t, 2, exitcode,
 		"EXITCODE is not the expected one")
 }
 
@@ -136,12 +228,20 @@ Start by configuring the Lacework CLI with the command:
 
 This will prompt you for your Lacework account and a set of API access keys.
 
+Exit Codes:
+  0    success
+  1    generic error
+  2    usage error (unknown command or flag, invalid arguments)
+  4    authentication error, the credentials are invalid or have no access
+  5    the requested resource was not found
+
 Usage:
   lacework [command]
 
 Available Commands:
   access-token  generate temporary access tokens
   api           helper to call Lacework's RestfulAPI
+  completion    generate shell completion scripts
   compliance    manage compliance reports
   configure     configure the Lacework CLI
   event         inspect Lacework events
@@ -150,14 +250,32 @@ Available Commands:
   vulnerability container and host vulnerability assessments
 
 Flags:
-  -a, --account string      account subdomain of URL (i.e. <ACCOUNT>.lacework.net)
-  -k, --api_key string      access key id
-  -s, --api_secret string   secret access key
-      --debug               turn on debug logging
-      --json                switch commands output from human-readable to json format
-      --nocolor             turn off colors
-      --noninteractive      turn off interactive mode (disable spinners, prompts, etc.)
-  -p, --profile string      switch between profiles configured at ~/.lacework.toml
+  -a, --account string         account subdomain of URL (i.e. <ACCOUNT>.lacework.net), overrides the account of the profile
+  -k, --api_key string         access key id
+  -s, --api_secret string      secret access key
+      --ca-cert string         path to a PEM file of certificate authorities to trust, like the CA of a TLS-intercepting proxy
+      --concurrency int        number of API requests that bulk operations, like a directory scan, send at once (default 4)
+      --config string          path to the config file, also set with LW_CONFIG (default ~/.lacework.toml)
+      --debug                  turn on debug logging, same as --log-level debug
+      --debug-http             log the API requests and responses (URLs, headers and bodies) with their secrets redacted
+      --insecure-skip-verify   turn off the verification of TLS certificates (insecure, prefer --ca-cert)
+      --json                   (deprecated) alias of --output json
+      --json-compact           print the JSON output in a single line
+      --jsonl                  print lists in the JSON output one element per line (JSON Lines)
+      --log-format string      format of the logs written to stderr: console or json (default "console")
+      --log-level string       level of the logs written to stderr: info or debug
+      --no-color               turn off colors, also set with the environment variable NO_COLOR
+      --no-pager               do not pipe the output through a pager, overrides --paginate
+      --no-retry               turn off retries of API requests on transient errors
+      --nocolor                (deprecated) alias of --no-color
+      --noninteractive         turn off interactive mode (disable spinners, prompts, etc.)
+  -o, --output string          output format of the commands: table, json, csv or yaml (default "table")
+      --paginate               pipe long tables through $PAGER or 'less -R' when the output is a terminal
+  -p, --profile string         switch between profiles configured at ~/.lacework.toml
+  -q, --quiet                  suppress informational output like hints and banners, print only the data
+      --subaccount string      sub-account name inside your organization (org admins only)
+      --timeout string         timeout of the API requests in seconds or as a duration like 2m (default 60s)
+      --timezone string        time zone of the timestamps in the output, an IANA name like America/New_York (default UTC)
 
 Use "lacework [command] --help" for more information about a command.
 `,
@@ -97,7 +97,7 @@ func TestIntegrationCommandShowNonExistingError(t *testing.T) {
 	out, err, exitcode := LaceworkCLIWithTOMLConfig("integration", "show", "EXXAMPLE_123")
 	assert.Emptyf(t, out.String(),
 		"STDOUT should be empty")
-	assert.Equal(t, 1, exitcode,
+	assert.Equal(t, 5, exitcode,
 		"EXITCODE is not the expected one")
 	assert.Contains(t, err.String(),
 		"ERROR the provided integration GUID was not found",
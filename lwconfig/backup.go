@@ -0,0 +1,94 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxBackups is the default number of config backups to keep
+const DefaultMaxBackups = 5
+
+// backupTimeFormat is a sortable timestamp used as suffix of the backups
+const backupTimeFormat = "20060102-150405.000000000"
+
+// BackupFile writes a timestamped copy of the provided config file next to it,
+// (i.e. ~/.lacework.toml.bak-<timestamp>) and prunes the oldest backups keeping
+// at most the provided number of backups. If the config file does not exist,
+// there is nothing to back up and it returns an empty path.
+func BackupFile(configPath string, maxBackups int) (string, error) {
+	info, err := os.Stat(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", errors.Wrap(err, "unable to back up config")
+	}
+
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to back up config")
+	}
+
+	backupPath := configPath + ".bak-" + time.Now().UTC().Format(backupTimeFormat)
+	if err := ioutil.WriteFile(backupPath, data, info.Mode().Perm()); err != nil {
+		return "", errors.Wrap(err, "unable to back up config")
+	}
+
+	return backupPath, PruneBackups(configPath, maxBackups)
+}
+
+// PruneBackups removes the oldest backups of the provided config file
+// keeping at most the provided number of backups
+func PruneBackups(configPath string, maxBackups int) error {
+	backups, err := ListBackups(configPath)
+	if err != nil {
+		return err
+	}
+
+	if maxBackups < 0 {
+		maxBackups = 0
+	}
+
+	for len(backups) > maxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return errors.Wrap(err, "unable to prune config backups")
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// ListBackups returns the backups of the provided config file, from oldest to newest
+func ListBackups(configPath string) ([]string, error) {
+	backups, err := filepath.Glob(configPath + ".bak-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list config backups")
+	}
+
+	// the timestamp suffix makes them sortable by name
+	sort.Strings(backups)
+	return backups, nil
+}
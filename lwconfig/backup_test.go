@@ -0,0 +1,90 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+func TestBackupFile(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, `[default]
+account = 'test.account'
+`)
+	defer cleanup()
+
+	backupPath, err := lwconfig.BackupFile(configPath, 2)
+	if assert.Nil(t, err) {
+		assert.Contains(t, backupPath, ".lacework.toml.bak-")
+
+		content, err := ioutil.ReadFile(backupPath)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "[default]\naccount = 'test.account'\n", string(content))
+		}
+
+		info, err := os.Stat(backupPath)
+		if assert.Nil(t, err) {
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		}
+	}
+
+	// only the newest backups should be kept
+	var latest string
+	for i := 0; i < 3; i++ {
+		latest, err = lwconfig.BackupFile(configPath, 2)
+		assert.Nil(t, err)
+	}
+
+	backups, err := lwconfig.ListBackups(configPath)
+	if assert.Nil(t, err) {
+		assert.Len(t, backups, 2)
+		assert.Equal(t, latest, backups[1])
+	}
+}
+
+func TestBackupFileWithoutConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lwconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	backupPath, err := lwconfig.BackupFile(filepath.Join(dir, ".lacework.toml"), 2)
+	assert.Nil(t, err)
+	assert.Empty(t, backupPath, "there should be nothing to back up")
+}
+
+func TestPruneBackupsZero(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, "")
+	defer cleanup()
+
+	_, err := lwconfig.BackupFile(configPath, 0)
+	assert.Nil(t, err)
+
+	backups, err := lwconfig.ListBackups(configPath)
+	if assert.Nil(t, err) {
+		assert.Empty(t, backups)
+	}
+}
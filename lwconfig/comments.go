@@ -0,0 +1,285 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// comments are the comments of a config file, they are collected while
+// loading the config so that Encode() writes them back, this way the notes
+// that users add to their config files, like "# prod key, rotate quarterly",
+// are not lost when the Lacework CLI updates the file
+//
+// Comments are attached to the table or key on the line below them, or to
+// the end of the line of a table or key (inline), comments of tables or keys
+// that no longer exist are dropped
+type comments struct {
+	// header is the block of comments at the top of the file, separated
+	// from the first setting by an empty line
+	header []string
+
+	// footer is the block of comments at the end of the file
+	footer []string
+
+	// above are the comments on the lines above a table or a key
+	above map[string][]string
+
+	// inline are the comments at the end of the line of a table or a key
+	inline map[string]string
+}
+
+// commentKey returns the key of the comments of a table, when the setting is
+// empty, or of a setting inside a table, top-level settings have no table
+func commentKey(table, setting string) string {
+	switch {
+	case setting == "":
+		return "[" + table + "]"
+	case table == "":
+		return setting
+	default:
+		return table + "." + setting
+	}
+}
+
+// parseComments collects the comments of the provided TOML document, it
+// returns nil if the document has no comments
+func parseComments(data []byte) *comments {
+	var (
+		c       = &comments{above: map[string][]string{}, inline: map[string]string{}}
+		table   string
+		pending []string
+		content bool
+		found   bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			// a block of comments at the top of the file followed by
+			// an empty line is the header of the file
+			if !content && len(pending) != 0 {
+				if len(c.header) != 0 {
+					c.header = append(c.header, "")
+				}
+				c.header = append(c.header, pending...)
+				pending = nil
+			}
+
+		case strings.HasPrefix(line, "#"):
+			pending = append(pending, line)
+			found = true
+
+		default:
+			key, inline, isTable := parseCommentedLine(line)
+			if key == "" {
+				continue
+			}
+			if isTable {
+				table = key
+				key = commentKey(table, "")
+			} else {
+				key = commentKey(table, key)
+			}
+
+			content = true
+			if len(pending) != 0 {
+				c.above[key] = pending
+				pending = nil
+			}
+			if inline != "" {
+				c.inline[key] = inline
+				found = true
+			}
+		}
+	}
+
+	if len(pending) != 0 {
+		if content {
+			c.footer = pending
+		} else {
+			c.header = append(c.header, pending...)
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return c
+}
+
+// annotate writes the comments into the provided TOML document, which is
+// the output of the TOML encoder
+func (c *comments) annotate(data []byte) []byte {
+	var (
+		buf   = new(bytes.Buffer)
+		table string
+	)
+
+	for _, comment := range c.header {
+		buf.WriteString(comment + "\n")
+	}
+	if len(c.header) != 0 {
+		buf.WriteString("\n")
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimLeft(line, " \t")
+
+		key, _, isTable := parseCommentedLine(trimmed)
+		if key == "" {
+			buf.WriteString(line + "\n")
+			continue
+		}
+
+		if isTable {
+			table = key
+			key = commentKey(table, "")
+		} else {
+			key = commentKey(table, key)
+		}
+
+		indent := line[:len(line)-len(trimmed)]
+		for _, comment := range c.above[key] {
+			buf.WriteString(indent + comment + "\n")
+		}
+		if inline, ok := c.inline[key]; ok {
+			line += " " + inline
+		}
+		buf.WriteString(line + "\n")
+	}
+
+	if len(c.footer) != 0 {
+		buf.WriteString("\n")
+		for _, comment := range c.footer {
+			buf.WriteString(comment + "\n")
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// rename moves the comments of a table and its settings to a new table name
+func (c *comments) rename(oldTable, newTable string) {
+	if c == nil {
+		return
+	}
+
+	var (
+		above  = make(map[string][]string, len(c.above))
+		inline = make(map[string]string, len(c.inline))
+	)
+	// the comments of an overwritten table are dropped
+	for key, comments := range c.above {
+		if newKey, ok := renamedCommentKey(key, oldTable, newTable); ok {
+			above[newKey] = comments
+		} else if !isTableCommentKey(key, newTable) {
+			above[key] = comments
+		}
+	}
+	for key, comment := range c.inline {
+		if newKey, ok := renamedCommentKey(key, oldTable, newTable); ok {
+			inline[newKey] = comment
+		} else if !isTableCommentKey(key, newTable) {
+			inline[key] = comment
+		}
+	}
+	c.above, c.inline = above, inline
+}
+
+// isTableCommentKey returns true if the comment key is of the provided
+// table or of one of its settings
+func isTableCommentKey(key, table string) bool {
+	return key == commentKey(table, "") || strings.HasPrefix(key, table+".")
+}
+
+// renamedCommentKey returns the new comment key of a table, or of one of its
+// settings, that was renamed
+func renamedCommentKey(key, oldTable, newTable string) (string, bool) {
+	switch {
+	case key == commentKey(oldTable, ""):
+		return commentKey(newTable, ""), true
+	case strings.HasPrefix(key, oldTable+"."):
+		return commentKey(newTable, strings.TrimPrefix(key, oldTable+".")), true
+	default:
+		return "", false
+	}
+}
+
+// parseCommentedLine parses a line of a TOML document that is a table header,
+// like [dev], or a setting, like account = "dev", and returns the name of the
+// table or the setting and its inline comment, if any
+func parseCommentedLine(line string) (string, string, bool) {
+	content, inline := line, ""
+	if i := inlineCommentIndex(line); i >= 0 {
+		content, inline = strings.TrimSpace(line[:i]), line[i:]
+	}
+
+	if strings.HasPrefix(content, "[") {
+		// arrays of tables, like [[name]], are not used by the config
+		if strings.HasPrefix(content, "[[") || !strings.HasSuffix(content, "]") {
+			return "", "", false
+		}
+		return unquoteTOMLKey(content[1 : len(content)-1]), inline, true
+	}
+
+	if i := strings.Index(content, "="); i > 0 {
+		return unquoteTOMLKey(content[:i]), inline, false
+	}
+	return "", "", false
+}
+
+// inlineCommentIndex returns the index of the comment at the end of a line,
+// that is, the first # outside of a string, or -1 if there is no comment
+func inlineCommentIndex(line string) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case quote == 0 && (ch == '"' || ch == '\''):
+			quote = ch
+		case quote == '"' && ch == '\\':
+			// skip the escaped character
+			i++
+		case quote != 0 && ch == quote:
+			quote = 0
+		case quote == 0 && ch == '#':
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteTOMLKey returns the name of a bare or quoted TOML key
+func unquoteTOMLKey(key string) string {
+	key = strings.TrimSpace(key)
+	if len(key) >= 2 && key[0] == '\'' && key[len(key)-1] == '\'' {
+		return key[1 : len(key)-1]
+	}
+	if unquoted, err := strconv.Unquote(key); err == nil && strings.HasPrefix(key, `"`) {
+		return unquoted
+	}
+	return key
+}
@@ -0,0 +1,123 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+func TestConfigEncodeKeepsComments(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, `# Lacework credentials
+# managed by the security team
+
+version = 1
+default_profile = "prod" # the one we use most
+
+# prod key, rotate quarterly
+[prod]
+account = "prod"
+api_key = "PROD_KEY"
+api_secret = "_secret#1" # not a comment
+
+[dev]
+  # shared with the QA team
+  account = "dev"
+  api_key = "DEV_KEY"
+  api_secret = "_secret"
+
+# end of file
+`)
+	defer cleanup()
+
+	config, err := lwconfig.LoadFromFile(configPath)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "_secret#1", config.Profiles["prod"].ApiSecret)
+
+	config.SetProfile("test", lwconfig.ProfileDetails{
+		Account: "test", ApiKey: "TEST_KEY", ApiSecret: "_secret",
+	})
+	delete(config.Profiles, "dev")
+
+	data, err := config.Encode()
+	if assert.Nil(t, err) {
+		assert.Equal(t, `# Lacework credentials
+# managed by the security team
+
+default_profile = "prod" # the one we use most
+version = 1
+
+# prod key, rotate quarterly
+[prod]
+  account = "prod"
+  api_key = "PROD_KEY"
+  api_secret = "_secret#1" # not a comment
+
+[test]
+  account = "test"
+  api_key = "TEST_KEY"
+  api_secret = "_secret"
+
+# end of file
+`, string(data))
+	}
+
+	// the comments follow a renamed profile
+	assert.Nil(t, config.RenameProfile("prod", "production"))
+	assert.Equal(t, "production", config.DefaultProfile)
+
+	data, err = config.Encode()
+	if assert.Nil(t, err) {
+		assert.Contains(t, string(data), "# prod key, rotate quarterly\n[production]\n")
+		assert.Contains(t, string(data), `api_secret = "_secret#1" # not a comment`)
+	}
+}
+
+func TestConfigEncodeWithoutComments(t *testing.T) {
+	content := `version = 1
+
+[default]
+  account = "test.account"
+  api_key = "KEY"
+  api_secret = "SECRET"
+`
+	configPath, cleanup := createTOMLConfig(t, content)
+	defer cleanup()
+
+	config, err := lwconfig.LoadFromFile(configPath)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	data, err := config.Encode()
+	if assert.Nil(t, err) {
+		assert.Equal(t, content, string(data))
+	}
+	assert.Equal(t, lwconfig.Config{
+		Version: lwconfig.ConfigVersion,
+		Profiles: lwconfig.Profiles{
+			"default": {Account: "test.account", ApiKey: "KEY", ApiSecret: "SECRET"},
+		},
+	}, config, "a config without comments should be equal to a new config")
+}
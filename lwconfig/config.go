@@ -0,0 +1,397 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// A configuration package for the Lacework CLI and SDK embedders that
+// need to read the Lacework configuration file (~/.lacework.toml)
+package lwconfig
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ApiKeyMinLength is the minimum length of a Lacework API access key id
+	ApiKeyMinLength = 55
+
+	// ApiSecretMinLength is the minimum length of a Lacework API secret access key
+	ApiSecretMinLength = 30
+)
+
+// Config is the representation of the configuration file ~/.lacework.toml
+//
+// Example:
+//
+// version = 1
+// default_profile = "dev"
+// updates = true
+//
+// [default]
+// account = "example"
+// api_key = "EXAMPLE_0123456789"
+// api_secret = "_0123456789"
+//
+// [dev]
+// account = "dev"
+// subaccount = "tenant-a"
+// api_key = "DEV_0123456789"
+// api_secret = "_0123456789"
+type Config struct {
+	Version  int
+	Profiles Profiles
+
+	// DefaultProfile is the profile selected with 'lacework configure default',
+	// it is used when no profile is provided, empty means the "default" profile
+	DefaultProfile string
+
+	// Updates enables or disables the check for updates of the Lacework CLI,
+	// nil means it was never configured, use UpdatesEnabled() to read it
+	Updates *bool
+
+	// legacyProfile is a profile defined at the top-level of the config
+	// file, a layout used before versioning the config, see Migrate()
+	legacyProfile *ProfileDetails
+
+	// migrated is true when the config was migrated while loading it
+	migrated bool
+
+	// warnings are problems found while loading the config, see Warnings()
+	warnings []error
+
+	// comments of the config file, they are written back by Encode()
+	comments *comments
+}
+
+// Profiles is a map of all the profiles configured, indexed by profile name
+type Profiles map[string]ProfileDetails
+
+// ProfileDetails contains the details of a single profile
+//
+// The subaccount is only used by organizational accounts, it is the
+// account (tenant) inside the organization to interact with
+//
+// The API URL is only needed for isolated regions or test environments
+// where the API does not live at https://<account>.lacework.net, same for
+// the domain, which is used to build links to the Lacework UI
+//
+// The CA certificate is a PEM file with the certificate authorities to trust
+// when connecting to the API, needed behind TLS-intercepting proxies
+type ProfileDetails struct {
+	Account    string `toml:"account" json:"account"`
+	Subaccount string `toml:"subaccount,omitempty" json:"subaccount,omitempty"`
+	ApiKey     string `toml:"api_key" json:"api_key" survey:"api_key"`
+	ApiSecret  string `toml:"api_secret" json:"api_secret" survey:"api_secret"`
+	ApiURL     string `toml:"api_url,omitempty" json:"api_url,omitempty"`
+	Domain     string `toml:"domain,omitempty" json:"domain,omitempty"`
+	CACert     string `toml:"ca_cert,omitempty" json:"ca_cert,omitempty"`
+
+	// Timeout of the API requests in seconds, zero means the default timeout
+	Timeout int `toml:"timeout,omitzero" json:"timeout,omitempty"`
+}
+
+// Verify checks that the profile has all the required settings, the
+// subaccount is optional since it is only needed by organizational accounts
+//
+// This is a lenient verification that only checks for empty settings, which
+// is useful for legacy API keys, use VerifyStrict to validate their format
+func (p *ProfileDetails) Verify() error {
+	if p.Account == "" {
+		return errors.New("account missing")
+	}
+	if p.ApiKey == "" {
+		return errors.New("api_key missing")
+	}
+	if p.ApiSecret == "" {
+		return errors.New("api_secret missing")
+	}
+	return nil
+}
+
+// VerifyStrict checks that the profile has all the required settings and
+// that the API access key and secret have the expected length
+func (p *ProfileDetails) VerifyStrict() error {
+	if err := p.Verify(); err != nil {
+		return err
+	}
+	if len(p.ApiKey) < ApiKeyMinLength {
+		return errors.Errorf("api_key must have at least %d characters", ApiKeyMinLength)
+	}
+	if len(p.ApiSecret) < ApiSecretMinLength {
+		return errors.Errorf("api_secret must have at least %d characters", ApiSecretMinLength)
+	}
+	return nil
+}
+
+// DefaultConfigPath returns the default location of the configuration
+// file, that is, the file .lacework.toml inside the user's home directory,
+// unless the environment variable LW_CONFIG is set with a different path
+func DefaultConfigPath() (string, error) {
+	if configPath := os.Getenv("LW_CONFIG"); configPath != "" {
+		return configPath, nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(home, ".lacework.toml"), nil
+}
+
+// LoadFromFile loads the configuration from the provided file path, if the
+// configuration has an older layout, it is migrated automatically to the
+// current version, use Migrated() to know if it needs to be written back
+//
+// Problems that do not prevent using the configuration, like a config file
+// accessible by other users, are returned by Warnings()
+func LoadFromFile(configPath string) (Config, error) {
+	config := Config{Profiles: Profiles{}}
+	if configPath == "" {
+		return config, errors.New("unable to load config. Path cannot be empty.")
+	}
+
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return config, errors.Wrap(err, "unable to decode profiles from config")
+	}
+
+	raw := map[string]toml.Primitive{}
+	md, err := toml.Decode(string(data), &raw)
+	if err != nil {
+		return config, errors.Wrap(err, "unable to decode profiles from config")
+	}
+	config.comments = parseComments(data)
+
+	for key, value := range raw {
+		if err := config.decodeKey(md, key, value); err != nil {
+			return config, errors.Wrapf(err, "unable to decode '%s' from config", key)
+		}
+	}
+
+	if config.Version < 0 {
+		return config, errors.Errorf("invalid config version %d", config.Version)
+	}
+	if config.Version > ConfigVersion {
+		return config, errors.Errorf(
+			"config version %d is newer than the supported version %d, upgrade to a newer release",
+			config.Version, ConfigVersion,
+		)
+	}
+
+	if err := CheckPermissions(configPath); IsInsecurePermissions(err) {
+		config.warnings = append(config.warnings, err)
+	}
+
+	config.Migrate()
+	return config, nil
+}
+
+// decodeKey decodes a single top-level key of the config file, which is
+// either the version, a setting of a legacy profile, or a profile table
+//
+// The names of the global keys are reserved, but a profile table can have
+// the name of a setting of a legacy profile, like [account]
+func (c *Config) decodeKey(md toml.MetaData, key string, value toml.Primitive) error {
+	if md.Type(key) == "Hash" && !IsReservedProfileName(key) {
+		profile := ProfileDetails{}
+		if err := md.PrimitiveDecode(value, &profile); err != nil {
+			return err
+		}
+		c.Profiles[key] = profile
+		return nil
+	}
+
+	switch key {
+	case "version":
+		return md.PrimitiveDecode(value, &c.Version)
+
+	case "default_profile":
+		return md.PrimitiveDecode(value, &c.DefaultProfile)
+
+	case "updates":
+		var updates bool
+		if err := md.PrimitiveDecode(value, &updates); err != nil {
+			return err
+		}
+		c.Updates = &updates
+		return nil
+
+	case "account", "subaccount", "api_key", "api_secret", "api_url", "domain":
+		var setting string
+		if err := md.PrimitiveDecode(value, &setting); err != nil {
+			return err
+		}
+		c.setLegacySetting(key, setting)
+		return nil
+
+	default:
+		return errors.New("unknown setting, profiles must be tables")
+	}
+}
+
+// setLegacySetting sets a setting of the profile defined at the top-level
+func (c *Config) setLegacySetting(key, setting string) {
+	if c.legacyProfile == nil {
+		c.legacyProfile = &ProfileDetails{}
+	}
+
+	switch key {
+	case "account":
+		c.legacyProfile.Account = setting
+	case "subaccount":
+		c.legacyProfile.Subaccount = setting
+	case "api_key":
+		c.legacyProfile.ApiKey = setting
+	case "api_secret":
+		c.legacyProfile.ApiSecret = setting
+	case "api_url":
+		c.legacyProfile.ApiURL = setting
+	case "domain":
+		c.legacyProfile.Domain = setting
+	}
+}
+
+// Warnings returns the problems found while loading the config that do not
+// prevent using it, like an InsecurePermissionsError, callers should surface
+// them to the user
+func (c Config) Warnings() []error {
+	return c.warnings
+}
+
+// UpdatesEnabled returns true if the check for updates of the Lacework CLI
+// is enabled, which is the default when the setting was never configured
+func (c Config) UpdatesEnabled() bool {
+	return c.Updates == nil || *c.Updates
+}
+
+// SetUpdates enables or disables the check for updates of the Lacework CLI
+func (c *Config) SetUpdates(enabled bool) {
+	c.Updates = &enabled
+}
+
+// Migrated returns true if the config was migrated while loading it, in
+// such case, it should be written back with Save() or WriteToFile()
+func (c Config) Migrated() bool {
+	return c.migrated
+}
+
+// Save writes the configuration into the default location of the
+// configuration file, that is $HOME/.lacework.toml or $LW_CONFIG
+func (c Config) Save() error {
+	configPath, err := DefaultConfigPath()
+	if err != nil {
+		return err
+	}
+	return c.WriteToFile(configPath)
+}
+
+// WriteToFile writes the configuration into the provided file path, the file
+// is written atomically by writing a temporary file that is then renamed,
+// if the file already exists, its permissions are preserved, otherwise the
+// file is created with 0600 permissions since it contains credentials, as
+// well as its parent directory, if missing, with 0700 permissions
+func (c Config) WriteToFile(configPath string) error {
+	if configPath == "" {
+		return errors.New("unable to write config. Path cannot be empty.")
+	}
+
+	data, err := c.Encode()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(configPath, data)
+}
+
+// Encode returns the TOML representation of the config, as it is written
+// to the config file by WriteToFile(), the comments of the config file that
+// was loaded are kept, the profiles are written in alphabetical order
+func (c Config) Encode() ([]byte, error) {
+	version := c.Version
+	if version == 0 {
+		version = ConfigVersion
+	}
+
+	// the version and the global settings are encoded at the top-level
+	// of the file, before the profiles
+	content := map[string]interface{}{"version": version}
+	if c.DefaultProfile != "" {
+		content["default_profile"] = c.DefaultProfile
+	}
+	if c.Updates != nil {
+		content["updates"] = *c.Updates
+	}
+	for name, profile := range c.Profiles {
+		content[name] = profile
+	}
+
+	buf := new(bytes.Buffer)
+	if err := toml.NewEncoder(buf).Encode(content); err != nil {
+		return nil, errors.Wrap(err, "unable to encode profiles")
+	}
+
+	if c.comments != nil {
+		return c.comments.annotate(buf.Bytes()), nil
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFileAtomic writes the provided data into a temporary file in the same
+// directory of the file path and renames it, so that the file is never left
+// partially written if the process dies in the middle of the write
+func writeFileAtomic(filePath string, data []byte) error {
+	var mode os.FileMode = 0600
+	if info, err := os.Stat(filePath); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return errors.Wrap(err, "unable to create config directory")
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "unable to create temporary config file")
+	}
+	// the file is removed only if we fail to rename it
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "unable to write temporary config file")
+	}
+	if err := tmpFile.Chmod(mode); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "unable to set config file permissions")
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "unable to write temporary config file")
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrap(err, "unable to write temporary config file")
+	}
+
+	return os.Rename(tmpFile.Name(), filePath)
+}
@@ -0,0 +1,340 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+func TestLoadFromFile(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, `[default]
+account = 'test.account'
+api_key = 'INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00'
+api_secret = '_00000000000000000000000000000000'
+
+[dev]
+account = 'dev.example'
+subaccount = 'tenant-a'
+api_key = 'DEVDEV_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000'
+api_secret = '_11111111111111111111111111111111'
+timeout = 120
+`)
+	defer cleanup()
+
+	config, err := lwconfig.LoadFromFile(configPath)
+	if assert.Nil(t, err) {
+		assert.Len(t, config.Profiles, 2)
+		assert.Equal(t, lwconfig.ProfileDetails{
+			Account:    "dev.example",
+			Subaccount: "tenant-a",
+			ApiKey:     "DEVDEV_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000",
+			ApiSecret:  "_11111111111111111111111111111111",
+			Timeout:    120,
+		}, config.Profiles["dev"])
+		assert.Empty(t, config.Profiles["default"].Subaccount)
+	}
+}
+
+func TestLoadFromFileErrors(t *testing.T) {
+	_, err := lwconfig.LoadFromFile("")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "unable to load config. Path cannot be empty.", err.Error())
+	}
+
+	_, err = lwconfig.LoadFromFile("/file/does/not/exist.toml")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unable to decode profiles from config")
+	}
+
+	configPath, cleanup := createTOMLConfig(t, "version = -1\n")
+	defer cleanup()
+	_, err = lwconfig.LoadFromFile(configPath)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "invalid config version -1", err.Error())
+	}
+
+	newerPath, cleanupNewer := createTOMLConfig(t, "version = 99\n")
+	defer cleanupNewer()
+	_, err = lwconfig.LoadFromFile(newerPath)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "config version 99 is newer than the supported version")
+	}
+}
+
+func TestLoadFromFileProfileNamedAsSetting(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, `version = 1
+
+[account]
+account = 'test.account'
+api_key = 'KEY'
+api_secret = 'SECRET'
+`)
+	defer cleanup()
+
+	config, err := lwconfig.LoadFromFile(configPath)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"account"}, config.ProfileNames())
+		assert.Equal(t, "test.account", config.Profiles["account"].Account)
+	}
+}
+
+func TestConfigWriteToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lwconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		configPath = filepath.Join(dir, ".lacework.toml")
+		config     = lwconfig.Config{
+			Version: lwconfig.ConfigVersion,
+			Profiles: lwconfig.Profiles{
+				"default": lwconfig.ProfileDetails{
+					Account:   "test.account",
+					ApiKey:    "KEY",
+					ApiSecret: "SECRET",
+				},
+			},
+		}
+	)
+
+	if assert.Nil(t, config.WriteToFile(configPath)) {
+		info, err := os.Stat(configPath)
+		if assert.Nil(t, err) {
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		}
+
+		content, err := ioutil.ReadFile(configPath)
+		if assert.Nil(t, err) {
+			assert.Equal(t, `version = 1
+
+[default]
+  account = "test.account"
+  api_key = "KEY"
+  api_secret = "SECRET"
+`, string(content))
+		}
+
+		loaded, err := lwconfig.LoadFromFile(configPath)
+		if assert.Nil(t, err) {
+			assert.Equal(t, config, loaded)
+			assert.False(t, loaded.Migrated())
+		}
+
+		// no temporary files should be left behind
+		files, err := ioutil.ReadDir(dir)
+		if assert.Nil(t, err) {
+			assert.Len(t, files, 1)
+		}
+	}
+
+	// the parent directory is created if missing
+	nestedPath := filepath.Join(dir, "nested", "config.toml")
+	if assert.Nil(t, config.WriteToFile(nestedPath)) {
+		info, err := os.Stat(filepath.Dir(nestedPath))
+		if assert.Nil(t, err) {
+			assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+		}
+	}
+
+	assert.EqualError(t, config.WriteToFile(""),
+		"unable to write config. Path cannot be empty.")
+}
+
+func TestConfigWriteToFileWithDefaultProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lwconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		configPath = filepath.Join(dir, ".lacework.toml")
+		config     = lwconfig.Config{
+			Version:        lwconfig.ConfigVersion,
+			DefaultProfile: "dev",
+			Profiles: lwconfig.Profiles{
+				"dev": lwconfig.ProfileDetails{
+					Account:   "dev.account",
+					ApiKey:    "KEY",
+					ApiSecret: "SECRET",
+				},
+			},
+		}
+	)
+
+	if assert.Nil(t, config.WriteToFile(configPath)) {
+		content, err := ioutil.ReadFile(configPath)
+		if assert.Nil(t, err) {
+			assert.Equal(t, `default_profile = "dev"
+version = 1
+
+[dev]
+  account = "dev.account"
+  api_key = "KEY"
+  api_secret = "SECRET"
+`, string(content))
+		}
+
+		loaded, err := lwconfig.LoadFromFile(configPath)
+		if assert.Nil(t, err) {
+			assert.Equal(t, config, loaded)
+			assert.Nil(t, loaded.Validate(), "the stored default profile should be validated")
+		}
+	}
+}
+
+func TestConfigEncode(t *testing.T) {
+	config := lwconfig.Config{
+		Profiles: lwconfig.Profiles{
+			"dev": lwconfig.ProfileDetails{
+				Account:   "dev.account",
+				ApiKey:    "KEY",
+				ApiSecret: "SECRET",
+			},
+		},
+	}
+
+	data, err := config.Encode()
+	if assert.Nil(t, err) {
+		assert.Equal(t, `version = 1
+
+[dev]
+  account = "dev.account"
+  api_key = "KEY"
+  api_secret = "SECRET"
+`, string(data), "the version should default to the current config version")
+	}
+}
+
+func TestConfigWriteToFilePreservesPermissions(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, "")
+	defer cleanup()
+
+	if err := os.Chmod(configPath, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	config := lwconfig.Config{}
+	config.SetProfile("default", lwconfig.ProfileDetails{Account: "test.account"})
+	if assert.Nil(t, config.WriteToFile(configPath)) {
+		info, err := os.Stat(configPath)
+		if assert.Nil(t, err) {
+			assert.Equal(t, os.FileMode(0640), info.Mode().Perm(),
+				"the permissions of the existing file should be preserved")
+		}
+	}
+}
+
+func TestDefaultConfigPath(t *testing.T) {
+	configPath, err := lwconfig.DefaultConfigPath()
+	if assert.Nil(t, err) {
+		assert.Equal(t, ".lacework.toml", filepath.Base(configPath))
+	}
+
+	os.Setenv("LW_CONFIG", "/etc/lacework/config.toml")
+	defer os.Unsetenv("LW_CONFIG")
+	configPath, err = lwconfig.DefaultConfigPath()
+	if assert.Nil(t, err) {
+		assert.Equal(t, "/etc/lacework/config.toml", configPath)
+	}
+}
+
+func TestProfileDetailsVerify(t *testing.T) {
+	profile := lwconfig.ProfileDetails{}
+	assert.EqualError(t, profile.Verify(), "account missing")
+
+	profile.Account = "account"
+	assert.EqualError(t, profile.Verify(), "api_key missing")
+
+	profile.ApiKey = "KEY"
+	assert.EqualError(t, profile.Verify(), "api_secret missing")
+
+	profile.ApiSecret = "SECRET"
+	assert.Nil(t, profile.Verify())
+}
+
+func TestProfileDetailsVerifyStrict(t *testing.T) {
+	profile := lwconfig.ProfileDetails{}
+	assert.EqualError(t, profile.VerifyStrict(), "account missing")
+
+	profile.Account = "account"
+	profile.ApiKey = "KEY"
+	profile.ApiSecret = "SECRET"
+	assert.EqualError(t, profile.VerifyStrict(), "api_key must have at least 55 characters")
+
+	profile.ApiKey = "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00"
+	assert.EqualError(t, profile.VerifyStrict(), "api_secret must have at least 30 characters")
+
+	profile.ApiSecret = "_00000000000000000000000000000000"
+	assert.Nil(t, profile.VerifyStrict())
+}
+
+func createTOMLConfig(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "lwconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(dir, ".lacework.toml")
+	if err := ioutil.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return configPath, func() { os.RemoveAll(dir) }
+}
+
+func TestConfigUpdates(t *testing.T) {
+	config := lwconfig.Config{}
+	assert.True(t, config.UpdatesEnabled(), "updates should be enabled by default")
+
+	config.SetUpdates(false)
+	assert.False(t, config.UpdatesEnabled())
+
+	configPath, cleanup := createTOMLConfig(t, `version = 1
+updates = false
+
+[default]
+account = 'test.account'
+api_key = 'KEY'
+api_secret = 'SECRET'
+`)
+	defer cleanup()
+
+	loaded, err := lwconfig.LoadFromFile(configPath)
+	if assert.Nil(t, err) && assert.NotNil(t, loaded.Updates) {
+		assert.False(t, loaded.UpdatesEnabled())
+	}
+
+	loaded.SetUpdates(true)
+	if assert.Nil(t, loaded.WriteToFile(configPath)) {
+		content, err := ioutil.ReadFile(configPath)
+		if assert.Nil(t, err) {
+			assert.Contains(t, string(content), "updates = true\n")
+		}
+	}
+}
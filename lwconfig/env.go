@@ -0,0 +1,82 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig
+
+import (
+	"os"
+)
+
+// DefaultProfile is the name of the profile used when none is selected
+const DefaultProfile = "default"
+
+// LoadWithEnv loads the configuration from the provided file path and
+// overlays the environment variables LW_ACCOUNT, LW_SUBACCOUNT, LW_API_KEY,
+// LW_API_SECRET, LW_API_URL, LW_DOMAIN and LW_CA_CERT, when set, onto the
+// selected profile. The order of precedence is environment variables over
+// the configuration file.
+//
+// If the profile is empty, it is selected from the environment variables
+// LW_PROFILE or LACEWORK_PROFILE, the default profile stored in the config,
+// or "default". If the configuration file does not exist, the profile is
+// loaded only from the environment variables, which is useful for containers
+// where the configuration comes from the environment.
+func LoadWithEnv(configPath, profile string) (Config, error) {
+	config := Config{Profiles: Profiles{}}
+	if configPath != "" {
+		if _, err := os.Stat(configPath); err == nil {
+			config, err = LoadFromFile(configPath)
+			if err != nil {
+				return config, err
+			}
+		}
+	}
+
+	profile = config.SelectProfile(profile)
+
+	details := config.Profiles[profile]
+	if v := os.Getenv("LW_ACCOUNT"); v != "" {
+		details.Account = v
+	}
+	if v := os.Getenv("LW_SUBACCOUNT"); v != "" {
+		details.Subaccount = v
+	}
+	if v := os.Getenv("LW_API_KEY"); v != "" {
+		details.ApiKey = v
+	}
+	if v := os.Getenv("LW_API_SECRET"); v != "" {
+		details.ApiSecret = v
+	}
+	if v := os.Getenv("LW_API_URL"); v != "" {
+		details.ApiURL = v
+	}
+	if v := os.Getenv("LW_DOMAIN"); v != "" {
+		details.Domain = v
+	}
+	if v := os.Getenv("LW_CA_CERT"); v != "" {
+		details.CACert = v
+	}
+
+	if details != (ProfileDetails{}) {
+		if err := config.SetProfile(profile, details); err != nil {
+			return config, err
+		}
+	}
+
+	return config, nil
+}
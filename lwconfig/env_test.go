@@ -0,0 +1,133 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+func TestLoadWithEnv(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, `[default]
+account = 'test.account'
+api_key = 'INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00'
+api_secret = '_00000000000000000000000000000000'
+
+[dev]
+account = 'dev.example'
+api_key = 'DEVDEV_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000'
+api_secret = '_11111111111111111111111111111111'
+`)
+	defer cleanup()
+
+	t.Run("without env variables", func(t *testing.T) {
+		config, err := lwconfig.LoadWithEnv(configPath, "")
+		if assert.Nil(t, err) {
+			assert.Equal(t, "test.account", config.Profiles["default"].Account)
+		}
+	})
+
+	t.Run("env variables override the default profile", func(t *testing.T) {
+		setEnv(t, "LW_ACCOUNT", "env.account")
+		setEnv(t, "LW_SUBACCOUNT", "env.tenant")
+		defer os.Unsetenv("LW_ACCOUNT")
+		defer os.Unsetenv("LW_SUBACCOUNT")
+
+		config, err := lwconfig.LoadWithEnv(configPath, "")
+		if assert.Nil(t, err) {
+			assert.Equal(t, lwconfig.ProfileDetails{
+				Account:    "env.account",
+				Subaccount: "env.tenant",
+				ApiKey:     "INTTEST_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC00",
+				ApiSecret:  "_00000000000000000000000000000000",
+			}, config.Profiles["default"])
+			assert.Equal(t, "dev.example", config.Profiles["dev"].Account,
+				"profiles not selected should not be modified")
+		}
+	})
+
+	t.Run("profile selected via LW_PROFILE", func(t *testing.T) {
+		setEnv(t, "LW_PROFILE", "dev")
+		setEnv(t, "LW_API_SECRET", "_env")
+		defer os.Unsetenv("LW_PROFILE")
+		defer os.Unsetenv("LW_API_SECRET")
+
+		config, err := lwconfig.LoadWithEnv(configPath, "")
+		if assert.Nil(t, err) {
+			assert.Equal(t, "_env", config.Profiles["dev"].ApiSecret)
+			assert.Equal(t, "_00000000000000000000000000000000",
+				config.Profiles["default"].ApiSecret)
+		}
+	})
+}
+
+func TestLoadWithEnvWithDefaultProfile(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, `version = 1
+default_profile = 'dev'
+
+[dev]
+account = 'dev.example'
+api_key = 'DEVDEV_ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890AAABBBCCC000'
+api_secret = '_11111111111111111111111111111111'
+`)
+	defer cleanup()
+
+	setEnv(t, "LW_ACCOUNT", "env.account")
+	defer os.Unsetenv("LW_ACCOUNT")
+
+	config, err := lwconfig.LoadWithEnv(configPath, "")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "dev", config.DefaultProfile)
+		assert.Equal(t, "env.account", config.Profiles["dev"].Account,
+			"the stored default profile should be selected")
+		assert.Equal(t, []string{"dev"}, config.ProfileNames())
+	}
+}
+
+func TestLoadWithEnvWithoutConfigFile(t *testing.T) {
+	setEnv(t, "LW_ACCOUNT", "env.account")
+	setEnv(t, "LW_API_KEY", "KEY")
+	setEnv(t, "LW_API_SECRET", "SECRET")
+	setEnv(t, "LW_API_URL", "https://api.example.com")
+	defer os.Unsetenv("LW_ACCOUNT")
+	defer os.Unsetenv("LW_API_KEY")
+	defer os.Unsetenv("LW_API_SECRET")
+	defer os.Unsetenv("LW_API_URL")
+
+	config, err := lwconfig.LoadWithEnv("/file/does/not/exist.toml", "container")
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"container"}, config.ProfileNames())
+		assert.Equal(t, lwconfig.ProfileDetails{
+			Account:   "env.account",
+			ApiKey:    "KEY",
+			ApiSecret: "SECRET",
+			ApiURL:    "https://api.example.com",
+		}, config.Profiles["container"])
+	}
+}
+
+func setEnv(t *testing.T, key, value string) {
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,133 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig
+
+import (
+	"github.com/pkg/errors"
+)
+
+// MergeAction is the action to take when a profile being merged already
+// exists in the config
+type MergeAction int
+
+const (
+	// MergeKeep keeps the existing profile and skips the new one
+	MergeKeep MergeAction = iota
+
+	// MergeOverwrite replaces the existing profile with the new one
+	MergeOverwrite
+
+	// MergeRename adds the new profile under a different name
+	MergeRename
+)
+
+// MergeStrategy decides what to do when the provided profile name exists
+// in both configs, when the action is MergeRename, it must also return
+// the new name of the profile
+type MergeStrategy func(name string) (MergeAction, string)
+
+// MergeKeepExisting is a MergeStrategy that always keeps the existing profiles
+func MergeKeepExisting(_ string) (MergeAction, string) {
+	return MergeKeep, ""
+}
+
+// MergeOverwriteExisting is a MergeStrategy that always overwrites the existing profiles
+func MergeOverwriteExisting(_ string) (MergeAction, string) {
+	return MergeOverwrite, ""
+}
+
+// MergeResult reports what happened to each of the merged profiles
+type MergeResult struct {
+	// Added are the profiles that did not exist in the config
+	Added []string `json:"added"`
+
+	// Overwritten are the existing profiles replaced by the merged ones
+	Overwritten []string `json:"overwritten"`
+
+	// Renamed maps the merged profiles to the names they were added as
+	Renamed map[string]string `json:"renamed"`
+
+	// Skipped are the merged profiles that were not added, either because
+	// the existing profile was kept or because both profiles are identical
+	Skipped []string `json:"skipped"`
+}
+
+// Merge adds the profiles from the other config into this config, when a
+// profile exists in both configs with different settings, the provided
+// strategy decides what to do, identical profiles are skipped
+//
+// The global settings of the other config, like the default profile, are
+// not merged
+func (c *Config) Merge(other Config, strategy MergeStrategy) (MergeResult, error) {
+	result := MergeResult{Renamed: map[string]string{}}
+	if strategy == nil {
+		strategy = MergeKeepExisting
+	}
+
+	for _, name := range other.ProfileNames() {
+		profile := other.Profiles[name]
+
+		current, exists := c.Profiles[name]
+		if !exists {
+			if err := c.SetProfile(name, profile); err != nil {
+				return result, err
+			}
+			result.Added = append(result.Added, name)
+			continue
+		}
+
+		if current == profile {
+			result.Skipped = append(result.Skipped, name)
+			continue
+		}
+
+		action, newName := strategy(name)
+		switch action {
+		case MergeKeep:
+			result.Skipped = append(result.Skipped, name)
+		case MergeOverwrite:
+			if err := c.SetProfile(name, profile); err != nil {
+				return result, err
+			}
+			result.Overwritten = append(result.Overwritten, name)
+		case MergeRename:
+			if newName == "" {
+				return result, errors.Errorf("unable to rename profile '%s', new name is empty", name)
+			}
+			if _, exists := c.Profiles[newName]; exists {
+				return result, errors.Errorf(
+					"unable to rename profile '%s', profile '%s' already exists", name, newName,
+				)
+			}
+			if _, exists := other.Profiles[newName]; exists {
+				return result, errors.Errorf(
+					"unable to rename profile '%s', profile '%s' is also being merged", name, newName,
+				)
+			}
+			if err := c.SetProfile(newName, profile); err != nil {
+				return result, err
+			}
+			result.Renamed[name] = newName
+		default:
+			return result, errors.Errorf("unknown merge action %d for profile '%s'", action, name)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,125 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+func mockMergeConfigs() (lwconfig.Config, lwconfig.Config) {
+	mine := lwconfig.Config{Profiles: lwconfig.Profiles{
+		"default": lwconfig.ProfileDetails{Account: "example"},
+		"dev":     lwconfig.ProfileDetails{Account: "dev"},
+	}}
+	other := lwconfig.Config{Profiles: lwconfig.Profiles{
+		"default": lwconfig.ProfileDetails{Account: "example"},
+		"dev":     lwconfig.ProfileDetails{Account: "dev.other"},
+		"prod":    lwconfig.ProfileDetails{Account: "prod"},
+	}}
+	return mine, other
+}
+
+func TestConfigMergeKeepExisting(t *testing.T) {
+	mine, other := mockMergeConfigs()
+
+	result, err := mine.Merge(other, lwconfig.MergeKeepExisting)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"prod"}, result.Added)
+		assert.Equal(t, []string{"default", "dev"}, result.Skipped)
+		assert.Empty(t, result.Overwritten)
+		assert.Empty(t, result.Renamed)
+	}
+	assert.Equal(t, []string{"default", "dev", "prod"}, mine.ProfileNames())
+	assert.Equal(t, "dev", mine.Profiles["dev"].Account)
+}
+
+func TestConfigMergeOverwriteExisting(t *testing.T) {
+	mine, other := mockMergeConfigs()
+
+	result, err := mine.Merge(other, lwconfig.MergeOverwriteExisting)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"prod"}, result.Added)
+		assert.Equal(t, []string{"dev"}, result.Overwritten)
+		// identical profiles are skipped without asking
+		assert.Equal(t, []string{"default"}, result.Skipped)
+	}
+	assert.Equal(t, "dev.other", mine.Profiles["dev"].Account)
+}
+
+func TestConfigMergeRename(t *testing.T) {
+	mine, other := mockMergeConfigs()
+
+	asked := []string{}
+	result, err := mine.Merge(other, func(name string) (lwconfig.MergeAction, string) {
+		asked = append(asked, name)
+		return lwconfig.MergeRename, name + "-other"
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"dev"}, asked)
+		assert.Equal(t, map[string]string{"dev": "dev-other"}, result.Renamed)
+	}
+	assert.Equal(t, []string{"default", "dev", "dev-other", "prod"}, mine.ProfileNames())
+	assert.Equal(t, "dev", mine.Profiles["dev"].Account)
+	assert.Equal(t, "dev.other", mine.Profiles["dev-other"].Account)
+}
+
+func TestConfigMergeRenameErrors(t *testing.T) {
+	mine, other := mockMergeConfigs()
+	_, err := mine.Merge(other, func(_ string) (lwconfig.MergeAction, string) {
+		return lwconfig.MergeRename, ""
+	})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "unable to rename profile 'dev', new name is empty", err.Error())
+	}
+
+	mine, other = mockMergeConfigs()
+	_, err = mine.Merge(other, func(_ string) (lwconfig.MergeAction, string) {
+		return lwconfig.MergeRename, "default"
+	})
+	if assert.NotNil(t, err) {
+		assert.Equal(t,
+			"unable to rename profile 'dev', profile 'default' already exists", err.Error())
+	}
+
+	mine, other = mockMergeConfigs()
+	_, err = mine.Merge(other, func(_ string) (lwconfig.MergeAction, string) {
+		return lwconfig.MergeRename, "prod"
+	})
+	if assert.NotNil(t, err) {
+		assert.Equal(t,
+			"unable to rename profile 'dev', profile 'prod' is also being merged", err.Error())
+	}
+}
+
+func TestConfigMergeNilStrategy(t *testing.T) {
+	var (
+		mine     = lwconfig.Config{}
+		_, other = mockMergeConfigs()
+	)
+
+	result, err := mine.Merge(other, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"default", "dev", "prod"}, result.Added)
+	}
+	assert.Equal(t, []string{"default", "dev", "prod"}, mine.ProfileNames())
+}
@@ -0,0 +1,61 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig
+
+// ConfigVersion is the current version of the configuration file layout
+const ConfigVersion = 1
+
+// configMigrations are the steps to migrate a config to the current version,
+// the migration at index N upgrades a config from version N to version N+1,
+// when adding a new migration, make sure to bump the ConfigVersion
+var configMigrations = []func(*Config){
+	migrateV0ToV1,
+}
+
+// Migrate upgrades the config from an older layout to the current version,
+// it returns true if the config was modified
+func (c *Config) Migrate() bool {
+	if c.Version < 0 || c.Version >= ConfigVersion {
+		return false
+	}
+
+	for c.Version < ConfigVersion {
+		configMigrations[c.Version](c)
+		c.Version++
+	}
+
+	c.migrated = true
+	return true
+}
+
+// migrateV0ToV1 moves a profile defined at the top-level of the config file
+// into the default profile, unless the default profile is already defined,
+// in which case the top-level settings were never used and are dropped
+func migrateV0ToV1(c *Config) {
+	if c.Profiles == nil {
+		c.Profiles = Profiles{}
+	}
+
+	if c.legacyProfile != nil {
+		if _, exist := c.Profiles[DefaultProfile]; !exist {
+			c.Profiles[DefaultProfile] = *c.legacyProfile
+		}
+		c.legacyProfile = nil
+	}
+}
@@ -0,0 +1,104 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+func TestMigrateCurrentVersion(t *testing.T) {
+	config := lwconfig.Config{Version: lwconfig.ConfigVersion}
+	assert.False(t, config.Migrate(), "current config should not be migrated")
+	assert.False(t, config.Migrated())
+
+	config = lwconfig.Config{Version: -1}
+	assert.False(t, config.Migrate(), "an invalid version should not be migrated")
+}
+
+func TestMigrateV0ToV1ProfileTables(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, `[default]
+account = 'test.account'
+api_key = 'KEY'
+api_secret = 'SECRET'
+`)
+	defer cleanup()
+
+	config, err := lwconfig.LoadFromFile(configPath)
+	if assert.Nil(t, err) {
+		assert.True(t, config.Migrated())
+		assert.Equal(t, 1, config.Version)
+		assert.Equal(t, []string{"default"}, config.ProfileNames())
+	}
+}
+
+func TestMigrateV0ToV1TopLevelProfile(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, `account = 'test.account'
+api_key = 'KEY'
+api_secret = 'SECRET'
+
+[dev]
+account = 'dev.example'
+api_key = 'DEVKEY'
+api_secret = 'DEVSECRET'
+`)
+	defer cleanup()
+
+	config, err := lwconfig.LoadFromFile(configPath)
+	if assert.Nil(t, err) {
+		assert.True(t, config.Migrated())
+		assert.Equal(t, 1, config.Version)
+		assert.Equal(t, []string{"default", "dev"}, config.ProfileNames())
+		assert.Equal(t, lwconfig.ProfileDetails{
+			Account:   "test.account",
+			ApiKey:    "KEY",
+			ApiSecret: "SECRET",
+		}, config.Profiles["default"])
+
+		// write the migrated config back and load it again
+		if assert.Nil(t, config.WriteToFile(configPath)) {
+			migrated, err := lwconfig.LoadFromFile(configPath)
+			if assert.Nil(t, err) {
+				assert.False(t, migrated.Migrated())
+				assert.Equal(t, config.Profiles, migrated.Profiles)
+			}
+		}
+	}
+}
+
+func TestMigrateV0ToV1TopLevelProfileWithDefault(t *testing.T) {
+	configPath, cleanup := createTOMLConfig(t, `account = 'unused'
+
+[default]
+account = 'test.account'
+api_key = 'KEY'
+api_secret = 'SECRET'
+`)
+	defer cleanup()
+
+	config, err := lwconfig.LoadFromFile(configPath)
+	if assert.Nil(t, err) {
+		assert.True(t, config.Migrated())
+		assert.Equal(t, "test.account", config.Profiles["default"].Account,
+			"the default profile should take precedence over top-level settings")
+	}
+}
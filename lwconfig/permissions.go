@@ -0,0 +1,77 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// InsecurePermissionsError is returned when the config file is accessible
+// by other users, since it contains API secrets, it should only be
+// readable and writable by its owner (0600)
+type InsecurePermissionsError struct {
+	Path string
+	Mode os.FileMode
+}
+
+// Error fulfills the built-in error interface function
+func (e *InsecurePermissionsError) Error() string {
+	return fmt.Sprintf("config file %s is accessible by other users (%04o), it should have 0600 permissions",
+		e.Path, e.Mode.Perm())
+}
+
+// IsInsecurePermissions returns true if the provided error, or its cause,
+// is an InsecurePermissionsError
+func IsInsecurePermissions(err error) bool {
+	_, ok := errors.Cause(err).(*InsecurePermissionsError)
+	return ok
+}
+
+// CheckPermissions checks that the provided config file is not accessible by
+// other users, if it is, it returns an InsecurePermissionsError
+//
+// File permissions are not checked on Windows
+func CheckPermissions(configPath string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return err
+	}
+
+	if info.Mode().Perm()&0077 != 0 {
+		return &InsecurePermissionsError{Path: configPath, Mode: info.Mode()}
+	}
+	return nil
+}
+
+// FixPermissions makes the provided config file only readable and writable
+// by its owner (0600)
+func FixPermissions(configPath string) error {
+	if err := os.Chmod(configPath, 0600); err != nil {
+		return errors.Wrap(err, "unable to fix config file permissions")
+	}
+	return nil
+}
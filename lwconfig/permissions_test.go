@@ -0,0 +1,81 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig_test
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not checked on Windows")
+	}
+
+	configPath, cleanup := createTOMLConfig(t, `[default]
+account = 'test.account'
+api_key = 'KEY'
+api_secret = 'SECRET'
+`)
+	defer cleanup()
+
+	assert.Nil(t, lwconfig.CheckPermissions(configPath))
+	config, err := lwconfig.LoadFromFile(configPath)
+	if assert.Nil(t, err) {
+		assert.Empty(t, config.Warnings())
+	}
+
+	if err := os.Chmod(configPath, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = lwconfig.CheckPermissions(configPath)
+	if assert.NotNil(t, err) {
+		assert.True(t, lwconfig.IsInsecurePermissions(err))
+		assert.True(t, lwconfig.IsInsecurePermissions(errors.Wrap(err, "wrapped")))
+		assert.Equal(t,
+			"config file "+configPath+" is accessible by other users (0644), it should have 0600 permissions",
+			err.Error())
+	}
+	assert.False(t, lwconfig.IsInsecurePermissions(errors.New("another error")))
+
+	config, err = lwconfig.LoadFromFile(configPath)
+	if assert.Nil(t, err, "insecure permissions should not prevent loading the config") {
+		assert.Equal(t, "test.account", config.Profiles["default"].Account)
+		if assert.Len(t, config.Warnings(), 1) {
+			assert.True(t, lwconfig.IsInsecurePermissions(config.Warnings()[0]))
+		}
+	}
+
+	if assert.Nil(t, lwconfig.FixPermissions(configPath)) {
+		assert.Nil(t, lwconfig.CheckPermissions(configPath))
+		info, err := os.Stat(configPath)
+		if assert.Nil(t, err) {
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		}
+	}
+
+	assert.True(t, os.IsNotExist(lwconfig.CheckPermissions(configPath+".missing")))
+}
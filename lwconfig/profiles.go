@@ -0,0 +1,186 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ProfileNotFoundError is returned when a profile does not exist in the config
+type ProfileNotFoundError struct {
+	Name string
+}
+
+// Error fulfills the built-in error interface function
+func (e *ProfileNotFoundError) Error() string {
+	return fmt.Sprintf("profile '%s' not found", e.Name)
+}
+
+// IsProfileNotFound returns true if the provided error, or its cause,
+// is a ProfileNotFoundError
+func IsProfileNotFound(err error) bool {
+	_, ok := errors.Cause(err).(*ProfileNotFoundError)
+	return ok
+}
+
+// ReservedProfileNames are the global settings of the config file, a profile
+// with one of these names would be loaded as a setting instead of a profile
+var ReservedProfileNames = []string{"version", "default_profile", "updates"}
+
+// IsReservedProfileName returns true if the provided name is reserved
+func IsReservedProfileName(name string) bool {
+	for _, reserved := range ReservedProfileNames {
+		if name == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateProfileName returns an error if the provided name cannot be used
+// as the name of a profile, because it is empty or reserved
+func ValidateProfileName(name string) error {
+	if name == "" {
+		return errors.New("the profile name cannot be empty")
+	}
+	if IsReservedProfileName(name) {
+		return errors.Errorf("the profile name '%s' is reserved, use a different name", name)
+	}
+	return nil
+}
+
+// GetProfile returns the details of the provided profile, if the profile
+// does not exist, it returns a ProfileNotFoundError
+func (c Config) GetProfile(name string) (ProfileDetails, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return ProfileDetails{}, &ProfileNotFoundError{name}
+	}
+	return profile, nil
+}
+
+// SetProfile creates or updates the provided profile, if the name of the
+// profile is not valid, it returns an error
+func (c *Config) SetProfile(name string, profile ProfileDetails) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if c.Profiles == nil {
+		c.Profiles = Profiles{}
+	}
+	c.Profiles[name] = profile
+	return nil
+}
+
+// DeleteProfile removes the provided profile, if the profile
+// does not exist, it returns a ProfileNotFoundError
+//
+// If the profile is the stored default profile, the default is cleared
+func (c *Config) DeleteProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return &ProfileNotFoundError{name}
+	}
+	delete(c.Profiles, name)
+	if c.DefaultProfile == name {
+		c.DefaultProfile = ""
+	}
+	return nil
+}
+
+// RenameProfile renames the provided profile, overwriting the new profile if
+// it already exists, the comments of the profile and the stored default
+// profile follow the renamed profile, if the profile does not exist, it
+// returns a ProfileNotFoundError
+func (c *Config) RenameProfile(oldName, newName string) error {
+	profile, ok := c.Profiles[oldName]
+	if !ok {
+		return &ProfileNotFoundError{oldName}
+	}
+	if oldName == newName {
+		return nil
+	}
+	if err := ValidateProfileName(newName); err != nil {
+		return err
+	}
+
+	delete(c.Profiles, oldName)
+	c.Profiles[newName] = profile
+	c.comments.rename(oldName, newName)
+	if c.DefaultProfile == oldName {
+		c.DefaultProfile = newName
+	}
+	return nil
+}
+
+// SetDefaultProfile stores the provided profile as the one to use when no
+// profile is selected, if the profile does not exist, it returns a
+// ProfileNotFoundError
+func (c *Config) SetDefaultProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return &ProfileNotFoundError{name}
+	}
+	c.DefaultProfile = name
+	return nil
+}
+
+// ProfileEnvVariables are the environment variables that select the profile
+// to use, in order of precedence, LACEWORK_PROFILE is an alias of LW_PROFILE
+var ProfileEnvVariables = []string{"LW_PROFILE", "LACEWORK_PROFILE"}
+
+// EnvProfile returns the profile selected via environment variables and the
+// name of the variable that selected it, or empty strings if none is set
+func EnvProfile() (string, string) {
+	for _, variable := range ProfileEnvVariables {
+		if profile := os.Getenv(variable); profile != "" {
+			return profile, variable
+		}
+	}
+	return "", ""
+}
+
+// SelectProfile returns the name of the profile to use, in order of
+// precedence, the provided profile (usually from a flag), the environment
+// variables LW_PROFILE or LACEWORK_PROFILE, the stored default profile,
+// or "default"
+func (c Config) SelectProfile(profile string) string {
+	if profile != "" {
+		return profile
+	}
+	if profile, _ = EnvProfile(); profile != "" {
+		return profile
+	}
+	if c.DefaultProfile != "" {
+		return c.DefaultProfile
+	}
+	return DefaultProfile
+}
+
+// ProfileNames returns the names of all the profiles sorted alphabetically
+func (c Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
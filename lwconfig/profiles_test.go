@@ -0,0 +1,159 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+func TestConfigGetProfile(t *testing.T) {
+	config := lwconfig.Config{Profiles: lwconfig.Profiles{
+		"default": lwconfig.ProfileDetails{Account: "example"},
+	}}
+
+	profile, err := config.GetProfile("default")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "example", profile.Account)
+	}
+
+	_, err = config.GetProfile("foo")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "profile 'foo' not found", err.Error())
+		assert.True(t, lwconfig.IsProfileNotFound(err))
+		assert.True(t, lwconfig.IsProfileNotFound(errors.Wrap(err, "wrapped")))
+	}
+	assert.False(t, lwconfig.IsProfileNotFound(errors.New("another error")))
+}
+
+func TestConfigSetProfile(t *testing.T) {
+	// a zero value config has a nil map, this should not panic
+	config := lwconfig.Config{}
+	assert.Nil(t, config.SetProfile("dev", lwconfig.ProfileDetails{Account: "dev"}))
+	assert.Nil(t, config.SetProfile("dev", lwconfig.ProfileDetails{Account: "dev.example"}))
+
+	profile, err := config.GetProfile("dev")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "dev.example", profile.Account)
+	}
+
+	for _, name := range append([]string{""}, lwconfig.ReservedProfileNames...) {
+		assert.NotNil(t, config.SetProfile(name, lwconfig.ProfileDetails{Account: "dev"}),
+			"the profile name '%s' should not be valid", name)
+	}
+	assert.Equal(t, []string{"dev"}, config.ProfileNames())
+}
+
+func TestConfigDeleteProfile(t *testing.T) {
+	config := lwconfig.Config{}
+	err := config.DeleteProfile("foo")
+	assert.True(t, lwconfig.IsProfileNotFound(err))
+
+	config.SetProfile("foo", lwconfig.ProfileDetails{Account: "foo"})
+	assert.Nil(t, config.DeleteProfile("foo"))
+	assert.Empty(t, config.ProfileNames())
+}
+
+func TestConfigProfileNames(t *testing.T) {
+	config := lwconfig.Config{}
+	assert.Equal(t, []string{}, config.ProfileNames())
+
+	config.SetProfile("prod", lwconfig.ProfileDetails{})
+	config.SetProfile("default", lwconfig.ProfileDetails{})
+	config.SetProfile("dev", lwconfig.ProfileDetails{})
+	assert.Equal(t, []string{"default", "dev", "prod"}, config.ProfileNames())
+}
+
+func TestConfigSetDefaultProfile(t *testing.T) {
+	config := lwconfig.Config{}
+	err := config.SetDefaultProfile("dev")
+	assert.True(t, lwconfig.IsProfileNotFound(err))
+	assert.Empty(t, config.DefaultProfile)
+
+	config.SetProfile("dev", lwconfig.ProfileDetails{Account: "dev"})
+	assert.Nil(t, config.SetDefaultProfile("dev"))
+	assert.Equal(t, "dev", config.DefaultProfile)
+
+	// deleting the default profile clears it
+	assert.Nil(t, config.DeleteProfile("dev"))
+	assert.Empty(t, config.DefaultProfile)
+}
+
+func TestConfigSelectProfile(t *testing.T) {
+	defer os.Setenv("LW_PROFILE", os.Getenv("LW_PROFILE"))
+	os.Unsetenv("LW_PROFILE")
+
+	config := lwconfig.Config{}
+	assert.Equal(t, "default", config.SelectProfile(""))
+
+	config.DefaultProfile = "stored"
+	assert.Equal(t, "stored", config.SelectProfile(""))
+
+	setEnv(t, "LW_PROFILE", "env")
+	assert.Equal(t, "env", config.SelectProfile(""))
+	assert.Equal(t, "flag", config.SelectProfile("flag"))
+}
+
+func TestConfigSelectProfileAlias(t *testing.T) {
+	defer os.Setenv("LW_PROFILE", os.Getenv("LW_PROFILE"))
+	defer os.Setenv("LACEWORK_PROFILE", os.Getenv("LACEWORK_PROFILE"))
+	os.Unsetenv("LW_PROFILE")
+
+	config := lwconfig.Config{DefaultProfile: "stored"}
+	setEnv(t, "LACEWORK_PROFILE", "alias")
+	assert.Equal(t, "alias", config.SelectProfile(""))
+
+	profile, variable := lwconfig.EnvProfile()
+	assert.Equal(t, "alias", profile)
+	assert.Equal(t, "LACEWORK_PROFILE", variable)
+
+	setEnv(t, "LW_PROFILE", "env")
+	assert.Equal(t, "env", config.SelectProfile(""), "LW_PROFILE takes precedence over its alias")
+	assert.Equal(t, "flag", config.SelectProfile("flag"))
+}
+
+func TestConfigRenameProfile(t *testing.T) {
+	config := lwconfig.Config{}
+	err := config.RenameProfile("dev", "staging")
+	assert.True(t, lwconfig.IsProfileNotFound(err))
+
+	config.SetProfile("dev", lwconfig.ProfileDetails{Account: "dev"})
+	config.SetProfile("staging", lwconfig.ProfileDetails{Account: "old"})
+	assert.Nil(t, config.SetDefaultProfile("dev"))
+
+	assert.Nil(t, config.RenameProfile("dev", "staging"))
+	assert.Equal(t, []string{"staging"}, config.ProfileNames())
+	assert.Equal(t, "dev", config.Profiles["staging"].Account)
+	assert.Equal(t, "staging", config.DefaultProfile,
+		"the default profile should follow the renamed profile")
+
+	assert.Nil(t, config.RenameProfile("staging", "staging"))
+	assert.Equal(t, []string{"staging"}, config.ProfileNames())
+
+	err = config.RenameProfile("staging", "version")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "the profile name 'version' is reserved, use a different name", err.Error())
+	}
+	assert.Equal(t, []string{"staging"}, config.ProfileNames())
+}
@@ -0,0 +1,91 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ValidationError is returned by Validate when the config has one or more
+// problems, it lists all of them instead of failing on the first one
+type ValidationError struct {
+	Errors []error
+}
+
+// Error fulfills the built-in error interface function
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return fmt.Sprintf("invalid config: %s", e.Errors[0])
+	}
+
+	problems := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		problems[i] = fmt.Sprintf("  * %s", err)
+	}
+	return fmt.Sprintf("invalid config, %d problems found:\n%s",
+		len(e.Errors), strings.Join(problems, "\n"))
+}
+
+// IsValidationError returns true if the provided error, or its cause,
+// is a ValidationError
+func IsValidationError(err error) bool {
+	_, ok := errors.Cause(err).(*ValidationError)
+	return ok
+}
+
+// Validate checks that the config has at least one profile, that the
+// default profile exists, and that every profile passes Verify(), if
+// there are problems, it returns a ValidationError listing all of them
+//
+// The default profile is the one stored in the config, or "default"
+func (c Config) Validate() error {
+	if c.DefaultProfile != "" {
+		return c.ValidateWithDefault(c.DefaultProfile)
+	}
+	return c.ValidateWithDefault(DefaultProfile)
+}
+
+// ValidateWithDefault is like Validate but checks that the provided profile
+// exists instead of the default one, useful when a different profile is
+// selected via the environment variable LW_PROFILE or the --profile flag
+func (c Config) ValidateWithDefault(defaultProfile string) error {
+	if len(c.Profiles) == 0 {
+		return &ValidationError{[]error{errors.New("no profiles configured")}}
+	}
+
+	problems := []error{}
+	if _, ok := c.Profiles[defaultProfile]; !ok {
+		problems = append(problems, &ProfileNotFoundError{defaultProfile})
+	}
+
+	for _, name := range c.ProfileNames() {
+		profile := c.Profiles[name]
+		if err := profile.Verify(); err != nil {
+			problems = append(problems, errors.Wrapf(err, "profile '%s'", name))
+		}
+	}
+
+	if len(problems) != 0 {
+		return &ValidationError{problems}
+	}
+	return nil
+}
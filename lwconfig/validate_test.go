@@ -0,0 +1,68 @@
+//
+// Author:: Salim Afiune Maya (<afiune@lacework.net>)
+// Copyright:: Copyright 2020, Lacework Inc.
+// License:: Apache License, Version 2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package lwconfig_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lacework/go-sdk/lwconfig"
+)
+
+func TestConfigValidate(t *testing.T) {
+	config := lwconfig.Config{Profiles: lwconfig.Profiles{
+		"default": lwconfig.ProfileDetails{Account: "example", ApiKey: "KEY", ApiSecret: "_secret"},
+		"dev":     lwconfig.ProfileDetails{Account: "dev", ApiKey: "KEY", ApiSecret: "_secret"},
+	}}
+	assert.Nil(t, config.Validate())
+	assert.Nil(t, config.ValidateWithDefault("dev"))
+}
+
+func TestConfigValidateNoProfiles(t *testing.T) {
+	err := lwconfig.Config{}.Validate()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "invalid config: no profiles configured", err.Error())
+		assert.True(t, lwconfig.IsValidationError(err))
+	}
+}
+
+func TestConfigValidateListsEveryProblem(t *testing.T) {
+	config := lwconfig.Config{Profiles: lwconfig.Profiles{
+		"prod": lwconfig.ProfileDetails{Account: "prod", ApiKey: "KEY"},
+		"dev":  lwconfig.ProfileDetails{ApiKey: "KEY", ApiSecret: "_secret"},
+		"test": lwconfig.ProfileDetails{Account: "test", ApiKey: "KEY", ApiSecret: "_secret"},
+	}}
+
+	err := config.Validate()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `invalid config, 3 problems found:
+  * profile 'default' not found
+  * profile 'dev': account missing
+  * profile 'prod': api_secret missing`, err.Error())
+		assert.True(t, lwconfig.IsValidationError(errors.Wrap(err, "wrapped")))
+	}
+
+	err = config.ValidateWithDefault("test")
+	if assert.NotNil(t, err) {
+		assert.Len(t, err.(*lwconfig.ValidationError).Errors, 2)
+	}
+	assert.False(t, lwconfig.IsValidationError(errors.New("another error")))
+}